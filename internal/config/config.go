@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +15,7 @@ type Config struct {
 	// Service Identity
 	ServiceName         string
 	ServiceInstanceName string // Instance identifier (e.g., "market-data-Coinmetrics")
+	InstanceID          string // Unique replica identifier (auto-generated if empty)
 	ServiceVersion      string
 	Environment         string
 
@@ -21,12 +23,18 @@ type Config struct {
 	SchemaName     string // PostgreSQL schema (auto-derived if empty)
 	RedisNamespace string // Redis key prefix (auto-derived if empty)
 
+	// Storage; DatabaseURL's scheme picks the backend and a postgres:// URL overrides PostgresURL
+	DatabaseURL string // e.g. "sqlite:///var/lib/market-data.db" for local storage without PostgreSQL
+
 	// PostgreSQL
 	PostgresURL           string
 	MaxConnections        int
 	MaxIdleConnections    int
 	ConnectionMaxLifetime time.Duration
 	ConnectionMaxIdleTime time.Duration
+	AutoMigrate           bool   // Apply schema migrations on Connect
+	TxIsolationLevel      string // Default for WithTx, e.g. "read_committed" or "serializable"
+	TxMaxRetries          int    // Retries of serialization failures and deadlocks in WithTx
 
 	// Redis
 	RedisURL          string
@@ -43,8 +51,82 @@ type Config struct {
 
 	// Service Discovery
 	ServiceDiscoveryNamespace string
+	ConfigureKeyspaceEvents   bool // Let Watch enable expiry notifications with CONFIG SET; managed Redis often forbids it
 	HeartbeatInterval         time.Duration
 	ServiceTTL                time.Duration
+	AutoRegisterService       bool
+	ServiceAddress            string // Advertised address (defaults to hostname)
+	ServicePort               int
+	ServiceTags               []string
+	ServiceWeight             int
+
+	// Real-time Publication
+	PublishMarketData bool
+
+	// Durable Event Log
+	EventLogEnabled bool
+	EventLogMaxLen  int // Approximate per-stream cap; 0 disables trimming
+
+	// Transactional Outbox
+	OutboxEnabled      bool
+	OutboxSink         string // "stream", "pubsub", "both" or "callback"
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+	OutboxRetention    time.Duration // How long delivered rows are kept
+	OutboxMaxAttempts  int           // Failed deliveries before an event is dead-lettered
+
+	// Change Feed
+	ChangeFeedEnabled bool // LISTEN for trigger notifications; requires migration 3
+
+	// Data Quality
+	GapDetectionEnabled bool // Record sequence gaps per feed source; requires migration 5
+
+	// Freshness Monitoring
+	FreshnessEnabled       bool
+	FreshnessDefaultSLA    time.Duration
+	FreshnessSymbolSLAs    map[string]time.Duration // e.g. "BTC-USD=5s,ETH-USD=10s"
+	FreshnessSourceSLAs    map[string]time.Duration // e.g. "coinbase=2s"
+	FreshnessCheckInterval time.Duration
+	FreshnessExpiry        time.Duration // Stale feeds not updated for this long stop counting, e.g. retired sources
+
+	// Anomaly Detection
+	AnomalyFilterEnabled bool    // Screen incoming price feeds; quarantine requires migration 8
+	AnomalyAction        string  // "flag", "quarantine" or "reject"
+	AnomalyZScore        float64 // Standard deviations from the recent mean; 0 disables the check
+	AnomalyMaxJump       float64 // Fractional move from the last price; 0 disables the check
+	AnomalyWindow        int     // Recent prices kept per symbol and source
+	AnomalyRebaseline    int     // Consecutive consistent anomalous prices that become the new baseline; 0 disables
+
+	// Snapshot Generation
+	SnapshotMode           string        // "off", "schedule" or "tick"
+	SnapshotInterval       time.Duration // Scheduled builds
+	SnapshotSymbols        []string      // Scheduled symbols; empty means every symbol with recent feeds
+	SnapshotWindow         time.Duration // Period covered by volume and price change
+	SnapshotCandleInterval string        // Candles summed over the window
+
+	// Retention
+	RetentionEnabled  bool
+	RetentionInterval time.Duration
+	RetentionPolicies string // e.g. "price_feeds=7d>1m,candles:1m=90d>1h,candles:1h=forever"; see retention.ParsePolicies
+
+	// Partitioning
+	PartitioningEnabled    bool   // Range-partition price_feeds, candles and market_snapshots by time; requires migration 10
+	PartitionIntervals     string // e.g. "price_feeds=day,candles=month"; tables left out are partitioned daily
+	PartitionPremake       int    // Partitions created ahead of the current one
+	PartitionCheckInterval time.Duration
+
+	// TimescaleDB
+	TimescaleEnabled         bool          // Use hypertables and continuous aggregates; falls back to plain PostgreSQL without the extension
+	TimescaleChunkInterval   time.Duration // Time covered by one chunk
+	TimescaleCompressAfter   time.Duration // Age at which chunks are compressed; 0 disables compression
+	TimescaleRetention       string        // e.g. "price_feeds=30d,market_snapshots=90d"; tables left out keep their data
+	TimescaleAggregateSource string        // Stored candle interval the aggregates roll up
+	TimescaleAggregates      []string      // Candle intervals served from continuous aggregates
+	TimescaleRefreshWindow   time.Duration // How far back aggregates are refreshed for late candles
+
+	// Leader Election
+	LeaderLeaseDuration time.Duration
+	LeaderRenewInterval time.Duration
 
 	// Test Environment
 	TestPostgresURL string
@@ -70,15 +152,20 @@ func LoadConfig() (*Config, error) {
 	cfg := &Config{
 		ServiceName:               getEnv("SERVICE_NAME", "market-data-adapter"),
 		ServiceInstanceName:       getEnv("SERVICE_INSTANCE_NAME", ""),
+		InstanceID:                getEnv("INSTANCE_ID", ""),
 		ServiceVersion:            getEnv("SERVICE_VERSION", "1.0.0"),
 		Environment:               getEnv("ENVIRONMENT", "development"),
 		SchemaName:                getEnv("SCHEMA_NAME", ""),
 		RedisNamespace:            getEnv("REDIS_NAMESPACE", ""),
+		DatabaseURL:               getEnv("DATABASE_URL", ""),
 		PostgresURL:               getEnv("POSTGRES_URL", ""),
 		MaxConnections:            getEnvInt("MAX_CONNECTIONS", 25),
 		MaxIdleConnections:        getEnvInt("MAX_IDLE_CONNECTIONS", 10),
 		ConnectionMaxLifetime:     getEnvDuration("CONNECTION_MAX_LIFETIME", 300*time.Second),
 		ConnectionMaxIdleTime:     getEnvDuration("CONNECTION_MAX_IDLE_TIME", 60*time.Second),
+		AutoMigrate:               getEnvBool("AUTO_MIGRATE", false),
+		TxIsolationLevel:          getEnv("TX_ISOLATION_LEVEL", "read_committed"),
+		TxMaxRetries:              getEnvInt("TX_MAX_RETRIES", 3),
 		RedisURL:                  getEnv("REDIS_URL", ""),
 		RedisPoolSize:             getEnvInt("REDIS_POOL_SIZE", 10),
 		RedisMinIdleConns:         getEnvInt("REDIS_MIN_IDLE_CONNS", 2),
@@ -89,8 +176,58 @@ func LoadConfig() (*Config, error) {
 		CacheTTL:                  getEnvDuration("CACHE_TTL", 300*time.Second),
 		CacheNamespace:            getEnv("CACHE_NAMESPACE", "market_data"),
 		ServiceDiscoveryNamespace: getEnv("SERVICE_DISCOVERY_NAMESPACE", "market_data"),
+		ConfigureKeyspaceEvents:   getEnvBool("CONFIGURE_KEYSPACE_EVENTS", false),
 		HeartbeatInterval:         getEnvDuration("HEARTBEAT_INTERVAL", 30*time.Second),
 		ServiceTTL:                getEnvDuration("SERVICE_TTL", 90*time.Second),
+		AutoRegisterService:       getEnvBool("AUTO_REGISTER_SERVICE", true),
+		ServiceAddress:            getEnv("SERVICE_ADDRESS", ""),
+		ServicePort:               getEnvInt("SERVICE_PORT", 0),
+		ServiceTags:               getEnvList("SERVICE_TAGS", nil),
+		ServiceWeight:             getEnvInt("SERVICE_WEIGHT", 1),
+		PublishMarketData:         getEnvBool("PUBLISH_MARKET_DATA", true),
+		EventLogEnabled:           getEnvBool("EVENT_LOG_ENABLED", true),
+		EventLogMaxLen:            getEnvInt("EVENT_LOG_MAX_LEN", 100000),
+		OutboxEnabled:             getEnvBool("OUTBOX_ENABLED", false),
+		OutboxSink:                getEnv("OUTBOX_SINK", "stream"),
+		OutboxPollInterval:        getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:           getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		OutboxRetention:           getEnvDuration("OUTBOX_RETENTION", 24*time.Hour),
+		OutboxMaxAttempts:         getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+		ChangeFeedEnabled:         getEnvBool("CHANGE_FEED_ENABLED", false),
+		GapDetectionEnabled:       getEnvBool("GAP_DETECTION_ENABLED", true),
+		FreshnessEnabled:          getEnvBool("FRESHNESS_ENABLED", false),
+		FreshnessDefaultSLA:       getEnvDuration("FRESHNESS_DEFAULT_SLA", 30*time.Second),
+		FreshnessSymbolSLAs:       getEnvDurationMap("FRESHNESS_SYMBOL_SLAS"),
+		FreshnessSourceSLAs:       getEnvDurationMap("FRESHNESS_SOURCE_SLAS"),
+		FreshnessCheckInterval:    getEnvDuration("FRESHNESS_CHECK_INTERVAL", 10*time.Second),
+		FreshnessExpiry:           getEnvDuration("FRESHNESS_EXPIRY", time.Hour),
+		AnomalyFilterEnabled:      getEnvBool("ANOMALY_FILTER_ENABLED", false),
+		AnomalyAction:             getEnv("ANOMALY_ACTION", "quarantine"),
+		AnomalyZScore:             getEnvFloat("ANOMALY_ZSCORE", 6),
+		AnomalyMaxJump:            getEnvFloat("ANOMALY_MAX_JUMP", 0.1),
+		AnomalyWindow:             getEnvInt("ANOMALY_WINDOW", 50),
+		AnomalyRebaseline:         getEnvInt("ANOMALY_REBASELINE", 5),
+		SnapshotMode:              getEnv("SNAPSHOT_MODE", "off"),
+		SnapshotInterval:          getEnvDuration("SNAPSHOT_INTERVAL", time.Minute),
+		SnapshotSymbols:           getEnvList("SNAPSHOT_SYMBOLS", nil),
+		SnapshotWindow:            getEnvDuration("SNAPSHOT_WINDOW", 24*time.Hour),
+		SnapshotCandleInterval:    getEnv("SNAPSHOT_CANDLE_INTERVAL", "1h"),
+		RetentionEnabled:          getEnvBool("RETENTION_ENABLED", false),
+		RetentionInterval:         getEnvDuration("RETENTION_INTERVAL", time.Hour),
+		RetentionPolicies:         getEnv("RETENTION_POLICIES", "price_feeds=7d,candles:1m=90d,candles:1h=forever,snapshots=30d"),
+		PartitioningEnabled:       getEnvBool("PARTITIONING_ENABLED", false),
+		PartitionIntervals:        getEnv("PARTITION_INTERVALS", "price_feeds=day,candles=month,market_snapshots=day"),
+		PartitionPremake:          getEnvInt("PARTITION_PREMAKE", 3),
+		PartitionCheckInterval:    getEnvDuration("PARTITION_CHECK_INTERVAL", time.Hour),
+		TimescaleEnabled:          getEnvBool("TIMESCALE_ENABLED", false),
+		TimescaleChunkInterval:    getEnvDuration("TIMESCALE_CHUNK_INTERVAL", 24*time.Hour),
+		TimescaleCompressAfter:    getEnvDuration("TIMESCALE_COMPRESS_AFTER", 7*24*time.Hour),
+		TimescaleRetention:        getEnv("TIMESCALE_RETENTION", ""),
+		TimescaleAggregateSource:  getEnv("TIMESCALE_AGGREGATE_SOURCE", "1m"),
+		TimescaleAggregates:       getEnvList("TIMESCALE_AGGREGATES", []string{"5m", "15m", "1h", "4h", "1d"}),
+		TimescaleRefreshWindow:    getEnvDuration("TIMESCALE_REFRESH_WINDOW", 72*time.Hour),
+		LeaderLeaseDuration:       getEnvDuration("LEADER_LEASE_DURATION", 15*time.Second),
+		LeaderRenewInterval:       getEnvDuration("LEADER_RENEW_INTERVAL", 5*time.Second),
 		TestPostgresURL:           getEnv("TEST_POSTGRES_URL", ""),
 		TestRedisURL:              getEnv("TEST_REDIS_URL", ""),
 		LogLevel:                  getEnv("LOG_LEVEL", "info"),
@@ -153,6 +290,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -170,3 +316,31 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+func getEnvList(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
+
+// getEnvDurationMap parses "key=duration" pairs separated by commas, skipping malformed entries
+func getEnvDurationMap(key string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for _, item := range getEnvList(key, nil) {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		if duration, err := time.ParseDuration(strings.TrimSpace(value)); err == nil {
+			result[strings.TrimSpace(name)] = duration
+		}
+	}
+	return result
+}
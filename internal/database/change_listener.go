@@ -0,0 +1,306 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Transactions commit out of timestamp order, so resync looks back a little further
+	// than the last event seen; subscribers may receive a few duplicates
+	resyncOverlap = 5 * time.Second
+
+	// Bound on rows replayed per table after a reconnect
+	resyncLimit = 10000
+
+	listenerPingInterval = 90 * time.Second
+)
+
+// changeTable describes how to replay a table's changes from its rows. The time column
+// is written by the database, so it shares a clock with the notifications' timestamps.
+type changeTable struct {
+	name       string
+	idColumn   string
+	timeColumn string
+	operation  interfaces.ChangeOperation
+}
+
+var changeTables = []changeTable{
+	{name: "price_feeds", idColumn: "feed_id", timeColumn: "persisted_at", operation: interfaces.ChangeInsert},
+	{name: "candles", idColumn: "candle_id", timeColumn: "updated_at", operation: interfaces.ChangeUpdate},
+	{name: "market_snapshots", idColumn: "snapshot_id", timeColumn: "persisted_at", operation: interfaces.ChangeInsert},
+	{name: "symbols", idColumn: "symbol_id", timeColumn: "updated_at", operation: interfaces.ChangeUpdate},
+}
+
+// ChangeListener turns NOTIFY messages from the change triggers into typed change events
+// and fans them out to subscribers. The underlying pq.Listener reconnects on its own;
+// after each reconnect the listener replays rows changed since the last event it saw.
+type ChangeListener struct {
+	db      *sql.DB
+	url     string
+	schema  string
+	channel string
+	logger  *logrus.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan *interfaces.ChangeEvent]struct{}
+	lastSeen    time.Time
+	listener    *pq.Listener
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewChangeListener creates a listener on this database's schema change channel
+func (p *PostgresDB) NewChangeListener() *ChangeListener {
+	return &ChangeListener{
+		db:          p.DB,
+		url:         p.config.PostgresURL,
+		schema:      p.config.SchemaName,
+		channel:     ChangeChannel(p.config.SchemaName),
+		logger:      p.logger,
+		subscribers: make(map[chan *interfaces.ChangeEvent]struct{}),
+	}
+}
+
+// ChangeChannel returns the NOTIFY channel the change triggers use for schema
+func ChangeChannel(schema string) string {
+	return schema + "_changes"
+}
+
+func (l *ChangeListener) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cancel != nil {
+		return fmt.Errorf("change listener already started")
+	}
+
+	listener := pq.NewListener(l.url, time.Second, 30*time.Second, l.onListenerEvent)
+	if err := listener.Listen(l.channel); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to listen on %s: %w", l.channel, err)
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	l.listener = listener
+	l.lastSeen = time.Now()
+	l.cancel = cancel
+	l.done = make(chan struct{})
+
+	go l.listenLoop(loopCtx, listener, l.done)
+
+	l.logger.WithField("channel", l.channel).Info("Change listener started")
+	return nil
+}
+
+func (l *ChangeListener) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	cancel, done, listener := l.cancel, l.done, l.listener
+	l.cancel, l.done, l.listener = nil, nil, nil
+	l.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	<-done
+
+	l.mu.Lock()
+	for ch := range l.subscribers {
+		close(ch)
+		delete(l.subscribers, ch)
+	}
+	l.mu.Unlock()
+
+	if err := listener.Close(); err != nil {
+		return fmt.Errorf("failed to close change listener: %w", err)
+	}
+
+	l.logger.WithField("channel", l.channel).Info("Change listener stopped")
+	return nil
+}
+
+// Subscribe returns a channel of change events that closes when ctx is done or the
+// listener stops, whichever comes first
+func (l *ChangeListener) Subscribe(ctx context.Context) (<-chan *interfaces.ChangeEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cancel == nil {
+		return nil, fmt.Errorf("change listener not started")
+	}
+
+	ch := make(chan *interfaces.ChangeEvent, 256)
+	l.subscribers[ch] = struct{}{}
+
+	stopped := l.done
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.subscribers[ch]; ok {
+			delete(l.subscribers, ch)
+			close(ch)
+		}
+	}()
+
+	return ch, nil
+}
+
+func (l *ChangeListener) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		l.logger.WithError(err).WithField("channel", l.channel).Warn("Change listener disconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.logger.WithError(err).WithField("channel", l.channel).Warn("Change listener reconnect attempt failed")
+	case pq.ListenerEventReconnected:
+		l.logger.WithField("channel", l.channel).Info("Change listener reconnected")
+	}
+}
+
+func (l *ChangeListener) listenLoop(ctx context.Context, listener *pq.Listener, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-listener.Notify:
+			if notification == nil {
+				// pq sends nil after re-establishing the connection
+				l.resync(ctx)
+				continue
+			}
+
+			event, err := ParseChangeNotification(notification.Extra)
+			if err != nil {
+				l.logger.WithError(err).WithField("channel", l.channel).Warn("Failed to parse change notification")
+				continue
+			}
+			l.dispatch(event)
+		case <-ticker.C:
+			// Surfaces a silently dropped connection so pq reconnects
+			go func() {
+				if err := listener.Ping(); err != nil {
+					l.logger.WithError(err).WithField("channel", l.channel).Warn("Change listener ping failed")
+				}
+			}()
+		}
+	}
+}
+
+// ParseChangeNotification decodes the JSON payload sent by the notify_change trigger
+func ParseChangeNotification(payload string) (*interfaces.ChangeEvent, error) {
+	var event interfaces.ChangeEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, fmt.Errorf("failed to decode change notification: %w", err)
+	}
+
+	switch event.Operation {
+	case interfaces.ChangeInsert, interfaces.ChangeUpdate, interfaces.ChangeDelete:
+	default:
+		return nil, fmt.Errorf("unknown change operation %q", event.Operation)
+	}
+	return &event, nil
+}
+
+// dispatch fans an event out without blocking; a subscriber that falls behind loses
+// events rather than stalling the others
+func (l *ChangeListener) dispatch(event *interfaces.ChangeEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if event.Timestamp.After(l.lastSeen) {
+		l.lastSeen = event.Timestamp
+	}
+
+	for ch := range l.subscribers {
+		select {
+		case ch <- event:
+		default:
+			l.logger.WithFields(logrus.Fields{
+				"table": event.Table,
+				"id":    event.ID,
+			}).Warn("Change subscriber is full, dropping event")
+		}
+	}
+}
+
+// resync replays rows changed while the connection was down
+func (l *ChangeListener) resync(ctx context.Context) {
+	l.mu.Lock()
+	since := l.lastSeen.Add(-resyncOverlap)
+	l.mu.Unlock()
+
+	replayed := 0
+	for _, table := range changeTables {
+		events, err := l.changedSince(ctx, table, since)
+		if err != nil {
+			l.logger.WithError(err).WithField("table", table.name).Error("Failed to resync changes")
+			continue
+		}
+		for _, event := range events {
+			l.dispatch(event)
+		}
+		replayed += len(events)
+
+		if len(events) >= resyncLimit {
+			l.logger.WithFields(logrus.Fields{
+				"table":          table.name,
+				"limit":          resyncLimit,
+				"replayed_until": events[len(events)-1].Timestamp,
+			}).Warn("Change resync truncated; later changes made while disconnected were not replayed")
+		}
+	}
+
+	l.logger.WithFields(logrus.Fields{
+		"since":    since,
+		"replayed": replayed,
+	}).Info("Change listener resynced")
+}
+
+func (l *ChangeListener) changedSince(ctx context.Context, table changeTable, since time.Time) ([]*interfaces.ChangeEvent, error) {
+	rows, err := l.db.QueryContext(ctx, l.resyncQuery(table), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s changes: %w", table.name, err)
+	}
+	defer rows.Close()
+
+	var events []*interfaces.ChangeEvent
+	for rows.Next() {
+		event := &interfaces.ChangeEvent{
+			Table:     table.name,
+			Operation: table.operation,
+			Resynced:  true,
+		}
+		if err := rows.Scan(&event.ID, &event.Symbol, &event.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan %s change: %w", table.name, err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// resyncQuery selects up to resyncLimit of table's rows written after $1, oldest first
+func (l *ChangeListener) resyncQuery(table changeTable) string {
+	timeColumn := pq.QuoteIdentifier(table.timeColumn)
+	return fmt.Sprintf(`SELECT %s::text, symbol, %s FROM %s.%s WHERE %s > $1 ORDER BY %s LIMIT %d`,
+		pq.QuoteIdentifier(table.idColumn), timeColumn,
+		pq.QuoteIdentifier(l.schema), pq.QuoteIdentifier(table.name),
+		timeColumn, timeColumn, resyncLimit)
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChangeNotification_DecodesTriggerPayload(t *testing.T) {
+	payload := `{"table" : "symbols", "operation" : "UPDATE", "id" : "6b1f3c0e-8f57-4c55-9d3e-4a2b8d0f1a11", "symbol" : "BTC-USD", "timestamp" : "2025-03-01T12:34:56.789012+00:00"}`
+
+	event, err := ParseChangeNotification(payload)
+	require.NoError(t, err)
+
+	assert.Equal(t, "symbols", event.Table)
+	assert.Equal(t, interfaces.ChangeUpdate, event.Operation)
+	assert.Equal(t, "6b1f3c0e-8f57-4c55-9d3e-4a2b8d0f1a11", event.ID)
+	assert.Equal(t, "BTC-USD", event.Symbol)
+	assert.Equal(t, 2025, event.Timestamp.Year())
+	assert.False(t, event.Resynced)
+}
+
+func TestParseChangeNotification_RejectsUnknownOperation(t *testing.T) {
+	_, err := ParseChangeNotification(`{"table": "symbols", "operation": "TRUNCATE", "id": "1"}`)
+	assert.Error(t, err)
+
+	_, err = ParseChangeNotification(`not json`)
+	assert.Error(t, err)
+}
+
+func TestChangeListener_DispatchFansOutAndTracksLastSeen(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	listener := &ChangeListener{
+		logger:      logger,
+		subscribers: make(map[chan *interfaces.ChangeEvent]struct{}),
+		cancel:      func() {},
+		done:        make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first, err := listener.Subscribe(ctx)
+	require.NoError(t, err)
+	second, err := listener.Subscribe(context.Background())
+	require.NoError(t, err)
+
+	at := time.Now().Add(time.Minute)
+	listener.dispatch(&interfaces.ChangeEvent{Table: "price_feeds", Operation: interfaces.ChangeInsert, ID: "feed-1", Timestamp: at})
+
+	assert.Equal(t, "feed-1", (<-first).ID)
+	assert.Equal(t, "feed-1", (<-second).ID)
+	assert.Equal(t, at, listener.lastSeen, "Resync should start from the newest event seen")
+
+	cancel()
+	_, open := <-first
+	assert.False(t, open, "Cancelling the subscription context should close its channel")
+}
+
+func TestChangeListener_SubscriptionEndsWhenListenerStops(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	done := make(chan struct{})
+	listener := &ChangeListener{
+		logger:      logger,
+		subscribers: make(map[chan *interfaces.ChangeEvent]struct{}),
+		cancel:      func() {},
+		done:        done,
+	}
+
+	events, err := listener.Subscribe(context.Background())
+	require.NoError(t, err)
+
+	// Stop closes done once the listen loop exits
+	close(done)
+
+	select {
+	case _, open := <-events:
+		assert.False(t, open, "Stopping the listener should close subscriptions made without a deadline")
+	case <-time.After(time.Second):
+		require.FailNow(t, "Subscription outlived the listener")
+	}
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+	assert.Empty(t, listener.subscribers)
+}
+
+func TestChangeChannel_IsPerSchema(t *testing.T) {
+	assert.Equal(t, "market_data_coinmetrics_changes", ChangeChannel("market_data_coinmetrics"))
+}
+
+func TestChangeListener_ResyncUsesDatabaseWriteTimes(t *testing.T) {
+	listener := &ChangeListener{schema: "market_data"}
+
+	queries := make(map[string]string)
+	for _, table := range changeTables {
+		queries[table.name] = listener.resyncQuery(table)
+	}
+
+	assert.Equal(t, `SELECT "feed_id"::text, symbol, "persisted_at" FROM "market_data"."price_feeds" WHERE "persisted_at" > $1 ORDER BY "persisted_at" LIMIT 10000`, queries["price_feeds"])
+	assert.Contains(t, queries["candles"], `WHERE "updated_at" > $1`)
+	assert.Contains(t, queries["market_snapshots"], `WHERE "persisted_at" > $1`)
+	assert.Contains(t, queries["symbols"], `WHERE "updated_at" > $1`)
+}
@@ -0,0 +1,484 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// Migration is one forward-only schema change. SQL may reference {{schema}}, which is
+// replaced with the quoted instance schema so each instance migrates its own tables.
+type Migration struct {
+	Version     int
+	Description string
+	SQL         string
+}
+
+// migrations must stay in ascending version order; never edit one that has shipped
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "market data tables",
+		SQL: `
+CREATE TABLE IF NOT EXISTS {{schema}}.price_feeds (
+    feed_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL,
+    price DECIMAL(24, 8) NOT NULL,
+    bid DECIMAL(24, 8),
+    ask DECIMAL(24, 8),
+    volume_24h DECIMAL(24, 8),
+    source VARCHAR(100) NOT NULL DEFAULT 'simulator',
+    timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    metadata JSONB,
+
+    CONSTRAINT positive_price CHECK (price > 0),
+    CONSTRAINT positive_bid CHECK (bid IS NULL OR bid > 0),
+    CONSTRAINT positive_ask CHECK (ask IS NULL OR ask > 0),
+    CONSTRAINT positive_volume CHECK (volume_24h IS NULL OR volume_24h >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_feeds_symbol ON {{schema}}.price_feeds(symbol);
+CREATE INDEX IF NOT EXISTS idx_price_feeds_timestamp ON {{schema}}.price_feeds(timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_price_feeds_symbol_timestamp ON {{schema}}.price_feeds(symbol, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_price_feeds_source ON {{schema}}.price_feeds(source);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.candles (
+    candle_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    open DECIMAL(24, 8) NOT NULL,
+    high DECIMAL(24, 8) NOT NULL,
+    low DECIMAL(24, 8) NOT NULL,
+    close DECIMAL(24, 8) NOT NULL,
+    volume DECIMAL(24, 8) NOT NULL DEFAULT 0,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    num_trades INTEGER DEFAULT 0,
+    metadata JSONB,
+
+    CONSTRAINT positive_ohlc CHECK (open > 0 AND high > 0 AND low > 0 AND close > 0),
+    CONSTRAINT valid_high_low CHECK (high >= low),
+    CONSTRAINT high_gte_open_close CHECK (high >= open AND high >= close),
+    CONSTRAINT low_lte_open_close CHECK (low <= open AND low <= close),
+    CONSTRAINT positive_volume CHECK (volume >= 0),
+    CONSTRAINT non_negative_trades CHECK (num_trades >= 0),
+    CONSTRAINT unique_symbol_interval_time UNIQUE (symbol, interval, start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candles_symbol ON {{schema}}.candles(symbol);
+CREATE INDEX IF NOT EXISTS idx_candles_interval ON {{schema}}.candles(interval);
+CREATE INDEX IF NOT EXISTS idx_candles_start_time ON {{schema}}.candles(start_time DESC);
+CREATE INDEX IF NOT EXISTS idx_candles_symbol_interval_time ON {{schema}}.candles(symbol, interval, start_time DESC);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.market_snapshots (
+    snapshot_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL,
+    last_price DECIMAL(24, 8) NOT NULL,
+    bid DECIMAL(24, 8),
+    ask DECIMAL(24, 8),
+    spread DECIMAL(24, 8),
+    volume_24h DECIMAL(24, 8),
+    price_change_24h DECIMAL(24, 8),
+    price_change_percent_24h DECIMAL(10, 4),
+    timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    metadata JSONB,
+
+    CONSTRAINT positive_last_price CHECK (last_price > 0),
+    CONSTRAINT positive_spread CHECK (spread IS NULL OR spread >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_snapshots_symbol ON {{schema}}.market_snapshots(symbol);
+CREATE INDEX IF NOT EXISTS idx_snapshots_timestamp ON {{schema}}.market_snapshots(timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_snapshots_symbol_timestamp ON {{schema}}.market_snapshots(symbol, timestamp DESC);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.symbols (
+    symbol_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL UNIQUE,
+    base_currency VARCHAR(10) NOT NULL,
+    quote_currency VARCHAR(10) NOT NULL,
+    display_name VARCHAR(100),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    min_price_movement DECIMAL(24, 8),
+    min_order_size DECIMAL(24, 8),
+    max_order_size DECIMAL(24, 8),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    metadata JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbols_active ON {{schema}}.symbols(is_active);
+CREATE INDEX IF NOT EXISTS idx_symbols_base_currency ON {{schema}}.symbols(base_currency);
+CREATE INDEX IF NOT EXISTS idx_symbols_quote_currency ON {{schema}}.symbols(quote_currency);
+`,
+	},
+	{
+		Version:     2,
+		Description: "transactional outbox",
+		SQL: `
+CREATE TABLE IF NOT EXISTS {{schema}}.outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    aggregate VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    operation VARCHAR(20) NOT NULL,
+    symbol VARCHAR(50),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON {{schema}}.outbox(id) WHERE delivered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_delivered_at ON {{schema}}.outbox(delivered_at) WHERE delivered_at IS NOT NULL;
+`,
+	},
+	{
+		Version:     3,
+		Description: "change notification triggers",
+		SQL: `
+-- Notifies <schema>_changes with the table, operation, primary key (named by the
+-- trigger argument) and symbol of every changed row
+CREATE OR REPLACE FUNCTION {{schema}}.notify_change() RETURNS trigger AS $$
+DECLARE
+    row_data JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+    ELSE
+        row_data := to_jsonb(NEW);
+    END IF;
+
+    PERFORM pg_notify(
+        TG_TABLE_SCHEMA || '_changes',
+        json_build_object(
+            'table', TG_TABLE_NAME,
+            'operation', TG_OP,
+            'id', row_data ->> TG_ARGV[0],
+            'symbol', row_data ->> 'symbol',
+            'timestamp', NOW()
+        )::text
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS price_feeds_notify_change ON {{schema}}.price_feeds;
+CREATE TRIGGER price_feeds_notify_change AFTER INSERT OR UPDATE OR DELETE ON {{schema}}.price_feeds
+    FOR EACH ROW EXECUTE FUNCTION {{schema}}.notify_change('feed_id');
+
+DROP TRIGGER IF EXISTS candles_notify_change ON {{schema}}.candles;
+CREATE TRIGGER candles_notify_change AFTER INSERT OR UPDATE OR DELETE ON {{schema}}.candles
+    FOR EACH ROW EXECUTE FUNCTION {{schema}}.notify_change('candle_id');
+
+DROP TRIGGER IF EXISTS market_snapshots_notify_change ON {{schema}}.market_snapshots;
+CREATE TRIGGER market_snapshots_notify_change AFTER INSERT OR UPDATE OR DELETE ON {{schema}}.market_snapshots
+    FOR EACH ROW EXECUTE FUNCTION {{schema}}.notify_change('snapshot_id');
+
+DROP TRIGGER IF EXISTS symbols_notify_change ON {{schema}}.symbols;
+CREATE TRIGGER symbols_notify_change AFTER INSERT OR UPDATE OR DELETE ON {{schema}}.symbols
+    FOR EACH ROW EXECUTE FUNCTION {{schema}}.notify_change('symbol_id');
+`,
+	},
+	{
+		Version:     4,
+		Description: "price feed idempotency keys",
+		SQL: `
+ALTER TABLE {{schema}}.price_feeds ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_feeds_idempotency_key
+    ON {{schema}}.price_feeds(idempotency_key) WHERE idempotency_key IS NOT NULL;
+`,
+	},
+	{
+		Version:     5,
+		Description: "price feed sequences and data quality events",
+		SQL: `
+ALTER TABLE {{schema}}.price_feeds ADD COLUMN IF NOT EXISTS sequence BIGINT;
+
+CREATE TABLE IF NOT EXISTS {{schema}}.data_quality_events (
+    event_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type VARCHAR(50) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    symbol VARCHAR(50) NOT NULL,
+    expected_sequence BIGINT NOT NULL DEFAULT 0,
+    received_sequence BIGINT NOT NULL DEFAULT 0,
+    missing BIGINT NOT NULL DEFAULT 0,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    metadata JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_quality_events_detected_at ON {{schema}}.data_quality_events(detected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_quality_events_source_symbol ON {{schema}}.data_quality_events(source, symbol, detected_at DESC);
+`,
+	},
+	{
+		Version:     6,
+		Description: "price feed receive and persist timestamps",
+		SQL: `
+ALTER TABLE {{schema}}.price_feeds ADD COLUMN IF NOT EXISTS received_at TIMESTAMPTZ;
+ALTER TABLE {{schema}}.price_feeds ADD COLUMN IF NOT EXISTS persisted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_price_feeds_source_received_at ON {{schema}}.price_feeds(source, received_at);
+`,
+	},
+	{
+		Version:     7,
+		Description: "feed source priorities and switch events",
+		SQL: `
+CREATE TABLE IF NOT EXISTS {{schema}}.symbol_source_priorities (
+    symbol VARCHAR(50) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    priority INTEGER NOT NULL,
+
+    PRIMARY KEY (symbol, source)
+);
+
+CREATE TABLE IF NOT EXISTS {{schema}}.source_switch_events (
+    event_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL,
+    from_source VARCHAR(100) NOT NULL,
+    to_source VARCHAR(100) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    switched_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_source_switch_events_symbol ON {{schema}}.source_switch_events(symbol, switched_at DESC);
+`,
+	},
+	{
+		Version:     8,
+		Description: "price feed quarantine",
+		SQL: `
+CREATE TABLE IF NOT EXISTS {{schema}}.price_feed_quarantine (
+    quarantine_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(50) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    price DECIMAL(24,8) NOT NULL,
+    feed JSONB NOT NULL,
+    findings JSONB NOT NULL,
+    quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_feed_quarantine_symbol ON {{schema}}.price_feed_quarantine(symbol, quarantined_at DESC);
+`,
+	},
+	{
+		Version:     9,
+		Description: "candle backfill checkpoints",
+		SQL: `
+CREATE TABLE IF NOT EXISTS {{schema}}.candle_backfill_checkpoints (
+    symbol VARCHAR(50) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    from_time TIMESTAMPTZ NOT NULL,
+    to_time TIMESTAMPTZ NOT NULL,
+    cursor_time TIMESTAMPTZ NOT NULL,
+    filled INTEGER NOT NULL DEFAULT 0,
+    unfilled INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (symbol, interval)
+);
+`,
+	},
+	{
+		Version:     10,
+		Description: "partition-ready idempotency keys and change triggers",
+		SQL: `
+-- Unique indexes on a partitioned table must include the partition key, so idempotency
+-- keys move to their own table where they stay unique across every timestamp
+CREATE TABLE IF NOT EXISTS {{schema}}.price_feed_idempotency_keys (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    feed_id UUID NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_feed_idempotency_keys_timestamp ON {{schema}}.price_feed_idempotency_keys(timestamp);
+
+INSERT INTO {{schema}}.price_feed_idempotency_keys (idempotency_key, feed_id, timestamp)
+    SELECT idempotency_key, feed_id, timestamp FROM {{schema}}.price_feeds WHERE idempotency_key IS NOT NULL
+    ON CONFLICT (idempotency_key) DO NOTHING;
+
+DROP INDEX IF EXISTS {{schema}}.idx_price_feeds_idempotency_key;
+
+-- Triggers cloned onto partitions see the partition's name, so an optional second
+-- argument names the table reported to listeners
+CREATE OR REPLACE FUNCTION {{schema}}.notify_change() RETURNS trigger AS $$
+DECLARE
+    row_data JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+    ELSE
+        row_data := to_jsonb(NEW);
+    END IF;
+
+    PERFORM pg_notify(
+        TG_TABLE_SCHEMA || '_changes',
+        json_build_object(
+            'table', COALESCE(TG_ARGV[1], TG_TABLE_NAME),
+            'operation', TG_OP,
+            'id', row_data ->> TG_ARGV[0],
+            'symbol', row_data ->> 'symbol',
+            'timestamp', NOW()
+        )::text
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+`,
+	},
+	{
+		Version:     11,
+		Description: "change trigger channel argument",
+		SQL: `
+-- TimescaleDB fires hypertable triggers on chunks in its own schema, so an optional third
+-- argument names the schema whose channel is notified
+CREATE OR REPLACE FUNCTION {{schema}}.notify_change() RETURNS trigger AS $$
+DECLARE
+    row_data JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+    ELSE
+        row_data := to_jsonb(NEW);
+    END IF;
+
+    PERFORM pg_notify(
+        COALESCE(TG_ARGV[2], TG_TABLE_SCHEMA) || '_changes',
+        json_build_object(
+            'table', COALESCE(TG_ARGV[1], TG_TABLE_NAME),
+            'operation', TG_OP,
+            'id', row_data ->> TG_ARGV[0],
+            'symbol', row_data ->> 'symbol',
+            'timestamp', NOW()
+        )::text
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+`,
+	},
+	{
+		Version:     12,
+		Description: "database write times for change resync",
+		SQL: `
+-- Resync after a reconnect replays rows by when the database wrote them, on the same
+-- clock as the change notifications. Existing rows have no write time and are not replayed.
+ALTER TABLE {{schema}}.candles ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE {{schema}}.candles ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE {{schema}}.market_snapshots ADD COLUMN IF NOT EXISTS persisted_at TIMESTAMPTZ;
+ALTER TABLE {{schema}}.market_snapshots ALTER COLUMN persisted_at SET DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_price_feeds_persisted_at ON {{schema}}.price_feeds(persisted_at);
+CREATE INDEX IF NOT EXISTS idx_candles_updated_at ON {{schema}}.candles(updated_at);
+CREATE INDEX IF NOT EXISTS idx_snapshots_persisted_at ON {{schema}}.market_snapshots(persisted_at);
+`,
+	},
+	{
+		Version:     13,
+		Description: "outbox dead letters",
+		SQL: `
+ALTER TABLE {{schema}}.outbox ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS {{schema}}.idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON {{schema}}.outbox(id) WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_dead_lettered_at ON {{schema}}.outbox(dead_lettered_at) WHERE dead_lettered_at IS NOT NULL;
+`,
+	},
+}
+
+// Migrations returns the known migrations in version order
+func Migrations() []Migration {
+	result := make([]Migration, len(migrations))
+	copy(result, migrations)
+	return result
+}
+
+// RenderSQL returns the migration's SQL for the given schema
+func (m Migration) RenderSQL(schema string) string {
+	return strings.ReplaceAll(m.SQL, "{{schema}}", pq.QuoteIdentifier(schema))
+}
+
+// Migrate applies pending migrations to the configured schema. Each migration runs in
+// its own transaction under an advisory lock, so concurrently starting replicas apply
+// it exactly once.
+func (p *PostgresDB) Migrate(ctx context.Context) error {
+	if p.DB == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	schema := p.config.SchemaName
+	quoted := pq.QuoteIdentifier(schema)
+
+	bootstrap := fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s;
+CREATE TABLE IF NOT EXISTS %s.schema_migrations (
+    version INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`, quoted, quoted)
+	if _, err := p.DB.ExecContext(ctx, bootstrap); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied := 0
+	for _, m := range migrations {
+		ok, err := p.applyMigration(ctx, schema, m)
+		if err != nil {
+			return err
+		}
+		if ok {
+			applied++
+			p.logger.WithFields(logrus.Fields{
+				"schema":      schema,
+				"version":     m.Version,
+				"description": m.Description,
+			}).Info("Applied migration")
+		}
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"schema":  schema,
+		"applied": applied,
+	}).Info("PostgreSQL migrations complete")
+	return nil
+}
+
+func (p *PostgresDB) applyMigration(ctx context.Context, schema string, m Migration) (bool, error) {
+	tx, err := p.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin migration %d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "schema_migrations:"+schema); err != nil {
+		return false, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	table := pq.QuoteIdentifier(schema) + ".schema_migrations"
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE version = $1)", m.Version).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check migration %d: %w", m.Version, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.RenderSQL(schema)); err != nil {
+		return false, fmt.Errorf("failed to apply migration %d (%s): %w", m.Version, m.Description, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+table+" (version, description) VALUES ($1, $2)", m.Version, m.Description); err != nil {
+		return false, fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations_VersionsStrictlyIncrease(t *testing.T) {
+	previous := 0
+	for _, m := range Migrations() {
+		assert.Greater(t, m.Version, previous, "Migration %d (%s) is out of order", m.Version, m.Description)
+		assert.NotEmpty(t, m.Description)
+		previous = m.Version
+	}
+}
+
+func TestMigration_RenderSQLQualifiesSchema(t *testing.T) {
+	for _, m := range Migrations() {
+		rendered := m.RenderSQL("market_data_coinmetrics")
+
+		assert.NotContains(t, rendered, "{{schema}}")
+		if strings.Contains(m.SQL, "{{schema}}") {
+			assert.Contains(t, rendered, `"market_data_coinmetrics".`)
+		}
+	}
+}
@@ -0,0 +1,627 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// PartitionInterval is the span of time one partition covers
+type PartitionInterval string
+
+const (
+	PartitionDaily   PartitionInterval = "day"
+	PartitionWeekly  PartitionInterval = "week"
+	PartitionMonthly PartitionInterval = "month"
+)
+
+// Align returns the start, in UTC, of the partition containing t; weeks start on Monday
+func (i PartitionInterval) Align(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch i {
+	case PartitionWeekly:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PartitionMonthly:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// Next returns the start of the partition after the one containing t
+func (i PartitionInterval) Next(t time.Time) time.Time {
+	start := i.Align(t)
+	switch i {
+	case PartitionWeekly:
+		return start.AddDate(0, 0, 7)
+	case PartitionMonthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func (i PartitionInterval) valid() bool {
+	return i == PartitionDaily || i == PartitionWeekly || i == PartitionMonthly
+}
+
+type partitionIndex struct {
+	name    string
+	columns string
+}
+
+// partitionedTable describes a table range-partitioned on a time column. Unique keys must
+// include that column, so the parent's primary key pairs it with the row ID; the other
+// indexes are those migrations 1 and 6 created on the unpartitioned table.
+type partitionedTable struct {
+	name     string
+	column   string
+	idColumn string
+	unique   []partitionIndex
+	indexes  []partitionIndex
+}
+
+var partitionedTables = []partitionedTable{
+	{
+		name: "price_feeds", column: "timestamp", idColumn: "feed_id",
+		indexes: []partitionIndex{
+			{"idx_price_feeds_symbol", "symbol"},
+			{"idx_price_feeds_timestamp", "timestamp DESC"},
+			{"idx_price_feeds_symbol_timestamp", "symbol, timestamp DESC"},
+			{"idx_price_feeds_source", "source"},
+			{"idx_price_feeds_source_received_at", "source, received_at"},
+		},
+	},
+	{
+		name: "candles", column: "start_time", idColumn: "candle_id",
+		unique: []partitionIndex{
+			{"unique_symbol_interval_time", "symbol, interval, start_time"},
+		},
+		indexes: []partitionIndex{
+			{"idx_candles_symbol", "symbol"},
+			{"idx_candles_interval", "interval"},
+			{"idx_candles_start_time", "start_time DESC"},
+			{"idx_candles_symbol_interval_time", "symbol, interval, start_time DESC"},
+		},
+	},
+	{
+		name: "market_snapshots", column: "timestamp", idColumn: "snapshot_id",
+		indexes: []partitionIndex{
+			{"idx_snapshots_symbol", "symbol"},
+			{"idx_snapshots_timestamp", "timestamp DESC"},
+			{"idx_snapshots_symbol_timestamp", "symbol, timestamp DESC"},
+		},
+	},
+}
+
+// partitionMigration is the first migration whose triggers and idempotency keys work on
+// partitioned tables
+const partitionMigration = 10
+
+// ParsePartitionIntervals reads comma-separated table=interval pairs, e.g.
+// "price_feeds=day,candles=month"; tables left out are partitioned daily
+func ParsePartitionIntervals(spec string) (map[string]PartitionInterval, error) {
+	intervals := make(map[string]PartitionInterval)
+	for _, item := range strings.Split(spec, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("partition interval %q: expected table=interval", item)
+		}
+		name, interval := strings.TrimSpace(name), PartitionInterval(strings.TrimSpace(value))
+		if _, ok := findPartitionedTable(name); !ok {
+			return nil, fmt.Errorf("partition interval %q: %s is not partitioned", item, name)
+		}
+		if !interval.valid() {
+			return nil, fmt.Errorf("partition interval %q: expected day, week or month", item)
+		}
+		intervals[name] = interval
+	}
+	return intervals, nil
+}
+
+func findPartitionedTable(name string) (partitionedTable, bool) {
+	for _, table := range partitionedTables {
+		if table.name == name {
+			return table, true
+		}
+	}
+	return partitionedTable{}, false
+}
+
+type PartitionOptions struct {
+	Intervals     map[string]PartitionInterval // Per table; tables left out are partitioned daily
+	Premake       int                          // Partitions kept ahead of the current one; defaults to 3
+	CheckInterval time.Duration                // Time between partition creation runs; defaults to 1h
+}
+
+// Partition is one range partition; From is zero for the open-ended legacy partition
+type Partition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// PartitionManager range-partitions price_feeds, candles and market_snapshots by time and
+// keeps partitions created ahead of the clock. There is no default partition, so rows
+// beyond the last partition fail to insert; PartitionRange lets writers reject them first,
+// and Premake must cover the longest time the manager might not run.
+type PartitionManager struct {
+	db      *sql.DB
+	schema  string
+	options PartitionOptions
+	logger  *logrus.Logger
+	now     func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	rangesMu sync.RWMutex
+	ranges   map[string]Partition // Span of each table's partitions as this replica last saw it
+}
+
+// NewPartitionManager creates a manager for this database's schema; call it after Connect
+func (p *PostgresDB) NewPartitionManager(options PartitionOptions) *PartitionManager {
+	if options.Premake <= 0 {
+		options.Premake = 3
+	}
+	if options.CheckInterval <= 0 {
+		options.CheckInterval = time.Hour
+	}
+
+	return &PartitionManager{
+		db:      p.DB,
+		schema:  p.config.SchemaName,
+		options: options,
+		logger:  p.logger,
+		now:     time.Now,
+		ranges:  make(map[string]Partition),
+	}
+}
+
+// PartitionRange returns the span table's partitions covered when this replica last
+// maintained or dropped them. Another replica may have created partitions since, so a time
+// past to is only certainly storable once Maintain has run here too.
+func (m *PartitionManager) PartitionRange(table string) (time.Time, time.Time, bool) {
+	m.rangesMu.RLock()
+	defer m.rangesMu.RUnlock()
+
+	span, ok := m.ranges[table]
+	return span.From, span.To, ok
+}
+
+func (m *PartitionManager) interval(table string) PartitionInterval {
+	if interval, ok := m.options.Intervals[table]; ok {
+		return interval
+	}
+	return PartitionDaily
+}
+
+func (m *PartitionManager) qualified(name string) string {
+	return pq.QuoteIdentifier(m.schema) + "." + pq.QuoteIdentifier(name)
+}
+
+// Setup converts tables that are not partitioned yet and creates upcoming partitions.
+// An existing table is kept whole as the <table>_legacy partition, covering everything
+// before the first new partition, and is dropped once retention passes its end.
+func (m *PartitionManager) Setup(ctx context.Context) error {
+	if m.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var version int
+	err := m.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM `+m.qualified("schema_migrations")).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version < partitionMigration {
+		return fmt.Errorf("partitioning requires migration %d, schema is at %d", partitionMigration, version)
+	}
+
+	for _, table := range partitionedTables {
+		err := m.withLock(ctx, func(tx *sql.Tx) error {
+			var partitioned bool
+			if err := tx.QueryRowContext(ctx,
+				`SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = $1::regclass)`, m.qualified(table.name),
+			).Scan(&partitioned); err != nil {
+				return err
+			}
+			if partitioned {
+				return nil
+			}
+			return m.convert(ctx, tx, table)
+		})
+		if err != nil {
+			m.logger.WithError(err).WithField("table", table.name).Error("Failed to partition table")
+			return fmt.Errorf("failed to partition %s: %w", table.name, err)
+		}
+	}
+
+	_, err = m.Maintain(ctx)
+	return err
+}
+
+// convert swaps table for a partitioned parent with the old table attached beneath it
+func (m *PartitionManager) convert(ctx context.Context, tx *sql.Tx, table partitionedTable) error {
+	parent := m.qualified(table.name)
+	legacyName := table.name + "_legacy"
+	legacy := m.qualified(legacyName)
+	interval := m.interval(table.name)
+
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE `+parent+` IN ACCESS EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	// The legacy partition ends after the current period and after its newest row
+	var latest sql.NullTime
+	column := pq.QuoteIdentifier(table.column)
+	if err := tx.QueryRowContext(ctx, `SELECT MAX(`+column+`) FROM `+parent).Scan(&latest); err != nil {
+		return err
+	}
+	end := m.now()
+	if latest.Valid && latest.Time.After(end) {
+		end = latest.Time
+	}
+	end = interval.Next(end)
+
+	// Free the index and trigger names for the parent
+	indexes, err := m.indexNames(ctx, tx, parent)
+	if err != nil {
+		return err
+	}
+	statements := []string{
+		`ALTER TABLE ` + parent + ` RENAME TO ` + pq.QuoteIdentifier(legacyName),
+		`DROP TRIGGER IF EXISTS ` + pq.QuoteIdentifier(table.name+"_notify_change") + ` ON ` + legacy,
+	}
+	for _, index := range indexes {
+		statements = append(statements,
+			`ALTER INDEX `+m.qualified(index)+` RENAME TO `+pq.QuoteIdentifier(legacyIndexName(index)))
+	}
+
+	keys := []string{fmt.Sprintf("PRIMARY KEY (%s, %s)", pq.QuoteIdentifier(table.idColumn), column)}
+	for _, unique := range table.unique {
+		keys = append(keys, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", pq.QuoteIdentifier(unique.name), unique.columns))
+	}
+	statements = append(statements, fmt.Sprintf(
+		`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS, %s) PARTITION BY RANGE (%s)`,
+		parent, legacy, strings.Join(keys, ", "), column))
+	for _, index := range table.indexes {
+		statements = append(statements,
+			fmt.Sprintf(`CREATE INDEX %s ON %s (%s)`, pq.QuoteIdentifier(index.name), parent, index.columns))
+	}
+
+	// Matching legacy indexes are adopted by the parent's rather than rebuilt
+	statements = append(statements,
+		fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (MINVALUE) TO (%s)`, parent, legacy, boundLiteral(end)),
+		fmt.Sprintf(`CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s
+    FOR EACH ROW EXECUTE FUNCTION %s.notify_change(%s, %s)`,
+			pq.QuoteIdentifier(table.name+"_notify_change"), parent, pq.QuoteIdentifier(m.schema),
+			pq.QuoteLiteral(table.idColumn), pq.QuoteLiteral(table.name)),
+	)
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"table":    table.name,
+		"interval": interval,
+		"legacy":   legacyName,
+		"until":    end,
+	}).Info("Partitioned table")
+	return nil
+}
+
+func (m *PartitionManager) indexNames(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT c.relname FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid WHERE i.indrelid = $1::regclass`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// legacyIndexName suffixes name, staying within PostgreSQL's 63-byte identifier limit
+func legacyIndexName(name string) string {
+	const suffix = "_legacy"
+	if len(name) > 63-len(suffix) {
+		name = name[:63-len(suffix)]
+	}
+	return name + suffix
+}
+
+// Maintain creates missing partitions up to Premake periods past the current one and
+// returns how many it created
+func (m *PartitionManager) Maintain(ctx context.Context) (int, error) {
+	if m.db == nil {
+		return 0, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	created := 0
+	spans := make(map[string]Partition)
+	err := m.withLock(ctx, func(tx *sql.Tx) error {
+		for _, table := range partitionedTables {
+			partitions, err := m.partitions(ctx, tx, table.name)
+			if err != nil {
+				return fmt.Errorf("failed to list %s partitions: %w", table.name, err)
+			}
+			if len(partitions) == 0 {
+				return fmt.Errorf("%s has no partitions", table.name)
+			}
+
+			interval := m.interval(table.name)
+			horizon := interval.Align(m.now())
+			for i := 0; i <= m.options.Premake; i++ {
+				horizon = interval.Next(horizon)
+			}
+
+			// Partitions are contiguous from the newest one's end, which need not be aligned
+			// if the interval was changed
+			from := partitions[len(partitions)-1].To
+			for from.Before(horizon) {
+				to := interval.Next(from)
+				name := fmt.Sprintf("%s_p%s", table.name, from.UTC().Format("20060102"))
+				if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+					`CREATE TABLE %s PARTITION OF %s FOR VALUES FROM (%s) TO (%s)`,
+					m.qualified(name), m.qualified(table.name), boundLiteral(from), boundLiteral(to),
+				)); err != nil {
+					return fmt.Errorf("failed to create partition %s: %w", name, err)
+				}
+				created++
+				from = to
+			}
+			spans[table.name] = Partition{Name: table.name, From: partitions[0].From, To: from}
+		}
+		return nil
+	})
+	if err != nil {
+		m.logger.WithError(err).Error("Failed to create partitions")
+		return 0, err
+	}
+
+	m.rangesMu.Lock()
+	for name, span := range spans {
+		m.ranges[name] = span
+	}
+	m.rangesMu.Unlock()
+
+	if created > 0 {
+		m.logger.WithField("created", created).Info("Created partitions")
+	}
+	return created, nil
+}
+
+// Partitions lists table's partitions, oldest first
+func (m *PartitionManager) Partitions(ctx context.Context, table string) ([]Partition, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	partitions, err := m.partitions(ctx, m.db, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s partitions: %w", table, err)
+	}
+	return partitions, nil
+}
+
+func (m *PartitionManager) partitions(ctx context.Context, db queryer, table string) ([]Partition, error) {
+	rows, err := db.QueryContext(ctx, `SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass`, m.qualified(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []Partition
+	for rows.Next() {
+		var partition Partition
+		var bound string
+		if err := rows.Scan(&partition.Name, &bound); err != nil {
+			return nil, err
+		}
+		if partition.From, partition.To, err = parsePartitionBound(bound); err != nil {
+			return nil, fmt.Errorf("partition %s: %w", partition.Name, err)
+		}
+		partitions = append(partitions, partition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].To.Before(partitions[j].To) })
+	return partitions, nil
+}
+
+// DropBefore drops table's partitions that end at or before cutoff, returning the rows
+// they held and the end of the newest one dropped, or zero if none were. Rows older than
+// cutoff in a partition that straddles it stay until the whole partition has expired.
+func (m *PartitionManager) DropBefore(ctx context.Context, table string, cutoff time.Time) (int64, time.Time, error) {
+	partitions, err := m.Partitions(ctx, table)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var rows int64
+	var boundary time.Time
+	for _, partition := range partitions {
+		if partition.To.After(cutoff) {
+			break
+		}
+
+		// Counted before the drop so the brief lock on the parent isn't held for a scan
+		qualified := m.qualified(partition.Name)
+		var count int64
+		if err := m.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+qualified).Scan(&count); err != nil {
+			return rows, boundary, fmt.Errorf("failed to count partition %s: %w", partition.Name, err)
+		}
+		if _, err := m.db.ExecContext(ctx, `DROP TABLE IF EXISTS `+qualified); err != nil {
+			m.logger.WithError(err).WithField("partition", partition.Name).Error("Failed to drop partition")
+			return rows, boundary, fmt.Errorf("failed to drop partition %s: %w", partition.Name, err)
+		}
+
+		rows += count
+		boundary = partition.To
+		m.rangesMu.Lock()
+		if span, ok := m.ranges[table]; ok {
+			span.From = boundary
+			m.ranges[table] = span
+		}
+		m.rangesMu.Unlock()
+		m.logger.WithFields(logrus.Fields{
+			"partition": partition.Name,
+			"until":     partition.To,
+			"rows":      count,
+		}).Info("Dropped partition")
+	}
+	return rows, boundary, nil
+}
+
+// withLock runs fn in a transaction holding the schema's partition lock, so replicas
+// never create the same partition twice
+func (m *PartitionManager) withLock(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "partitions:"+m.schema); err != nil {
+		return fmt.Errorf("failed to lock partitions: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *PartitionManager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return fmt.Errorf("partition manager already started")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.maintainLoop(loopCtx, m.done)
+
+	m.logger.WithFields(logrus.Fields{
+		"schema":   m.schema,
+		"interval": m.options.CheckInterval,
+		"premake":  m.options.Premake,
+	}).Info("Partition manager started")
+	return nil
+}
+
+func (m *PartitionManager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	m.logger.Info("Partition manager stopped")
+	return nil
+}
+
+// maintainLoop relies on Setup having created partitions, so it waits a full interval first
+func (m *PartitionManager) maintainLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.options.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = m.Maintain(ctx)
+		}
+	}
+}
+
+// boundLiteral renders t as a timestamptz literal for partition bounds, which cannot be
+// passed as placeholders
+func boundLiteral(t time.Time) string {
+	return pq.QuoteLiteral(t.UTC().Format("2006-01-02 15:04:05.999999") + "+00")
+}
+
+var partitionBoundPattern = regexp.MustCompile(`^FOR VALUES FROM \((MINVALUE|'[^']*')\) TO \('([^']*)'\)$`)
+
+// parsePartitionBound reads pg_get_expr's rendering of a range bound, whose timestamps are
+// in the session time zone; only MINVALUE is supported as an open end
+func parsePartitionBound(bound string) (time.Time, time.Time, error) {
+	match := partitionBoundPattern.FindStringSubmatch(bound)
+	if match == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("unsupported partition bound %q", bound)
+	}
+
+	var from time.Time
+	if match[1] != "MINVALUE" {
+		var err error
+		if from, err = parseBoundTime(strings.Trim(match[1], "'")); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	to, err := parseBoundTime(match[2])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+func parseBoundTime(value string) (time.Time, error) {
+	// Fractional seconds are accepted without appearing in the layouts
+	for _, layout := range []string{"2006-01-02 15:04:05-07", "2006-01-02 15:04:05-07:00", "2006-01-02 15:04:05-07:00:00"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid partition bound time %q", value)
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionInterval_AlignAndNext(t *testing.T) {
+	// A Wednesday evening in New York is already Thursday in UTC
+	local := time.Date(2026, 3, 11, 21, 30, 0, 0, time.FixedZone("EST", -5*3600))
+
+	assert.Equal(t, time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), PartitionDaily.Align(local))
+	assert.Equal(t, time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), PartitionDaily.Next(local))
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), PartitionWeekly.Align(local), "Weeks start on Monday")
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), PartitionWeekly.Next(local))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), PartitionMonthly.Align(local))
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), PartitionMonthly.Next(local))
+
+	sunday := time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), PartitionWeekly.Align(sunday))
+}
+
+func TestParsePartitionIntervals(t *testing.T) {
+	intervals, err := ParsePartitionIntervals("price_feeds=day, candles=month")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]PartitionInterval{"price_feeds": PartitionDaily, "candles": PartitionMonthly}, intervals)
+
+	for _, spec := range []string{"symbols=day", "candles=year", "candles"} {
+		_, err := ParsePartitionIntervals(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParsePartitionBound(t *testing.T) {
+	from, to, err := parsePartitionBound("FOR VALUES FROM ('2026-03-16 00:00:00+00') TO ('2026-03-17 00:00:00+00')")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC), to)
+
+	// Bounds are rendered in the session time zone
+	from, to, err = parsePartitionBound("FOR VALUES FROM (MINVALUE) TO ('2026-03-17 05:30:00.5+05:30')")
+	require.NoError(t, err)
+	assert.True(t, from.IsZero())
+	assert.Equal(t, time.Date(2026, 3, 17, 0, 0, 0, 500000000, time.UTC), to)
+
+	_, _, err = parsePartitionBound("DEFAULT")
+	assert.Error(t, err)
+	_, _, err = parsePartitionBound("FOR VALUES FROM ('2026-03-16 00:00:00+00') TO (MAXVALUE)")
+	assert.Error(t, err)
+}
+
+func TestBoundLiteral_RoundTrips(t *testing.T) {
+	bound := time.Date(2026, 3, 16, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	literal := boundLiteral(bound)
+	assert.Equal(t, "'2026-03-15 23:00:00+00'", literal)
+
+	_, to, err := parsePartitionBound("FOR VALUES FROM (MINVALUE) TO (" + literal + ")")
+	require.NoError(t, err)
+	assert.True(t, bound.Equal(to))
+}
+
+func TestLegacyIndexName_StaysWithinIdentifierLimit(t *testing.T) {
+	assert.Equal(t, "idx_price_feeds_symbol_legacy", legacyIndexName("idx_price_feeds_symbol"))
+
+	long := legacyIndexName(strings.Repeat("x", 63))
+	assert.Len(t, long, 63)
+	assert.True(t, strings.HasSuffix(long, "_legacy"))
+}
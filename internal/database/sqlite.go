@@ -0,0 +1,255 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/config"
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDB stores price feeds, candles, snapshots and symbols in one SQLite file for
+// deployments without PostgreSQL. SQLite serialises writers, so the pool holds a single
+// connection, which also keeps an in-memory database alive between statements.
+type SQLiteDB struct {
+	DB     *sql.DB
+	dsn    string
+	config *config.Config
+	logger *logrus.Logger
+}
+
+// IsSQLiteURL reports whether url selects the SQLite backend, e.g. "sqlite:///var/lib/md.db",
+// "sqlite://md.db" for a path relative to the working directory, or "sqlite::memory:"
+func IsSQLiteURL(url string) bool {
+	return strings.HasPrefix(url, "sqlite:")
+}
+
+func NewSQLiteDB(cfg *config.Config, logger *logrus.Logger) (*SQLiteDB, error) {
+	if !IsSQLiteURL(cfg.DatabaseURL) {
+		return nil, fmt.Errorf("SQLite URL is required")
+	}
+
+	// Anything after the path, such as ?_pragma=..., is passed to the driver
+	dsn := strings.TrimPrefix(strings.TrimPrefix(cfg.DatabaseURL, "sqlite:"), "//")
+	if dsn == "" {
+		return nil, fmt.Errorf("SQLite URL %q has no path", cfg.DatabaseURL)
+	}
+
+	return &SQLiteDB{
+		dsn:    dsn,
+		config: cfg,
+		logger: logger,
+	}, nil
+}
+
+func (s *SQLiteDB) Connect(ctx context.Context) error {
+	db, err := sql.Open("sqlite", s.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	// WAL lets readers in other processes work alongside the writer; in-memory databases keep
+	// their own journal mode
+	for _, pragma := range []string{
+		"PRAGMA busy_timeout = 5000",
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA synchronous = NORMAL",
+	} {
+		if _, err := db.ExecContext(ctx, pragma); err != nil {
+			db.Close()
+			return fmt.Errorf("failed to configure SQLite database: %w", err)
+		}
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to ping SQLite database: %w", err)
+	}
+
+	s.DB = db
+	s.logger.WithField("path", s.dsn).Info("SQLite connected successfully")
+	return nil
+}
+
+func (s *SQLiteDB) Disconnect(ctx context.Context) error {
+	if s.DB != nil {
+		if err := s.DB.Close(); err != nil {
+			return fmt.Errorf("failed to close SQLite database: %w", err)
+		}
+		s.logger.Info("SQLite disconnected")
+	}
+	return nil
+}
+
+func (s *SQLiteDB) HealthCheck(ctx context.Context) error {
+	if s.DB == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+	return s.DB.PingContext(ctx)
+}
+
+// sqliteMigrations mirror the PostgreSQL tables the SQLite repositories use. Decimals are
+// stored as text so no precision is lost, and times as fixed-width UTC text, which sorts
+// and compares in time order. Checks cast to REAL; rounding preserves order, so they never
+// reject a row PostgreSQL would accept.
+var sqliteMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "market data tables",
+		SQL: `
+CREATE TABLE IF NOT EXISTS price_feeds (
+    feed_id TEXT PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    price TEXT NOT NULL,
+    bid TEXT,
+    ask TEXT,
+    volume_24h TEXT,
+    source TEXT NOT NULL DEFAULT 'simulator',
+    timestamp TEXT NOT NULL,
+    metadata TEXT,
+    idempotency_key TEXT UNIQUE,
+    sequence INTEGER,
+    received_at TEXT,
+    persisted_at TEXT,
+
+    CONSTRAINT positive_price CHECK (CAST(price AS REAL) > 0),
+    CONSTRAINT positive_bid CHECK (bid IS NULL OR CAST(bid AS REAL) > 0),
+    CONSTRAINT positive_ask CHECK (ask IS NULL OR CAST(ask AS REAL) > 0),
+    CONSTRAINT positive_volume CHECK (volume_24h IS NULL OR CAST(volume_24h AS REAL) >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_feeds_symbol_timestamp ON price_feeds(symbol, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_price_feeds_timestamp ON price_feeds(timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_price_feeds_source_received_at ON price_feeds(source, received_at);
+
+CREATE TABLE IF NOT EXISTS candles (
+    candle_id TEXT PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    interval TEXT NOT NULL,
+    open TEXT NOT NULL,
+    high TEXT NOT NULL,
+    low TEXT NOT NULL,
+    close TEXT NOT NULL,
+    volume TEXT NOT NULL DEFAULT '0',
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    num_trades INTEGER DEFAULT 0,
+    metadata TEXT,
+
+    CONSTRAINT positive_ohlc CHECK (CAST(open AS REAL) > 0 AND CAST(high AS REAL) > 0 AND CAST(low AS REAL) > 0 AND CAST(close AS REAL) > 0),
+    CONSTRAINT valid_high_low CHECK (CAST(high AS REAL) >= CAST(low AS REAL)),
+    CONSTRAINT high_gte_open_close CHECK (CAST(high AS REAL) >= CAST(open AS REAL) AND CAST(high AS REAL) >= CAST(close AS REAL)),
+    CONSTRAINT low_lte_open_close CHECK (CAST(low AS REAL) <= CAST(open AS REAL) AND CAST(low AS REAL) <= CAST(close AS REAL)),
+    CONSTRAINT positive_volume CHECK (CAST(volume AS REAL) >= 0),
+    CONSTRAINT non_negative_trades CHECK (num_trades >= 0),
+    CONSTRAINT unique_symbol_interval_time UNIQUE (symbol, interval, start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_candles_start_time ON candles(start_time DESC);
+
+CREATE TABLE IF NOT EXISTS market_snapshots (
+    snapshot_id TEXT PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    last_price TEXT NOT NULL,
+    bid TEXT,
+    ask TEXT,
+    spread TEXT,
+    volume_24h TEXT,
+    price_change_24h TEXT,
+    price_change_percent_24h TEXT,
+    timestamp TEXT NOT NULL,
+    metadata TEXT,
+
+    CONSTRAINT positive_last_price CHECK (CAST(last_price AS REAL) > 0),
+    CONSTRAINT positive_spread CHECK (spread IS NULL OR CAST(spread AS REAL) >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_snapshots_symbol_timestamp ON market_snapshots(symbol, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_snapshots_timestamp ON market_snapshots(timestamp DESC);
+
+CREATE TABLE IF NOT EXISTS symbols (
+    symbol_id TEXT PRIMARY KEY,
+    symbol TEXT NOT NULL UNIQUE,
+    base_currency TEXT NOT NULL,
+    quote_currency TEXT NOT NULL,
+    display_name TEXT,
+    is_active INTEGER NOT NULL DEFAULT 1,
+    min_price_movement TEXT,
+    min_order_size TEXT,
+    max_order_size TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    metadata TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbols_active ON symbols(is_active);
+CREATE INDEX IF NOT EXISTS idx_symbols_base_currency ON symbols(base_currency);
+`,
+	},
+}
+
+func (s *SQLiteDB) Migrate(ctx context.Context) error {
+	if s.DB == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+
+	if _, err := s.DB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied := 0
+	for _, m := range sqliteMigrations {
+		ok, err := s.applyMigration(ctx, m)
+		if err != nil {
+			return err
+		}
+		if ok {
+			applied++
+			s.logger.WithFields(logrus.Fields{
+				"version":     m.Version,
+				"description": m.Description,
+			}).Info("Applied SQLite migration")
+		}
+	}
+
+	s.logger.WithField("applied", applied).Info("SQLite migrations complete")
+	return nil
+}
+
+func (s *SQLiteDB) applyMigration(ctx context.Context, m Migration) (bool, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin migration %d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.Version).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check migration %d: %w", m.Version, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return false, fmt.Errorf("failed to apply migration %d (%s): %w", m.Version, m.Description, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, description) VALUES ($1, $2)", m.Version, m.Description); err != nil {
+		return false, fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+	}
+	return true, nil
+}
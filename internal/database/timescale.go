@@ -0,0 +1,478 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrTimescaleUnavailable is returned by Timescale.Setup when the server cannot load the
+// timescaledb extension, so callers can fall back to plain PostgreSQL
+var ErrTimescaleUnavailable = errors.New("timescaledb extension unavailable")
+
+// timescaleMigration is the first migration whose change triggers can notify from chunks
+const timescaleMigration = 11
+
+// compressSegmentBy groups compressed rows by the columns queries filter on
+var compressSegmentBy = map[string]string{
+	"price_feeds":      "symbol, source",
+	"candles":          "symbol, interval",
+	"market_snapshots": "symbol",
+}
+
+type TimescaleOptions struct {
+	ChunkInterval   time.Duration            // Time covered by one chunk; defaults to 1 day
+	CompressAfter   time.Duration            // Age at which chunks are compressed; 0 disables compression
+	Retention       map[string]time.Duration // Age at which a table's chunks are dropped; tables left out keep their data
+	AggregateSource models.CandleInterval    // Stored interval the aggregates roll up; defaults to 1m
+	Aggregates      []models.CandleInterval  // Coarser intervals kept as continuous aggregates
+	RefreshWindow   time.Duration            // How far back aggregates are refreshed for late candles; defaults to 3 days
+}
+
+// Validate checks that aggregates are whole multiples of the source interval and that
+// retention only names hypertables
+func (o TimescaleOptions) Validate() error {
+	source := o.AggregateSource.Duration()
+	if source == 0 {
+		return fmt.Errorf("unknown aggregate source interval %q", o.AggregateSource)
+	}
+	for _, interval := range o.Aggregates {
+		d := interval.Duration()
+		if d == 0 {
+			return fmt.Errorf("unknown aggregate interval %q", interval)
+		}
+		if d <= source || d%source != 0 {
+			return fmt.Errorf("aggregate interval %s is not a multiple of %s", interval, o.AggregateSource)
+		}
+	}
+	for table, age := range o.Retention {
+		if _, ok := findPartitionedTable(table); !ok {
+			return fmt.Errorf("retention for %s: not a hypertable", table)
+		}
+		if age <= 0 {
+			return fmt.Errorf("retention for %s: age must be positive", table)
+		}
+	}
+	return nil
+}
+
+// AggregateView names the continuous aggregate holding candles of interval
+func AggregateView(interval models.CandleInterval) string {
+	return "candles_" + string(interval)
+}
+
+// Timescale turns price_feeds, candles and market_snapshots into TimescaleDB hypertables
+// with native compression and retention, and keeps coarser candles as continuous
+// aggregates of the source interval. Compressed chunks accept late inserts and upserts
+// from TimescaleDB 2.11.
+type Timescale struct {
+	db      *sql.DB
+	schema  string
+	options TimescaleOptions
+	logger  *logrus.Logger
+}
+
+// NewTimescale creates a manager for this database's schema; call it after Connect
+func (p *PostgresDB) NewTimescale(options TimescaleOptions) *Timescale {
+	if options.ChunkInterval <= 0 {
+		options.ChunkInterval = 24 * time.Hour
+	}
+	if options.AggregateSource == "" {
+		options.AggregateSource = models.Interval1m
+	}
+	if options.RefreshWindow <= 0 {
+		options.RefreshWindow = 72 * time.Hour
+	}
+
+	return &Timescale{
+		db:      p.DB,
+		schema:  p.config.SchemaName,
+		options: options,
+		logger:  p.logger,
+	}
+}
+
+func (t *Timescale) qualified(name string) string {
+	return pq.QuoteIdentifier(t.schema) + "." + pq.QuoteIdentifier(name)
+}
+
+// Setup loads the extension, converts tables that are not hypertables yet, and applies
+// the configured compression, retention and aggregate policies. Existing rows are moved
+// into chunks, which holds an exclusive lock on each table while it is converted.
+func (t *Timescale) Setup(ctx context.Context) error {
+	if t.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+	if err := t.options.Validate(); err != nil {
+		return err
+	}
+
+	var version int
+	err := t.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM `+t.qualified("schema_migrations")).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version < timescaleMigration {
+		return fmt.Errorf("TimescaleDB requires migration %d, schema is at %d", timescaleMigration, version)
+	}
+
+	// Policies are replaced rather than updated, so replicas must not set up concurrently
+	conn, err := t.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	lockKey := "timescale:" + t.schema
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", lockKey); err != nil {
+		return fmt.Errorf("failed to lock TimescaleDB setup: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", lockKey)
+
+	if err := t.loadExtension(ctx, conn); err != nil {
+		t.logger.WithError(err).Warn("TimescaleDB is not available")
+		return err
+	}
+
+	steps := []struct {
+		name string
+		run  func(context.Context, *sql.Conn) error
+	}{
+		{"create hypertables", t.createHypertables},
+		{"apply compression", t.applyCompression},
+		{"apply retention", t.applyRetention},
+		{"create continuous aggregates", t.createAggregates},
+	}
+	for _, step := range steps {
+		if err := step.run(ctx, conn); err != nil {
+			t.logger.WithError(err).Errorf("Failed to %s", step.name)
+			return fmt.Errorf("failed to %s: %w", step.name, err)
+		}
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"schema":         t.schema,
+		"chunk_interval": t.options.ChunkInterval,
+		"compress_after": t.options.CompressAfter,
+		"aggregates":     t.options.Aggregates,
+	}).Info("TimescaleDB set up")
+	return nil
+}
+
+func (t *Timescale) loadExtension(ctx context.Context, conn *sql.Conn) error {
+	var available bool
+	if err := conn.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'timescaledb')`,
+	).Scan(&available); err != nil {
+		return fmt.Errorf("failed to check extensions: %w", err)
+	}
+	if !available {
+		return fmt.Errorf("%w: not installed on the server", ErrTimescaleUnavailable)
+	}
+
+	// Fails unless the library is in shared_preload_libraries
+	if _, err := conn.ExecContext(ctx, `CREATE EXTENSION IF NOT EXISTS timescaledb`); err != nil {
+		return fmt.Errorf("%w: %v", ErrTimescaleUnavailable, err)
+	}
+	return nil
+}
+
+func (t *Timescale) createHypertables(ctx context.Context, conn *sql.Conn) error {
+	for _, table := range partitionedTables {
+		var hypertable, partitioned bool
+		if err := conn.QueryRowContext(ctx, `SELECT
+			EXISTS (SELECT 1 FROM timescaledb_information.hypertables WHERE hypertable_schema = $1 AND hypertable_name = $2),
+			EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = $3::regclass)`,
+			t.schema, table.name, t.qualified(table.name),
+		).Scan(&hypertable, &partitioned); err != nil {
+			return err
+		}
+		if hypertable {
+			continue
+		}
+		if partitioned {
+			return fmt.Errorf("%s is range-partitioned; hypertables replace native partitioning", table.name)
+		}
+
+		if err := t.convert(ctx, conn, table); err != nil {
+			return fmt.Errorf("%s: %w", table.name, err)
+		}
+	}
+	return nil
+}
+
+// convert rekeys table on its ID and time column, which unique keys on a hypertable must
+// include, and moves its rows into chunks
+func (t *Timescale) convert(ctx context.Context, conn *sql.Conn, table partitionedTable) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qualified := t.qualified(table.name)
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE `+qualified+` IN ACCESS EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	var primaryKey string
+	if err := tx.QueryRowContext(ctx,
+		`SELECT conname FROM pg_constraint WHERE conrelid = $1::regclass AND contype = 'p'`, qualified,
+	).Scan(&primaryKey); err != nil {
+		return fmt.Errorf("failed to find primary key: %w", err)
+	}
+
+	// Chunks live in TimescaleDB's schema, so the trigger names the table and channel schema
+	trigger := pq.QuoteIdentifier(table.name + "_notify_change")
+	statements := []string{
+		fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s, ADD PRIMARY KEY (%s, %s)`,
+			qualified, pq.QuoteIdentifier(primaryKey), pq.QuoteIdentifier(table.idColumn), pq.QuoteIdentifier(table.column)),
+		`DROP TRIGGER IF EXISTS ` + trigger + ` ON ` + qualified,
+		fmt.Sprintf(`CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s
+    FOR EACH ROW EXECUTE FUNCTION %s.notify_change(%s, %s, %s)`,
+			trigger, qualified, pq.QuoteIdentifier(t.schema),
+			pq.QuoteLiteral(table.idColumn), pq.QuoteLiteral(table.name), pq.QuoteLiteral(t.schema)),
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`SELECT create_hypertable($1::regclass, $2::name, chunk_time_interval => $3::interval, migrate_data => true)`,
+		qualified, table.column, pgInterval(t.options.ChunkInterval),
+	); err != nil {
+		return fmt.Errorf("failed to create hypertable: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"table":          table.name,
+		"chunk_interval": t.options.ChunkInterval,
+	}).Info("Converted table to hypertable")
+	return nil
+}
+
+// applyCompression enables compression once and replaces each table's policy, so a
+// changed CompressAfter takes effect; with compression disabled only the policy is removed
+func (t *Timescale) applyCompression(ctx context.Context, conn *sql.Conn) error {
+	for _, table := range partitionedTables {
+		qualified := t.qualified(table.name)
+		if _, err := conn.ExecContext(ctx,
+			`SELECT remove_compression_policy($1::regclass, if_exists => true)`, qualified); err != nil {
+			return err
+		}
+		if t.options.CompressAfter <= 0 {
+			continue
+		}
+
+		var enabled bool
+		if err := conn.QueryRowContext(ctx, `SELECT compression_enabled FROM timescaledb_information.hypertables
+			WHERE hypertable_schema = $1 AND hypertable_name = $2`, t.schema, table.name,
+		).Scan(&enabled); err != nil {
+			return err
+		}
+		if !enabled {
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf(
+				`ALTER TABLE %s SET (timescaledb.compress, timescaledb.compress_segmentby = %s, timescaledb.compress_orderby = %s)`,
+				qualified, pq.QuoteLiteral(compressSegmentBy[table.name]), pq.QuoteLiteral(table.column+" DESC"),
+			)); err != nil {
+				return err
+			}
+		}
+
+		if _, err := conn.ExecContext(ctx,
+			`SELECT add_compression_policy($1::regclass, compress_after => $2::interval)`,
+			qualified, pgInterval(t.options.CompressAfter),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Timescale) applyRetention(ctx context.Context, conn *sql.Conn) error {
+	for _, table := range partitionedTables {
+		qualified := t.qualified(table.name)
+		if _, err := conn.ExecContext(ctx,
+			`SELECT remove_retention_policy($1::regclass, if_exists => true)`, qualified); err != nil {
+			return err
+		}
+
+		age, ok := t.options.Retention[table.name]
+		if !ok {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx,
+			`SELECT add_retention_policy($1::regclass, drop_after => $2::interval)`, qualified, pgInterval(age),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createAggregates creates missing continuous aggregates and replaces their refresh
+// policies. The policy only refreshes its start_offset window, so a new aggregate is
+// materialized once for everything older than that.
+func (t *Timescale) createAggregates(ctx context.Context, conn *sql.Conn) error {
+	for _, interval := range t.options.Aggregates {
+		name := AggregateView(interval)
+		view := t.qualified(name)
+
+		var exists bool
+		if err := conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM timescaledb_information.continuous_aggregates
+			WHERE view_schema = $1 AND view_name = $2)`, t.schema, name,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, t.aggregateSQL(interval)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		bucket := interval.Duration()
+		start := t.options.RefreshWindow
+		if minimum := 3 * bucket; start < minimum {
+			start = minimum
+		}
+		schedule := bucket
+		if schedule > time.Hour {
+			schedule = time.Hour
+		}
+
+		if !exists {
+			if _, err := conn.ExecContext(ctx, t.refreshHistorySQL(interval, start)); err != nil {
+				return fmt.Errorf("%s: failed to materialize history: %w", name, err)
+			}
+		}
+
+		if _, err := conn.ExecContext(ctx,
+			`SELECT remove_continuous_aggregate_policy($1::regclass, if_exists => true)`, view); err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, `SELECT add_continuous_aggregate_policy($1::regclass,
+			start_offset => $2::interval, end_offset => $3::interval, schedule_interval => $4::interval)`,
+			view, pgInterval(start), pgInterval(bucket), pgInterval(schedule),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggregateSQL defines the continuous aggregate rolling source candles up into interval.
+// Real-time aggregation covers the buckets after the last refresh; older buckets are only
+// visible once materialized.
+func (t *Timescale) aggregateSQL(interval models.CandleInterval) string {
+	bucket := fmt.Sprintf("time_bucket(%s, start_time)", pgIntervalLiteral(interval.Duration()))
+	return fmt.Sprintf(`CREATE MATERIALIZED VIEW IF NOT EXISTS %s
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT symbol,
+    %s AS bucket,
+    first(open, start_time) AS open,
+    max(high) AS high,
+    min(low) AS low,
+    last(close, start_time) AS close,
+    sum(volume) AS volume,
+    sum(num_trades) AS num_trades,
+    count(*) AS candles
+FROM %s
+WHERE interval = %s
+GROUP BY symbol, %s
+WITH NO DATA`,
+		t.qualified(AggregateView(interval)), bucket, t.qualified("candles"),
+		pq.QuoteLiteral(string(t.options.AggregateSource)), bucket)
+}
+
+// refreshHistorySQL materializes the aggregate's buckets older than the policy's window.
+// The procedure cannot run in a transaction block, so it takes no placeholders.
+func (t *Timescale) refreshHistorySQL(interval models.CandleInterval, window time.Duration) string {
+	return fmt.Sprintf(`CALL refresh_continuous_aggregate(%s, NULL, now() - %s)`,
+		pq.QuoteLiteral(t.qualified(AggregateView(interval))), pgIntervalLiteral(window))
+}
+
+// DropBefore drops table's chunks that end at or before cutoff, returning the rows they
+// held and the end of the newest one dropped, or zero if none were
+func (t *Timescale) DropBefore(ctx context.Context, table string, cutoff time.Time) (int64, time.Time, error) {
+	if t.db == nil {
+		return 0, time.Time{}, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	rows, err := t.db.QueryContext(ctx, `SELECT chunk_schema, chunk_name, range_end
+		FROM timescaledb_information.chunks
+		WHERE hypertable_schema = $1 AND hypertable_name = $2 AND range_end <= $3
+		ORDER BY range_end`, t.schema, table, cutoff)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to list %s chunks: %w", table, err)
+	}
+
+	type chunk struct {
+		name string
+		end  time.Time
+	}
+	var chunks []chunk
+	for rows.Next() {
+		var schema, name string
+		var end time.Time
+		if err := rows.Scan(&schema, &name, &end); err != nil {
+			rows.Close()
+			return 0, time.Time{}, fmt.Errorf("failed to scan %s chunk: %w", table, err)
+		}
+		chunks = append(chunks, chunk{pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(name), end})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to list %s chunks: %w", table, err)
+	}
+	if len(chunks) == 0 {
+		return 0, time.Time{}, nil
+	}
+
+	// Counted before the drop; compressed chunks are counted through decompression
+	var count int64
+	for _, chunk := range chunks {
+		var n int64
+		if err := t.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+chunk.name).Scan(&n); err != nil {
+			return 0, time.Time{}, fmt.Errorf("failed to count chunk %s: %w", chunk.name, err)
+		}
+		count += n
+	}
+
+	boundary := chunks[len(chunks)-1].end
+	if _, err := t.db.ExecContext(ctx,
+		`SELECT drop_chunks($1::regclass, older_than => $2::timestamptz)`, t.qualified(table), boundary,
+	); err != nil {
+		t.logger.WithError(err).WithField("table", table).Error("Failed to drop chunks")
+		return 0, time.Time{}, fmt.Errorf("failed to drop %s chunks: %w", table, err)
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"table":  table,
+		"chunks": len(chunks),
+		"until":  boundary,
+		"rows":   count,
+	}).Info("Dropped chunks")
+	return count, boundary, nil
+}
+
+// pgInterval renders d as an interval value for placeholders
+func pgInterval(d time.Duration) string {
+	return fmt.Sprintf("%d seconds", int64(d/time.Second))
+}
+
+// pgIntervalLiteral renders d as an interval literal for statements that cannot take
+// placeholders, such as view definitions
+func pgIntervalLiteral(d time.Duration) string {
+	return "INTERVAL " + pq.QuoteLiteral(pgInterval(d))
+}
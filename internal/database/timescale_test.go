@@ -0,0 +1,45 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimescaleOptions_Validate(t *testing.T) {
+	options := TimescaleOptions{
+		AggregateSource: models.Interval5m,
+		Aggregates:      []models.CandleInterval{models.Interval15m, models.Interval1h},
+		Retention:       map[string]time.Duration{"price_feeds": 24 * time.Hour},
+	}
+	assert.NoError(t, options.Validate())
+
+	invalid := []TimescaleOptions{
+		{AggregateSource: "2m"},
+		{AggregateSource: models.Interval5m, Aggregates: []models.CandleInterval{models.Interval1m}},
+		{AggregateSource: models.Interval15m, Aggregates: []models.CandleInterval{"3h"}},
+		{AggregateSource: models.Interval1m, Retention: map[string]time.Duration{"symbols": time.Hour}},
+	}
+	for _, options := range invalid {
+		assert.Error(t, options.Validate(), "%+v", options)
+	}
+}
+
+func TestTimescale_AggregateSQL(t *testing.T) {
+	ts := &Timescale{schema: "market_data", options: TimescaleOptions{AggregateSource: models.Interval1m}}
+
+	statement := ts.aggregateSQL(models.Interval4h)
+	assert.Contains(t, statement, `CREATE MATERIALIZED VIEW IF NOT EXISTS "market_data"."candles_4h"`)
+	assert.Contains(t, statement, `time_bucket(INTERVAL '14400 seconds', start_time) AS bucket`)
+	assert.Contains(t, statement, `WHERE interval = '1m'`)
+	assert.Contains(t, statement, "timescaledb.continuous")
+}
+
+func TestTimescale_RefreshHistorySQL(t *testing.T) {
+	ts := &Timescale{schema: "market_data", options: TimescaleOptions{AggregateSource: models.Interval1m}}
+
+	statement := ts.refreshHistorySQL(models.Interval1h, 72*time.Hour)
+	assert.Equal(t, `CALL refresh_continuous_aggregate('"market_data"."candles_1h"', NULL, now() - INTERVAL '259200 seconds')`, statement)
+}
@@ -0,0 +1,306 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed MAJOR.MINOR.PATCH[-prerelease] version; build metadata is ignored
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Parse accepts "1.2.3", "v1.2.3", "1.2" and "1" (missing parts are zero)
+func Parse(s string) (Version, error) {
+	v, _, err := parsePartial(s)
+	return v, err
+}
+
+// parsePartial is Parse that also reports how many of MAJOR.MINOR.PATCH were given
+func parsePartial(s string) (Version, int, error) {
+	var v Version
+
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(raw, '+'); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.IndexByte(raw, '-'); i >= 0 {
+		v.Prerelease = raw[i+1:]
+		raw = raw[:i]
+	}
+
+	parts := strings.Split(raw, ".")
+	if raw == "" || len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("invalid version: %q", s)
+	}
+
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, 0, fmt.Errorf("invalid version: %q", s)
+		}
+		*fields[i] = n
+	}
+
+	return v, len(parts), nil
+}
+
+// Compare returns -1, 0 or 1; a prerelease sorts before its release and prereleases
+// compare identifier by identifier as in semver 2.0.0 (rc.9 < rc.10)
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(v.Prerelease, o.Prerelease)
+	}
+}
+
+// comparePrerelease orders dot-separated identifiers: numeric ones numerically and
+// below alphanumeric ones, and a shorter list first when it is a prefix of the other
+func comparePrerelease(a, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		x, xErr := strconv.Atoi(left[i])
+		y, yErr := strconv.Atoi(right[i])
+		switch {
+		case xErr == nil && yErr == nil:
+			if x != y {
+				return compareInts(x, y)
+			}
+		case xErr == nil:
+			return -1
+		case yErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(left[i], right[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(left), len(right))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// release drops the prerelease, leaving the MAJOR.MINOR.PATCH tuple
+func (v Version) release() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default: // "<="
+		return cmp <= 0
+	}
+}
+
+// Constraint is a set of alternatives ("||"), each a conjunction of comparators
+type Constraint struct {
+	alternatives [][]comparator
+}
+
+// ParseConstraint understands comparators (=, !=, >, >=, <, <=), caret (^1.2),
+// tilde (~1.2.3), wildcards (1.x, 1.2.*), partial versions (1.2 means 1.2.x) and "||"
+// between space-separated ranges. An empty constraint matches every release.
+func ParseConstraint(s string) (*Constraint, error) {
+	constraint := &Constraint{}
+	if strings.TrimSpace(s) == "" {
+		constraint.alternatives = [][]comparator{nil}
+		return constraint, nil
+	}
+
+	for _, alternative := range strings.Split(s, "||") {
+		terms := strings.Fields(alternative)
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty alternative", s)
+		}
+
+		var comparators []comparator
+		for _, term := range terms {
+			parsed, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			comparators = append(comparators, parsed...)
+		}
+		constraint.alternatives = append(constraint.alternatives, comparators)
+	}
+
+	return constraint, nil
+}
+
+// Check reports whether v satisfies the constraint. As with npm ranges, a prerelease
+// only matches an alternative that names a prerelease of the same MAJOR.MINOR.PATCH,
+// so ">=1.0.0" does not match "2.0.0-alpha".
+func (c *Constraint) Check(v Version) bool {
+	for _, alternative := range c.alternatives {
+		if v.Prerelease != "" && !allowsPrerelease(alternative, v) {
+			continue
+		}
+
+		matched := true
+		for _, comp := range alternative {
+			if !comp.check(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPrerelease reports whether a comparator opts in to prereleases of v's release
+func allowsPrerelease(alternative []comparator, v Version) bool {
+	for _, comp := range alternative {
+		if comp.version.Prerelease != "" && comp.version.release() == v.release() {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckString parses v and checks it, treating unparseable versions as non-matching
+func (c *Constraint) CheckString(v string) bool {
+	version, err := Parse(v)
+	if err != nil {
+		return false
+	}
+	return c.Check(version)
+}
+
+func parseTerm(term string) ([]comparator, error) {
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if strings.HasPrefix(term, op) {
+			v, err := Parse(term[len(op):])
+			if err != nil {
+				return nil, err
+			}
+			return []comparator{{op: op, version: v}}, nil
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(term, "^"):
+		// The upper bound bumps the leftmost non-zero part that was given
+		v, parts, err := parsePartial(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		var upper Version
+		switch {
+		case v.Major > 0 || parts == 1:
+			upper = Version{Major: v.Major + 1}
+		case v.Minor > 0 || parts == 2:
+			upper = Version{Minor: v.Minor + 1}
+		default:
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return []comparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+
+	case strings.HasPrefix(term, "~"):
+		// ~1.2.3 and ~1.2 allow patch changes, ~1 allows minor changes
+		v, parts, err := parsePartial(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := Version{Major: v.Major, Minor: v.Minor + 1}
+		if parts == 1 {
+			upper = Version{Major: v.Major + 1}
+		}
+		return []comparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	}
+
+	return parseWildcard(term)
+}
+
+// parseWildcard handles "*", "1.x", "1.2.*" and bare versions; a full version is an
+// exact match and a partial one is a wildcard over its missing parts
+func parseWildcard(term string) ([]comparator, error) {
+	parts := strings.Split(strings.TrimPrefix(term, "v"), ".")
+	wild := -1
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wild = i
+			break
+		}
+	}
+
+	if wild < 0 {
+		v, given, err := parsePartial(term)
+		if err != nil {
+			return nil, err
+		}
+		if given == 3 || v.Prerelease != "" {
+			return []comparator{{op: "=", version: v}}, nil
+		}
+		wild = given
+	}
+	if wild == 0 {
+		return nil, nil
+	}
+
+	lower, err := Parse(strings.Join(parts[:wild], "."))
+	if err != nil {
+		return nil, err
+	}
+	upper := Version{Major: lower.Major + 1}
+	if wild == 2 {
+		upper = Version{Major: lower.Major, Minor: lower.Minor + 1}
+	}
+	return []comparator{{op: ">=", version: lower}, {op: "<", version: upper}}, nil
+}
@@ -0,0 +1,130 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.2.3-rc.1+build.5")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}, v)
+
+	v, err = Parse("2.1")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 2, Minor: 1}, v)
+
+	_, err = Parse("1.two.3")
+	assert.Error(t, err)
+}
+
+func TestCompare_PrereleaseSortsBeforeRelease(t *testing.T) {
+	rc, _ := Parse("1.0.0-rc.1")
+	release, _ := Parse("1.0.0")
+
+	assert.Equal(t, -1, rc.Compare(release))
+	assert.Equal(t, 1, release.Compare(rc))
+}
+
+func TestCompare_PrereleaseIdentifiers(t *testing.T) {
+	cases := []struct {
+		lower, higher string
+	}{
+		{"1.0.0-rc.9", "1.0.0-rc.10"},
+		{"1.0.0-alpha", "1.0.0-alpha.1"},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta"},
+		{"1.0.0-alpha.beta", "1.0.0-beta"},
+		{"1.0.0-beta.2", "1.0.0-beta.11"},
+		{"1.0.0-beta.11", "1.0.0-rc.1"},
+	}
+
+	for _, tc := range cases {
+		lower, err := Parse(tc.lower)
+		require.NoError(t, err)
+		higher, err := Parse(tc.higher)
+		require.NoError(t, err)
+
+		assert.Equal(t, -1, lower.Compare(higher), "%s < %s", tc.lower, tc.higher)
+		assert.Equal(t, 1, higher.Compare(lower), "%s > %s", tc.higher, tc.lower)
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=1.2.0 <2.0.0", "1.5.3", true},
+		{">=1.2.0 <2.0.0", "2.0.0", false},
+		{"^1.2.0", "1.9.9", true},
+		{"^1.2.0", "2.0.0", false},
+		{"^0.3.1", "0.3.9", true},
+		{"^0.3.1", "0.4.0", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"1.x", "1.7.0", true},
+		{"1.2.*", "1.3.0", false},
+		{"*", "9.9.9", true},
+		{"1.0.0", "1.0.0", true},
+		{"!=1.0.0", "1.0.0", false},
+		{"<1.0.0 || >=3.0.0", "3.1.0", true},
+		{"<1.0.0 || >=3.0.0", "2.0.0", false},
+		{"", "0.0.1", true},
+
+		// Caret on 0.0.x only allows the patch itself
+		{"^0.0.3", "0.0.3", true},
+		{"^0.0.3", "0.0.4", false},
+		{"^0.0", "0.0.9", true},
+		{"^0.0", "0.1.0", false},
+		{"^0", "0.9.0", true},
+		{"^0", "1.0.0", false},
+
+		// Tilde with only a major allows minor changes
+		{"~1", "1.9.0", true},
+		{"~1", "2.0.0", false},
+		{"~1.2", "1.2.9", true},
+		{"~1.2", "1.3.0", false},
+
+		// Bare partial versions are wildcards over the missing parts
+		{"1.2", "1.2.0", true},
+		{"1.2", "1.2.7", true},
+		{"1.2", "1.3.0", false},
+		{"1.2", "1.1.9", false},
+		{"1", "1.9.9", true},
+		{"1", "2.0.0", false},
+
+		// Prereleases compare numerically and only match ranges that opt in
+		{">=1.0.0-rc.9", "1.0.0-rc.10", true},
+		{"<1.0.0-rc.10", "1.0.0-rc.9", true},
+		{">=1.0.0", "2.0.0-alpha", false},
+		{"^1.0.0", "1.5.0-beta", false},
+		{"<2.0.0", "2.0.0-alpha", false},
+		{">=2.0.0-alpha <3.0.0", "2.0.0-beta", true},
+		{">=2.0.0-alpha <3.0.0", "2.1.0-beta", false},
+		{"*", "1.0.0-rc.1", false},
+	}
+
+	for _, tc := range cases {
+		constraint, err := ParseConstraint(tc.constraint)
+		require.NoError(t, err, tc.constraint)
+		assert.Equal(t, tc.want, constraint.CheckString(tc.version),
+			"%q against %q", tc.version, tc.constraint)
+	}
+}
+
+func TestConstraint_UnparseableVersionDoesNotMatch(t *testing.T) {
+	constraint, err := ParseConstraint(">=1.0.0")
+	require.NoError(t, err)
+
+	assert.False(t, constraint.CheckString("latest"))
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	for _, constraint := range []string{">=one", "^1.0.0 ||", "|| <2.0.0", "1.0.0 || || 2.0.0", "   ||  "} {
+		_, err := ParseConstraint(constraint)
+		assert.Error(t, err, constraint)
+	}
+}
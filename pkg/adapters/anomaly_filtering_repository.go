@@ -0,0 +1,197 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/anomaly"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// AnomalyFilteringPriceFeedRepository screens incoming price feeds before they are stored.
+// Feeds that fail a check are flagged in their metadata and stored, held in quarantine, or
+// rejected, depending on the action. Only clean, newly stored feeds extend the filter's
+// price history. In a transaction the filter's state changes are staged and applied after
+// the commit, so a rollback or retry neither pollutes nor double-counts the window.
+type AnomalyFilteringPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	filter      screener
+	stage       *anomaly.Stage
+	action      anomaly.Action
+	quarantine  interfaces.QuarantineRepository
+	afterCommit *afterCommit
+	logger      *logrus.Logger
+}
+
+// screener is the part of anomaly.Filter the repository uses, also met by anomaly.Stage
+type screener interface {
+	Inspect(feed *models.PriceFeed) []models.AnomalyFinding
+	Accept(feed *models.PriceFeed)
+}
+
+func NewAnomalyFilteringPriceFeedRepository(repo interfaces.PriceFeedRepository, filter *anomaly.Filter, action anomaly.Action, quarantine interfaces.QuarantineRepository, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return newAnomalyFilteringPriceFeedRepository(repo, filter, action, quarantine, nil, logger)
+}
+
+func newAnomalyFilteringPriceFeedRepository(repo interfaces.PriceFeedRepository, filter *anomaly.Filter, action anomaly.Action, quarantine interfaces.QuarantineRepository, hooks *afterCommit, logger *logrus.Logger) *AnomalyFilteringPriceFeedRepository {
+	r := &AnomalyFilteringPriceFeedRepository{
+		PriceFeedRepository: repo,
+		filter:              filter,
+		action:              action,
+		quarantine:          quarantine,
+		afterCommit:         hooks,
+		logger:              logger,
+	}
+	if hooks != nil {
+		r.stage = filter.Stage()
+		r.filter = r.stage
+	}
+	return r
+}
+
+// commitStage applies this write's filter changes once the transaction commits
+func (r *AnomalyFilteringPriceFeedRepository) commitStage(ctx context.Context) {
+	if r.stage == nil {
+		return
+	}
+	r.afterCommit.do(ctx, func(context.Context) {
+		r.stage.Commit()
+	})
+}
+
+func (r *AnomalyFilteringPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	r.commitStage(ctx)
+
+	findings := r.filter.Inspect(feed)
+	if len(findings) > 0 {
+		if result, handled, err := r.handle(ctx, feed, findings); handled {
+			if result.Rejected {
+				return result, fmt.Errorf("%w: %s", interfaces.ErrAnomalousPrice, describeFindings(findings))
+			}
+			return result, err
+		}
+	}
+
+	result, err := r.PriceFeedRepository.Create(ctx, feed)
+	if err != nil {
+		return result, err
+	}
+
+	if len(findings) == 0 && !result.Duplicate {
+		r.filter.Accept(feed)
+	}
+	return result, nil
+}
+
+// CreateBatch stores the feeds that pass, or are only flagged, in one batch. Rejected
+// feeds are reported in their results rather than failing the batch.
+func (r *AnomalyFilteringPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	r.commitStage(ctx)
+
+	results := make([]interfaces.CreateResult, len(feeds))
+	clean := make([]bool, len(feeds))
+	var (
+		pending []*models.PriceFeed
+		indexes []int
+	)
+
+	for i, feed := range feeds {
+		findings := r.filter.Inspect(feed)
+		if len(findings) > 0 {
+			result, handled, err := r.handle(ctx, feed, findings)
+			if err != nil {
+				return nil, err
+			}
+			if handled {
+				results[i] = result
+				continue
+			}
+		}
+		clean[i] = len(findings) == 0
+		pending = append(pending, feed)
+		indexes = append(indexes, i)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	stored, err := r.PriceFeedRepository.CreateBatch(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, result := range stored {
+		i := indexes[j]
+		results[i] = result
+		if clean[i] && !result.Duplicate && !result.Rejected {
+			r.filter.Accept(feeds[i])
+		}
+	}
+	return results, nil
+}
+
+// handle applies the action to an anomalous feed. It reports handled when the feed must
+// not be passed on; flagged feeds are annotated and passed on.
+func (r *AnomalyFilteringPriceFeedRepository) handle(ctx context.Context, feed *models.PriceFeed, findings []models.AnomalyFinding) (interfaces.CreateResult, bool, error) {
+	logger := r.logger.WithFields(logrus.Fields{
+		"symbol":   feed.Symbol,
+		"source":   feed.Source,
+		"price":    feed.Price,
+		"action":   r.action,
+		"findings": describeFindings(findings),
+	})
+	logger.Warn("Anomalous price feed detected")
+
+	switch r.action {
+	case anomaly.ActionReject:
+		return interfaces.CreateResult{Rejected: true}, true, nil
+	case anomaly.ActionFlag:
+		if err := flagFeed(feed, findings); err != nil {
+			logger.WithError(err).Warn("Failed to flag price feed metadata")
+		}
+		return interfaces.CreateResult{}, false, nil
+	default:
+		quarantined := &models.QuarantinedFeed{Feed: *feed, Findings: findings}
+		if err := r.quarantine.Create(ctx, quarantined); err != nil {
+			return interfaces.CreateResult{}, true, err
+		}
+		return interfaces.CreateResult{Quarantined: true}, true, nil
+	}
+}
+
+// flagFeed adds the findings to the feed's metadata under "anomalies"; metadata that is
+// not a JSON object is left as is
+func flagFeed(feed *models.PriceFeed, findings []models.AnomalyFinding) error {
+	metadata := make(map[string]json.RawMessage)
+	if len(feed.Metadata) > 0 {
+		if err := json.Unmarshal(feed.Metadata, &metadata); err != nil {
+			return fmt.Errorf("metadata is not a JSON object: %w", err)
+		}
+	}
+
+	anomalies, err := json.Marshal(findings)
+	if err != nil {
+		return err
+	}
+	metadata["anomalies"] = anomalies
+
+	flagged, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	feed.Metadata = flagged
+	return nil
+}
+
+func describeFindings(findings []models.AnomalyFinding) string {
+	reasons := make([]string, len(findings))
+	for i, finding := range findings {
+		reasons[i] = finding.Check + ": " + finding.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
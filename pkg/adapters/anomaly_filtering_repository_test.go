@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/anomaly"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingQuarantineRepository records quarantined feeds
+type recordingQuarantineRepository struct {
+	interfaces.QuarantineRepository
+	quarantined []*models.QuarantinedFeed
+}
+
+func (r *recordingQuarantineRepository) Create(ctx context.Context, quarantined *models.QuarantinedFeed) error {
+	r.quarantined = append(r.quarantined, quarantined)
+	return nil
+}
+
+func pricedFeed(price string) *models.PriceFeed {
+	return &models.PriceFeed{Symbol: "BTC-USD", Source: "coinbase", Price: decimal.RequireFromString(price)}
+}
+
+func newAnomalyFilteringRepository(action anomaly.Action) (interfaces.PriceFeedRepository, *stubPriceFeedRepository, *recordingQuarantineRepository) {
+	inner := &stubPriceFeedRepository{}
+	quarantine := &recordingQuarantineRepository{}
+	filter := anomaly.NewFilter(10, 0, anomaly.JumpDetector{MaxChange: 0.1})
+	return NewAnomalyFilteringPriceFeedRepository(inner, filter, action, quarantine, newQuietLogger()), inner, quarantine
+}
+
+func TestAnomalyFilteringPriceFeedRepository_Quarantines(t *testing.T) {
+	repo, inner, quarantine := newAnomalyFilteringRepository(anomaly.ActionQuarantine)
+
+	_, err := repo.Create(context.Background(), pricedFeed("100"))
+	require.NoError(t, err)
+	result, err := repo.Create(context.Background(), pricedFeed("150"))
+	require.NoError(t, err)
+
+	assert.True(t, result.Quarantined)
+	assert.Len(t, inner.created, 1)
+	require.Len(t, quarantine.quarantined, 1)
+	assert.Equal(t, "jump", quarantine.quarantined[0].Findings[0].Check)
+
+	// The quarantined print did not become the reference price
+	_, err = repo.Create(context.Background(), pricedFeed("105"))
+	require.NoError(t, err)
+	assert.Len(t, inner.created, 2)
+}
+
+func TestAnomalyFilteringPriceFeedRepository_Rejects(t *testing.T) {
+	repo, inner, _ := newAnomalyFilteringRepository(anomaly.ActionReject)
+	_, err := repo.Create(context.Background(), pricedFeed("100"))
+	require.NoError(t, err)
+
+	result, err := repo.Create(context.Background(), pricedFeed("50"))
+	assert.True(t, errors.Is(err, interfaces.ErrAnomalousPrice))
+	assert.True(t, result.Rejected)
+
+	results, err := repo.CreateBatch(context.Background(), []*models.PriceFeed{pricedFeed("101"), pricedFeed("200"), pricedFeed("102")})
+	require.NoError(t, err, "Rejected rows do not fail the batch")
+	require.Len(t, results, 3)
+	assert.False(t, results[0].Rejected)
+	assert.True(t, results[1].Rejected)
+	assert.False(t, results[2].Rejected)
+	assert.Len(t, inner.created, 3)
+}
+
+func TestAnomalyFilteringPriceFeedRepository_Flags(t *testing.T) {
+	repo, inner, quarantine := newAnomalyFilteringRepository(anomaly.ActionFlag)
+	_, err := repo.Create(context.Background(), pricedFeed("100"))
+	require.NoError(t, err)
+
+	flagged := pricedFeed("150")
+	flagged.Metadata = json.RawMessage(`{"venue":"spot"}`)
+	result, err := repo.Create(context.Background(), flagged)
+	require.NoError(t, err)
+
+	assert.False(t, result.Quarantined)
+	assert.Empty(t, quarantine.quarantined)
+	require.Len(t, inner.created, 2)
+
+	var metadata map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(inner.created[1].Metadata, &metadata))
+	assert.JSONEq(t, `"spot"`, string(metadata["venue"]))
+	assert.Contains(t, string(metadata["anomalies"]), `"check":"jump"`)
+}
+
+func TestAnomalyFilteringPriceFeedRepository_WaitsForCommit(t *testing.T) {
+	filter := anomaly.NewFilter(10, 2, anomaly.JumpDetector{MaxChange: 0.1})
+	ctx := context.Background()
+	newTxRepo := func(hooks *afterCommit) *AnomalyFilteringPriceFeedRepository {
+		return newAnomalyFilteringPriceFeedRepository(&stubPriceFeedRepository{}, filter, anomaly.ActionReject, &recordingQuarantineRepository{}, hooks, newQuietLogger())
+	}
+
+	// A rolled-back transaction: its accepted price and rejected run never reach the filter
+	rolledBack := newTxRepo(&afterCommit{})
+	_, err := rolledBack.CreateBatch(ctx, []*models.PriceFeed{pricedFeed("100"), pricedFeed("150")})
+	require.NoError(t, err)
+	_, err = rolledBack.Create(ctx, pricedFeed("50"))
+	assert.ErrorIs(t, err, interfaces.ErrAnomalousPrice, "Feeds are screened against earlier ones in the transaction")
+
+	assert.Empty(t, filter.Inspect(pricedFeed("1000")), "The filter has no window after the rollback")
+
+	// Its retry commits, and only then does the window move
+	hooks := &afterCommit{}
+	committed := newTxRepo(hooks)
+	_, err = committed.Create(ctx, pricedFeed("100"))
+	require.NoError(t, err)
+	assert.Empty(t, filter.Inspect(pricedFeed("1000")), "Nothing is applied before the commit")
+
+	hooks.run(ctx)
+	assert.NotEmpty(t, filter.Inspect(pricedFeed("150")), "The committed price became the reference")
+}
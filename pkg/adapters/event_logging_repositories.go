@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// EventLoggingPriceFeedRepository appends every newly created price feed to the event
+// log; replays ignored as duplicates are not logged again. Append failures are logged
+// rather than returned: the write already succeeded.
+type EventLoggingPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	eventLog interfaces.EventLog
+	logger   *logrus.Logger
+}
+
+func NewEventLoggingPriceFeedRepository(repo interfaces.PriceFeedRepository, eventLog interfaces.EventLog, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return &EventLoggingPriceFeedRepository{
+		PriceFeedRepository: repo,
+		eventLog:            eventLog,
+		logger:              logger,
+	}
+}
+
+func (r *EventLoggingPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	result, err := r.PriceFeedRepository.Create(ctx, feed)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.Duplicate {
+		r.append(ctx, feed)
+	}
+	return result, nil
+}
+
+func (r *EventLoggingPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results, err := r.PriceFeedRepository.CreateBatch(ctx, feeds)
+	if err != nil {
+		return results, err
+	}
+
+	for i, result := range results {
+		if !result.Duplicate && !result.Rejected {
+			r.append(ctx, feeds[i])
+		}
+	}
+	return results, nil
+}
+
+func (r *EventLoggingPriceFeedRepository) append(ctx context.Context, feed *models.PriceFeed) {
+	event, err := interfaces.NewPriceFeedEvent(feed)
+	if err == nil {
+		_, err = r.eventLog.Append(ctx, event)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("feed_id", feed.FeedID).Warn("Price feed stored but not logged")
+	}
+}
+
+// EventLoggingCandleRepository appends every successfully upserted candle to the event log
+type EventLoggingCandleRepository struct {
+	interfaces.CandleRepository
+	eventLog interfaces.EventLog
+	logger   *logrus.Logger
+}
+
+func NewEventLoggingCandleRepository(repo interfaces.CandleRepository, eventLog interfaces.EventLog, logger *logrus.Logger) interfaces.CandleRepository {
+	return &EventLoggingCandleRepository{
+		CandleRepository: repo,
+		eventLog:         eventLog,
+		logger:           logger,
+	}
+}
+
+func (r *EventLoggingCandleRepository) Upsert(ctx context.Context, candle *models.Candle) error {
+	if err := r.CandleRepository.Upsert(ctx, candle); err != nil {
+		return err
+	}
+
+	event, err := interfaces.NewCandleEvent(candle)
+	if err == nil {
+		_, err = r.eventLog.Append(ctx, event)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("candle_id", candle.CandleID).Warn("Candle stored but not logged")
+	}
+	return nil
+}
+
+// EventLoggingMarketSnapshotRepository appends every successfully created snapshot to the event log
+type EventLoggingMarketSnapshotRepository struct {
+	interfaces.MarketSnapshotRepository
+	eventLog interfaces.EventLog
+	logger   *logrus.Logger
+}
+
+func NewEventLoggingMarketSnapshotRepository(repo interfaces.MarketSnapshotRepository, eventLog interfaces.EventLog, logger *logrus.Logger) interfaces.MarketSnapshotRepository {
+	return &EventLoggingMarketSnapshotRepository{
+		MarketSnapshotRepository: repo,
+		eventLog:                 eventLog,
+		logger:                   logger,
+	}
+}
+
+func (r *EventLoggingMarketSnapshotRepository) Create(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	if err := r.MarketSnapshotRepository.Create(ctx, snapshot); err != nil {
+		return err
+	}
+
+	event, err := interfaces.NewSnapshotEvent(snapshot)
+	if err == nil {
+		_, err = r.eventLog.Append(ctx, event)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("snapshot_id", snapshot.SnapshotID).Warn("Snapshot stored but not logged")
+	}
+	return nil
+}
@@ -2,13 +2,25 @@ package adapters
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/cache"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/config"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/database"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/anomaly"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/freshness"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/ohlc"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/quality"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/retention"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/snapshot"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +32,34 @@ type DataAdapter interface {
 	SymbolRepository() interfaces.SymbolRepository
 	ServiceDiscoveryRepository() interfaces.ServiceDiscoveryRepository
 	CacheRepository() interfaces.CacheRepository
+	DataQualityRepository() interfaces.DataQualityRepository
+	ArbitrationRepository() interfaces.ArbitrationRepository
+	QuarantineRepository() interfaces.QuarantineRepository
+	BackfillCheckpointRepository() interfaces.BackfillCheckpointRepository
+
+	// Real-time publication
+	MarketDataPublisher() interfaces.MarketDataPublisher
+	EventLog() interfaces.EventLog
+	SetEventSink(sink interfaces.EventSink)
+	ChangeFeed() interfaces.ChangeFeed
+
+	// Monitoring
+	FreshnessReport() *freshness.Report
+	RetentionReport() *retention.Report
+
+	// Unit of work
+	WithTx(ctx context.Context, fn func(tx interfaces.TxRepositories) error) error
+	WithTxOptions(ctx context.Context, options interfaces.TxOptions, fn func(tx interfaces.TxRepositories) error) error
+
+	// Coordination
+	Locker() interfaces.Locker
+	NewLeaderElector(callbacks interfaces.LeaderCallbacks) (interfaces.LeaderElector, error)
+
+	// Candle backfill
+	NewCandleBackfiller(provider interfaces.HistoricalDataProvider, options ohlc.Options) (*ohlc.Backfiller, error)
+
+	// Service registration
+	UpdateServiceHealth(ctx context.Context, status interfaces.HealthStatus, note string) error
 
 	// Lifecycle
 	Connect(ctx context.Context) error
@@ -31,8 +71,9 @@ type MarketDataAdapter struct {
 	config *config.Config
 	logger *logrus.Logger
 
-	// Infrastructure
+	// Infrastructure; at most one of postgresDB and sqliteDB is set
 	postgresDB  *database.PostgresDB
+	sqliteDB    *database.SQLiteDB
 	redisClient *cache.RedisClient
 
 	// Repositories
@@ -42,6 +83,53 @@ type MarketDataAdapter struct {
 	symbolRepo           interfaces.SymbolRepository
 	serviceDiscoveryRepo interfaces.ServiceDiscoveryRepository
 	cacheRepo            interfaces.CacheRepository
+	dataQualityRepo      interfaces.DataQualityRepository
+	arbitrationRepo      interfaces.ArbitrationRepository
+	quarantineRepo       interfaces.QuarantineRepository
+	backfillRepo         interfaces.BackfillCheckpointRepository
+
+	// Data quality; the detectors outlive repository rebuilds so no history is forgotten
+	gapDetector   *quality.GapDetector
+	anomalyFilter *anomaly.Filter
+	anomalyAction anomaly.Action
+
+	// Real-time publication
+	publisher interfaces.MarketDataPublisher
+	eventLog  interfaces.EventLog
+
+	// Coordination
+	locker interfaces.Locker
+
+	// Transactional outbox
+	eventSink   interfaces.EventSink
+	outboxRelay *OutboxRelay
+
+	// Change feed
+	changeListener *database.ChangeListener
+
+	// Freshness monitoring
+	freshnessMonitor *freshness.Monitor
+
+	// Snapshot generation
+	snapshotBuilder *snapshot.Builder
+
+	// Retention
+	retentionPolicies  []retention.Policy
+	retentionScheduler *retention.Scheduler
+
+	// Partitioning
+	partitionIntervals map[string]database.PartitionInterval
+	partitionManager   *database.PartitionManager
+
+	// TimescaleDB; timescale stays nil when the extension is unavailable
+	timescaleOptions database.TimescaleOptions
+	timescale        *database.Timescale
+
+	// Unit of work defaults
+	txOptions interfaces.TxOptions
+
+	// Lifecycle-managed services
+	registrationManager *ServiceRegistrationManager
 }
 
 func NewMarketDataAdapter(cfg *config.Config, logger *logrus.Logger) (DataAdapter, error) {
@@ -62,33 +150,112 @@ func NewMarketDataAdapter(cfg *config.Config, logger *logrus.Logger) (DataAdapte
 		cfg.RedisNamespace = deriveRedisNamespace(cfg.ServiceName, cfg.ServiceInstanceName)
 	}
 
+	// Generate a replica identifier if not explicitly provided
+	if cfg.InstanceID == "" {
+		cfg.InstanceID = deriveInstanceID(cfg.ServiceInstanceName)
+	}
+
+	// Fall back to LoadConfig defaults for timings left unset
+	applyTimingDefaults(cfg)
+
 	logger.WithFields(logrus.Fields{
 		"service_name":    cfg.ServiceName,
 		"instance_name":   cfg.ServiceInstanceName,
+		"instance_id":     cfg.InstanceID,
 		"schema_name":     cfg.SchemaName,
 		"redis_namespace": cfg.RedisNamespace,
 	}).Info("DataAdapter configuration resolved")
 
+	isolation, err := parseIsolationLevel(cfg.TxIsolationLevel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TX_ISOLATION_LEVEL: %w", err)
+	}
+
 	adapter := &MarketDataAdapter{
 		config: cfg,
 		logger: logger,
+		txOptions: interfaces.TxOptions{
+			Isolation:  isolation,
+			MaxRetries: cfg.TxMaxRetries,
+		},
 	}
 
-	// Initialize PostgreSQL
-	if cfg.PostgresURL != "" {
-		postgresDB, err := database.NewPostgresDB(cfg, logger)
+	if cfg.AnomalyFilterEnabled {
+		action, err := anomaly.ParseAction(cfg.AnomalyAction)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create PostgreSQL client: %w", err)
+			return nil, fmt.Errorf("invalid ANOMALY_ACTION: %w", err)
 		}
-		adapter.postgresDB = postgresDB
+		adapter.anomalyAction = action
+		adapter.anomalyFilter = anomaly.NewFilter(cfg.AnomalyWindow, cfg.AnomalyRebaseline,
+			anomaly.ZScoreDetector{Threshold: cfg.AnomalyZScore},
+			anomaly.JumpDetector{MaxChange: cfg.AnomalyMaxJump},
+			anomaly.CrossedQuoteDetector{},
+		)
+	}
 
-		// Initialize PostgreSQL repositories
-		adapter.priceFeedRepo = NewPostgresPriceFeedRepository(postgresDB.DB, logger)
-		adapter.candleRepo = NewPostgresCandleRepository(postgresDB.DB, logger)
-		adapter.marketSnapshotRepo = NewPostgresMarketSnapshotRepository(postgresDB.DB, logger)
-		adapter.symbolRepo = NewPostgresSymbolRepository(postgresDB.DB, logger)
-	} else {
-		logger.Warn("PostgreSQL URL not configured, repositories will not be available")
+	switch cfg.SnapshotMode {
+	case "", "off", "schedule", "tick":
+	default:
+		return nil, fmt.Errorf("invalid SNAPSHOT_MODE: unknown snapshot mode %q", cfg.SnapshotMode)
+	}
+
+	if cfg.RetentionEnabled {
+		policies, err := retention.ParsePolicies(cfg.RetentionPolicies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RETENTION_POLICIES: %w", err)
+		}
+		adapter.retentionPolicies = policies
+	}
+
+	if cfg.PartitioningEnabled {
+		intervals, err := database.ParsePartitionIntervals(cfg.PartitionIntervals)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PARTITION_INTERVALS: %w", err)
+		}
+		adapter.partitionIntervals = intervals
+	}
+
+	if cfg.TimescaleEnabled {
+		if cfg.PartitioningEnabled {
+			return nil, fmt.Errorf("TIMESCALE_ENABLED and PARTITIONING_ENABLED are mutually exclusive")
+		}
+		options, err := buildTimescaleOptions(cfg)
+		if err != nil {
+			return nil, err
+		}
+		adapter.timescaleOptions = options
+	}
+
+	// Initialize storage; DATABASE_URL's scheme picks the backend
+	switch {
+	case database.IsSQLiteURL(cfg.DatabaseURL):
+		if unsupported := adapter.sqliteUnsupported(); len(unsupported) > 0 {
+			return nil, fmt.Errorf("SQLite backend does not support %s", strings.Join(unsupported, ", "))
+		}
+		// Gap detection is on by default, so it is skipped rather than refused
+		if cfg.GapDetectionEnabled {
+			logger.Warn("Gap detection needs the PostgreSQL data quality store and is disabled on SQLite")
+		}
+		sqliteDB, err := database.NewSQLiteDB(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SQLite client: %w", err)
+		}
+		adapter.sqliteDB = sqliteDB
+	case cfg.DatabaseURL != "" && !strings.HasPrefix(cfg.DatabaseURL, "postgres://") && !strings.HasPrefix(cfg.DatabaseURL, "postgresql://"):
+		return nil, fmt.Errorf("invalid DATABASE_URL: unsupported scheme %q", strings.SplitN(cfg.DatabaseURL, ":", 2)[0])
+	default:
+		if cfg.DatabaseURL != "" {
+			cfg.PostgresURL = cfg.DatabaseURL
+		}
+		if cfg.PostgresURL != "" {
+			postgresDB, err := database.NewPostgresDB(cfg, logger)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create PostgreSQL client: %w", err)
+			}
+			adapter.postgresDB = postgresDB
+		} else {
+			logger.Warn("PostgreSQL URL not configured, repositories will not be available")
+		}
 	}
 
 	// Initialize Redis
@@ -100,15 +267,246 @@ func NewMarketDataAdapter(cfg *config.Config, logger *logrus.Logger) (DataAdapte
 		adapter.redisClient = redisClient
 
 		// Initialize Redis repositories
-		adapter.serviceDiscoveryRepo = NewRedisServiceDiscovery(redisClient.Client, cfg.ServiceDiscoveryNamespace, logger)
+		adapter.serviceDiscoveryRepo = NewRedisServiceDiscovery(redisClient.Client, cfg.ServiceDiscoveryNamespace, cfg.ServiceTTL, cfg.ConfigureKeyspaceEvents, logger)
 		adapter.cacheRepo = NewRedisCacheRepository(redisClient.Client, cfg.CacheNamespace, logger)
+
+		adapter.publisher = NewRedisMarketDataPublisher(redisClient.Client, cfg.RedisNamespace, logger)
+		adapter.eventLog = NewRedisEventLog(redisClient.Client, cfg.RedisNamespace, int64(cfg.EventLogMaxLen), logger)
+
+		// Initialize coordination primitives
+		adapter.locker = NewRedisLocker(redisClient.Client, cfg.RedisNamespace, logger)
+
+		if cfg.AutoRegisterService {
+			adapter.registrationManager, err = NewServiceRegistrationManager(
+				adapter.serviceDiscoveryRepo,
+				buildServiceInfo(cfg),
+				cfg.HeartbeatInterval,
+				cfg.ServiceTTL,
+				logger,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("invalid service registration timing: %w", err)
+			}
+		}
 	} else {
-		logger.Warn("Redis URL not configured, cache and service discovery will not be available")
+		logger.Warn("Redis URL not configured, cache, service discovery and locking will not be available")
 	}
 
+	// Repositories exist before Connect; they are rebuilt once the pool is open
+	adapter.initRepositories()
+
 	return adapter, nil
 }
 
+// sqliteUnsupported lists the enabled features that need PostgreSQL
+func (a *MarketDataAdapter) sqliteUnsupported() []string {
+	cfg := a.config
+
+	var unsupported []string
+	if cfg.OutboxEnabled {
+		unsupported = append(unsupported, "OUTBOX_ENABLED")
+	}
+	if cfg.ChangeFeedEnabled {
+		unsupported = append(unsupported, "CHANGE_FEED_ENABLED")
+	}
+	if cfg.PartitioningEnabled {
+		unsupported = append(unsupported, "PARTITIONING_ENABLED")
+	}
+	if cfg.TimescaleEnabled {
+		unsupported = append(unsupported, "TIMESCALE_ENABLED")
+	}
+	if a.anomalyFilter != nil && a.anomalyAction == anomaly.ActionQuarantine {
+		unsupported = append(unsupported, "ANOMALY_ACTION=quarantine")
+	}
+	for _, policy := range a.retentionPolicies {
+		if policy.Entity == retention.EntityDataQuality {
+			unsupported = append(unsupported, "retention of "+string(retention.EntityDataQuality))
+			break
+		}
+	}
+	return unsupported
+}
+
+// initRepositories builds the configured backend's repositories over its current pool and
+// wraps them with the configured write-side fan-out
+func (a *MarketDataAdapter) initRepositories() {
+	switch {
+	case a.sqliteDB != nil:
+		a.initSQLiteRepositories()
+	case a.postgresDB != nil:
+		a.initPostgresRepositories()
+	default:
+		return
+	}
+	a.wrapRepositories()
+}
+
+// initSQLiteRepositories builds the SQLite repositories; data quality, arbitration,
+// quarantine and backfill checkpoints stay unavailable
+func (a *MarketDataAdapter) initSQLiteRepositories() {
+	logger, db := a.logger, a.sqliteDB.DB
+
+	a.priceFeedRepo = NewSQLitePriceFeedRepository(db, logger)
+	a.candleRepo = NewSQLiteCandleRepository(db, logger)
+	a.marketSnapshotRepo = NewSQLiteMarketSnapshotRepository(db, logger)
+	a.symbolRepo = NewSQLiteSymbolRepository(db, logger)
+}
+
+// initPostgresRepositories builds the PostgreSQL repositories over the current pool
+func (a *MarketDataAdapter) initPostgresRepositories() {
+	cfg, logger, db := a.config, a.logger, a.postgresDB.DB
+
+	repos := &repositorySet{
+		priceFeedRepo:      NewPostgresPriceFeedRepository(db, cfg.SchemaName, cfg.OutboxEnabled, logger),
+		candleRepo:         NewPostgresCandleRepository(db, cfg.SchemaName, cfg.OutboxEnabled, logger),
+		marketSnapshotRepo: NewPostgresMarketSnapshotRepository(db, cfg.SchemaName, cfg.OutboxEnabled, logger),
+		symbolRepo:         NewPostgresSymbolRepository(db, cfg.SchemaName, cfg.OutboxEnabled, logger),
+	}
+	a.dataQualityRepo = NewPostgresDataQualityRepository(db, cfg.SchemaName, logger)
+	a.arbitrationRepo = NewPostgresArbitrationRepository(db, cfg.SchemaName, logger)
+	a.quarantineRepo = NewPostgresQuarantineRepository(db, cfg.SchemaName, logger)
+	a.backfillRepo = NewPostgresBackfillCheckpointRepository(db, cfg.SchemaName, logger)
+
+	if cfg.GapDetectionEnabled && a.gapDetector == nil {
+		a.gapDetector = quality.NewGapDetector()
+	}
+	a.wrapStorage(repos, asDBTX(db), nil)
+	a.setRepositories(repos)
+}
+
+// wrapStorage applies the PostgreSQL decorators over db, the pool or a transaction;
+// hooks is nil outside a transaction
+func (a *MarketDataAdapter) wrapStorage(repos *repositorySet, db dbtx, hooks *afterCommit) {
+	logger := a.logger
+
+	// Expire partitioned tables a partition at a time rather than row by row
+	if a.partitionManager != nil {
+		repos.priceFeedRepo = NewPartitionedPriceFeedRepository(repos.priceFeedRepo, a.partitionManager, logger)
+		repos.candleRepo = NewPartitionedCandleRepository(repos.candleRepo, a.partitionManager, logger)
+		repos.marketSnapshotRepo = NewPartitionedMarketSnapshotRepository(repos.marketSnapshotRepo, a.partitionManager, logger)
+	}
+
+	// Hypertables expire a chunk at a time and serve coarser candles from aggregates
+	if a.timescale != nil {
+		repos.priceFeedRepo = NewPartitionedPriceFeedRepository(repos.priceFeedRepo, a.timescale, logger)
+		repos.candleRepo = NewPartitionedCandleRepository(repos.candleRepo, a.timescale, logger)
+		repos.marketSnapshotRepo = NewPartitionedMarketSnapshotRepository(repos.marketSnapshotRepo, a.timescale, logger)
+		repos.candleRepo = newTimescaleCandleRepository(repos.candleRepo, db, a.config.SchemaName, a.timescaleOptions.Aggregates, logger)
+	}
+
+	// Check sequences of stored feeds so dropped vendor messages are recorded
+	if a.config.GapDetectionEnabled && a.gapDetector != nil {
+		repos.priceFeedRepo = newGapDetectingPriceFeedRepository(repos.priceFeedRepo, a.gapDetector, a.dataQualityRepo, hooks, logger)
+	}
+}
+
+// wrapRepositories applies the decorators shared by both backends
+func (a *MarketDataAdapter) wrapRepositories() {
+	cfg, logger := a.config, a.logger
+	repos := a.repositories()
+
+	// With the outbox enabled the relay delivers instead, so writes are not fanned out twice
+	if !cfg.OutboxEnabled {
+		a.wrapFanOut(repos, a.publisher, a.eventLog)
+	}
+
+	// Derive snapshots through the wrapped snapshot repository so they are fanned out too
+	switch cfg.SnapshotMode {
+	case "schedule", "tick":
+		a.snapshotBuilder = snapshot.NewBuilder(repos.priceFeedRepo, repos.candleRepo, repos.marketSnapshotRepo, snapshot.Options{
+			Window:         cfg.SnapshotWindow,
+			CandleInterval: models.CandleInterval(cfg.SnapshotCandleInterval),
+			BuildInterval:  cfg.SnapshotInterval,
+			Symbols:        cfg.SnapshotSymbols,
+			Locker:         a.locker,
+		}, logger)
+	}
+
+	a.wrapScreening(repos, a.quarantineRepo, nil)
+	a.setRepositories(repos)
+}
+
+// wrapFanOut wraps the repositories so writes reach subscribers and the event log
+func (a *MarketDataAdapter) wrapFanOut(repos *repositorySet, publisher interfaces.MarketDataPublisher, eventLog interfaces.EventLog) {
+	cfg, logger := a.config, a.logger
+
+	// Fan out new prices and snapshots to subscribers when both stores are available
+	if cfg.PublishMarketData && publisher != nil {
+		repos.priceFeedRepo = NewPublishingPriceFeedRepository(repos.priceFeedRepo, publisher, logger)
+		repos.marketSnapshotRepo = NewPublishingMarketSnapshotRepository(repos.marketSnapshotRepo, publisher, logger)
+	}
+
+	// Record writes durably for consumers that cannot afford to miss pub/sub messages
+	if cfg.EventLogEnabled && eventLog != nil {
+		repos.priceFeedRepo = NewEventLoggingPriceFeedRepository(repos.priceFeedRepo, eventLog, logger)
+		repos.candleRepo = NewEventLoggingCandleRepository(repos.candleRepo, eventLog, logger)
+		repos.marketSnapshotRepo = NewEventLoggingMarketSnapshotRepository(repos.marketSnapshotRepo, eventLog, logger)
+	}
+}
+
+// wrapScreening builds tick snapshots from stored feeds and screens prices outermost, so
+// held-back feeds are neither stored nor fanned out
+func (a *MarketDataAdapter) wrapScreening(repos *repositorySet, quarantine interfaces.QuarantineRepository, hooks *afterCommit) {
+	if a.config.SnapshotMode == "tick" && a.snapshotBuilder != nil {
+		repos.priceFeedRepo = newSnapshottingPriceFeedRepository(repos.priceFeedRepo, a.snapshotBuilder, hooks, a.logger)
+	}
+
+	if a.anomalyFilter != nil {
+		repos.priceFeedRepo = newAnomalyFilteringPriceFeedRepository(repos.priceFeedRepo, a.anomalyFilter, a.anomalyAction, quarantine, hooks, a.logger)
+	}
+}
+
+func (a *MarketDataAdapter) repositories() *repositorySet {
+	return &repositorySet{
+		priceFeedRepo:      a.priceFeedRepo,
+		candleRepo:         a.candleRepo,
+		marketSnapshotRepo: a.marketSnapshotRepo,
+		symbolRepo:         a.symbolRepo,
+	}
+}
+
+func (a *MarketDataAdapter) setRepositories(repos *repositorySet) {
+	a.priceFeedRepo = repos.priceFeedRepo
+	a.candleRepo = repos.candleRepo
+	a.marketSnapshotRepo = repos.marketSnapshotRepo
+	a.symbolRepo = repos.symbolRepo
+}
+
+// SetEventSink overrides the configured outbox sink, e.g. with an EventSinkFunc callback;
+// it must be called before Connect
+func (a *MarketDataAdapter) SetEventSink(sink interfaces.EventSink) {
+	a.eventSink = sink
+}
+
+// buildEventSink resolves the sink the outbox relay delivers to
+func (a *MarketDataAdapter) buildEventSink() (interfaces.EventSink, error) {
+	if a.eventSink != nil {
+		return a.eventSink, nil
+	}
+
+	switch a.config.OutboxSink {
+	case "", "stream":
+		if a.eventLog == nil {
+			return nil, fmt.Errorf("outbox sink %q requires Redis", "stream")
+		}
+		return NewEventLogSink(a.eventLog), nil
+	case "pubsub":
+		if a.publisher == nil {
+			return nil, fmt.Errorf("outbox sink %q requires Redis", "pubsub")
+		}
+		return NewPublisherEventSink(a.publisher), nil
+	case "both":
+		if a.eventLog == nil || a.publisher == nil {
+			return nil, fmt.Errorf("outbox sink %q requires Redis", "both")
+		}
+		return MultiEventSink{NewEventLogSink(a.eventLog), NewPublisherEventSink(a.publisher)}, nil
+	case "callback":
+		return nil, fmt.Errorf("outbox sink %q requires SetEventSink before Connect", "callback")
+	default:
+		return nil, fmt.Errorf("unknown outbox sink %q", a.config.OutboxSink)
+	}
+}
+
 func NewMarketDataAdapterFromEnv(logger *logrus.Logger) (DataAdapter, error) {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -119,10 +517,79 @@ func NewMarketDataAdapterFromEnv(logger *logrus.Logger) (DataAdapter, error) {
 }
 
 func (a *MarketDataAdapter) Connect(ctx context.Context) error {
+	// Connect to SQLite
+	databaseConnected := false
+	if a.sqliteDB != nil {
+		if err := a.sqliteDB.Connect(ctx); err != nil {
+			a.logger.WithError(err).Warn("Failed to connect to SQLite (stub mode)")
+		} else {
+			databaseConnected = true
+
+			if a.config.AutoMigrate {
+				if err := a.sqliteDB.Migrate(ctx); err != nil {
+					return fmt.Errorf("failed to migrate SQLite schema: %w", err)
+				}
+			}
+
+			// Rebind repositories to the now-open database
+			a.initRepositories()
+		}
+	}
+
 	// Connect to PostgreSQL
 	if a.postgresDB != nil {
 		if err := a.postgresDB.Connect(ctx); err != nil {
 			a.logger.WithError(err).Warn("Failed to connect to PostgreSQL (stub mode)")
+		} else {
+			databaseConnected = true
+
+			if a.config.AutoMigrate {
+				if err := a.postgresDB.Migrate(ctx); err != nil {
+					return fmt.Errorf("failed to migrate PostgreSQL schema: %w", err)
+				}
+			}
+
+			// Convert to hypertables before the repositories are bound; without the extension
+			// the adapter keeps running on plain PostgreSQL
+			a.timescale = nil
+			if a.config.TimescaleEnabled {
+				timescale := a.postgresDB.NewTimescale(a.timescaleOptions)
+				switch err := timescale.Setup(ctx); {
+				case err == nil:
+					a.timescale = timescale
+				case errors.Is(err, database.ErrTimescaleUnavailable):
+					a.logger.WithError(err).Warn("TimescaleDB unavailable, falling back to plain PostgreSQL")
+				default:
+					return fmt.Errorf("failed to set up TimescaleDB: %w", err)
+				}
+			}
+
+			// Partition before the repositories are bound, so they drop partitions for retention
+			if a.config.PartitioningEnabled {
+				a.partitionManager = a.postgresDB.NewPartitionManager(database.PartitionOptions{
+					Intervals:     a.partitionIntervals,
+					Premake:       a.config.PartitionPremake,
+					CheckInterval: a.config.PartitionCheckInterval,
+				})
+				if err := a.partitionManager.Setup(ctx); err != nil {
+					a.partitionManager = nil
+					return fmt.Errorf("failed to set up partitions: %w", err)
+				}
+				if err := a.partitionManager.Start(ctx); err != nil {
+					return fmt.Errorf("failed to start partition manager: %w", err)
+				}
+			}
+
+			// Rebind repositories to the now-open pool
+			a.initRepositories()
+
+			if a.config.ChangeFeedEnabled {
+				a.changeListener = a.postgresDB.NewChangeListener()
+				if err := a.changeListener.Start(ctx); err != nil {
+					a.changeListener = nil
+					a.logger.WithError(err).Warn("Failed to start change listener")
+				}
+			}
 		}
 	}
 
@@ -130,6 +597,66 @@ func (a *MarketDataAdapter) Connect(ctx context.Context) error {
 	if a.redisClient != nil {
 		if err := a.redisClient.Connect(ctx); err != nil {
 			a.logger.WithError(err).Warn("Failed to connect to Redis (stub mode)")
+		} else if a.registrationManager != nil {
+			if err := a.registrationManager.Start(ctx); err != nil {
+				a.logger.WithError(err).Warn("Failed to start service registration")
+			}
+		}
+	}
+
+	// Relay outbox rows once the sink's transport is up
+	if databaseConnected && a.config.OutboxEnabled {
+		sink, err := a.buildEventSink()
+		if err != nil {
+			return fmt.Errorf("failed to configure outbox sink: %w", err)
+		}
+
+		a.outboxRelay = NewOutboxRelay(a.postgresDB.DB, a.config.SchemaName, sink, OutboxRelayOptions{
+			PollInterval: a.config.OutboxPollInterval,
+			BatchSize:    a.config.OutboxBatchSize,
+			Retention:    a.config.OutboxRetention,
+			MaxAttempts:  a.config.OutboxMaxAttempts,
+		}, a.logger)
+		if err := a.outboxRelay.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start outbox relay: %w", err)
+		}
+	}
+
+	// Watch for feeds that stop updating once service health can be reported
+	if databaseConnected && a.config.FreshnessEnabled {
+		a.freshnessMonitor = freshness.NewMonitor(a.priceFeedRepo, a.marketSnapshotRepo, freshness.Options{
+			DefaultSLA:    a.config.FreshnessDefaultSLA,
+			SymbolSLAs:    a.config.FreshnessSymbolSLAs,
+			SourceSLAs:    a.config.FreshnessSourceSLAs,
+			CheckInterval: a.config.FreshnessCheckInterval,
+			Expiry:        a.config.FreshnessExpiry,
+			OnEvent:       a.onFreshnessEvent,
+		}, a.logger)
+		if err := a.freshnessMonitor.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start freshness monitor: %w", err)
+		}
+	}
+
+	// Build snapshots on a schedule once the repositories are bound to the open pool
+	if databaseConnected && a.config.SnapshotMode == "schedule" {
+		if err := a.snapshotBuilder.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start snapshot builder: %w", err)
+		}
+	}
+
+	// Apply retention policies; with Redis, replicas take turns through the locker
+	if databaseConnected && a.config.RetentionEnabled {
+		a.retentionScheduler = retention.NewScheduler(retention.Repositories{
+			PriceFeeds:  a.priceFeedRepo,
+			Candles:     a.candleRepo,
+			Snapshots:   a.marketSnapshotRepo,
+			DataQuality: a.dataQualityRepo,
+		}, a.retentionPolicies, retention.Options{
+			Interval: a.config.RetentionInterval,
+			Locker:   a.locker,
+		}, a.logger)
+		if err := a.retentionScheduler.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start retention scheduler: %w", err)
 		}
 	}
 
@@ -140,6 +667,62 @@ func (a *MarketDataAdapter) Connect(ctx context.Context) error {
 func (a *MarketDataAdapter) Disconnect(ctx context.Context) error {
 	var errors []error
 
+	// Stop background workers before either connection goes away
+	if a.freshnessMonitor != nil {
+		if err := a.freshnessMonitor.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("freshness monitor stop error: %w", err))
+		}
+		a.freshnessMonitor = nil
+	}
+
+	if a.retentionScheduler != nil {
+		if err := a.retentionScheduler.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("retention scheduler stop error: %w", err))
+		}
+		a.retentionScheduler = nil
+	}
+
+	if a.partitionManager != nil {
+		if err := a.partitionManager.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("partition manager stop error: %w", err))
+		}
+		a.partitionManager = nil
+	}
+
+	if a.snapshotBuilder != nil {
+		if err := a.snapshotBuilder.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("snapshot builder stop error: %w", err))
+		}
+	}
+
+	if a.outboxRelay != nil {
+		if err := a.outboxRelay.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("outbox relay stop error: %w", err))
+		}
+		a.outboxRelay = nil
+	}
+
+	if a.changeListener != nil {
+		if err := a.changeListener.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("change listener stop error: %w", err))
+		}
+		a.changeListener = nil
+	}
+
+	// Deregister before the Redis connection goes away
+	if a.registrationManager != nil {
+		if err := a.registrationManager.Stop(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("service deregistration error: %w", err))
+		}
+	}
+
+	// Disconnect from SQLite
+	if a.sqliteDB != nil {
+		if err := a.sqliteDB.Disconnect(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("SQLite disconnect error: %w", err))
+		}
+	}
+
 	// Disconnect from PostgreSQL
 	if a.postgresDB != nil {
 		if err := a.postgresDB.Disconnect(ctx); err != nil {
@@ -163,6 +746,13 @@ func (a *MarketDataAdapter) Disconnect(ctx context.Context) error {
 }
 
 func (a *MarketDataAdapter) HealthCheck(ctx context.Context) error {
+	// Check SQLite health
+	if a.sqliteDB != nil {
+		if err := a.sqliteDB.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("SQLite health check failed: %w", err)
+		}
+	}
+
 	// Check PostgreSQL health
 	if a.postgresDB != nil {
 		if err := a.postgresDB.HealthCheck(ctx); err != nil {
@@ -177,9 +767,70 @@ func (a *MarketDataAdapter) HealthCheck(ctx context.Context) error {
 		}
 	}
 
+	// Stale feeds leave the service usable but degraded
+	if report := a.FreshnessReport(); report != nil && report.Stale > 0 {
+		return fmt.Errorf("%w: %d stale feeds", interfaces.ErrDegraded, report.Stale)
+	}
+
 	return nil
 }
 
+// FreshnessReport returns the latest freshness check, or nil unless monitoring is enabled
+func (a *MarketDataAdapter) FreshnessReport() *freshness.Report {
+	if a.freshnessMonitor == nil {
+		return nil
+	}
+	return a.freshnessMonitor.Report()
+}
+
+// RetentionReport returns the last retention run's report, or nil if retention is off
+func (a *MarketDataAdapter) RetentionReport() *retention.Report {
+	if a.retentionScheduler == nil {
+		return nil
+	}
+	return a.retentionScheduler.Report()
+}
+
+// onFreshnessEvent records a stale or recovered transition as a data-quality event and
+// marks this replica degraded while any feed is stale
+func (a *MarketDataAdapter) onFreshnessEvent(event freshness.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// SQLite has no data quality store; transitions are still logged and reported. An
+	// expired feed is no longer tracked, which only changes the health below.
+	if a.dataQualityRepo != nil && event.Type != freshness.EventExpired {
+		eventType := models.QualityStaleFeed
+		if event.Type == freshness.EventRecovered {
+			eventType = models.QualityRecovered
+		}
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"kind":        event.Entry.Kind,
+			"last_update": event.Entry.LastUpdate,
+			"sla":         event.Entry.SLA.String(),
+		})
+		if err := a.dataQualityRepo.Create(ctx, &models.DataQualityEvent{
+			Type:     eventType,
+			Source:   event.Entry.Source,
+			Symbol:   event.Entry.Symbol,
+			Metadata: metadata,
+		}); err != nil {
+			a.logger.WithError(err).Warn("Failed to record freshness event")
+		}
+	}
+
+	if a.registrationManager == nil {
+		return
+	}
+	status, note := interfaces.HealthPassing, ""
+	if report := a.freshnessMonitor.Report(); report != nil && report.Stale > 0 {
+		status, note = interfaces.HealthWarning, fmt.Sprintf("%d stale feeds", report.Stale)
+	}
+	if err := a.registrationManager.SetHealth(ctx, status, note); err != nil {
+		a.logger.WithError(err).Warn("Failed to update service health for feed freshness")
+	}
+}
+
 // Repository accessors
 func (a *MarketDataAdapter) PriceFeedRepository() interfaces.PriceFeedRepository {
 	return a.priceFeedRepo
@@ -205,6 +856,156 @@ func (a *MarketDataAdapter) CacheRepository() interfaces.CacheRepository {
 	return a.cacheRepo
 }
 
+func (a *MarketDataAdapter) DataQualityRepository() interfaces.DataQualityRepository {
+	return a.dataQualityRepo
+}
+
+func (a *MarketDataAdapter) ArbitrationRepository() interfaces.ArbitrationRepository {
+	return a.arbitrationRepo
+}
+
+func (a *MarketDataAdapter) QuarantineRepository() interfaces.QuarantineRepository {
+	return a.quarantineRepo
+}
+
+func (a *MarketDataAdapter) BackfillCheckpointRepository() interfaces.BackfillCheckpointRepository {
+	return a.backfillRepo
+}
+
+func (a *MarketDataAdapter) MarketDataPublisher() interfaces.MarketDataPublisher {
+	return a.publisher
+}
+
+func (a *MarketDataAdapter) EventLog() interfaces.EventLog {
+	return a.eventLog
+}
+
+// ChangeFeed returns nil unless the change feed is enabled and PostgreSQL is connected
+func (a *MarketDataAdapter) ChangeFeed() interfaces.ChangeFeed {
+	if a.changeListener == nil {
+		return nil
+	}
+	return a.changeListener
+}
+
+func (a *MarketDataAdapter) Locker() interfaces.Locker {
+	return a.locker
+}
+
+// NewLeaderElector creates an elector shared by all replicas of this service instance
+func (a *MarketDataAdapter) NewLeaderElector(callbacks interfaces.LeaderCallbacks) (interfaces.LeaderElector, error) {
+	if a.redisClient == nil {
+		return nil, fmt.Errorf("leader election requires Redis")
+	}
+
+	return NewRedisLeaderElector(
+		a.redisClient.Client,
+		a.config.ServiceDiscoveryNamespace,
+		a.config.ServiceInstanceName,
+		a.config.InstanceID,
+		a.config.LeaderLeaseDuration,
+		a.config.LeaderRenewInterval,
+		callbacks,
+		a.logger,
+	), nil
+}
+
+// NewCandleBackfiller fills candle gaps from stored price feeds, then finer candles, then
+// the provider if one is given. SQLite has no checkpoint store, so runs there start over.
+func (a *MarketDataAdapter) NewCandleBackfiller(provider interfaces.HistoricalDataProvider, options ohlc.Options) (*ohlc.Backfiller, error) {
+	if a.postgresDB == nil && a.sqliteDB == nil {
+		return nil, fmt.Errorf("candle backfill requires a database")
+	}
+
+	sources := []ohlc.Source{
+		&ohlc.FeedSource{Feeds: a.priceFeedRepo},
+		&ohlc.ResampleSource{Candles: a.candleRepo},
+	}
+	if provider != nil {
+		sources = append(sources, &ohlc.ProviderSource{Provider: provider})
+	}
+	return ohlc.NewBackfiller(a.candleRepo, a.backfillRepo, sources, options, a.logger), nil
+}
+
+// buildTimescaleOptions reads the TimescaleDB settings; TIMESCALE_RETENTION takes
+// comma-separated table=age pairs with ages as in RETENTION_POLICIES
+func buildTimescaleOptions(cfg *config.Config) (database.TimescaleOptions, error) {
+	options := database.TimescaleOptions{
+		ChunkInterval:   cfg.TimescaleChunkInterval,
+		CompressAfter:   cfg.TimescaleCompressAfter,
+		Retention:       make(map[string]time.Duration),
+		AggregateSource: models.CandleInterval(cfg.TimescaleAggregateSource),
+		RefreshWindow:   cfg.TimescaleRefreshWindow,
+	}
+	for _, interval := range cfg.TimescaleAggregates {
+		options.Aggregates = append(options.Aggregates, models.CandleInterval(interval))
+	}
+
+	for _, item := range strings.Split(cfg.TimescaleRetention, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		table, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return options, fmt.Errorf("invalid TIMESCALE_RETENTION: %q: expected table=age", item)
+		}
+		age, err := retention.ParseAge(strings.TrimSpace(value))
+		if err != nil {
+			return options, fmt.Errorf("invalid TIMESCALE_RETENTION: %q: %w", item, err)
+		}
+		if age > 0 {
+			options.Retention[strings.TrimSpace(table)] = age
+		}
+	}
+
+	if err := options.Validate(); err != nil {
+		return options, fmt.Errorf("invalid TimescaleDB settings: %w", err)
+	}
+	return options, nil
+}
+
+// buildServiceInfo describes this replica for service discovery
+func buildServiceInfo(cfg *config.Config) *interfaces.ServiceInfo {
+	address := cfg.ServiceAddress
+	if address == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			address = hostname
+		}
+	}
+
+	return &interfaces.ServiceInfo{
+		ServiceName: cfg.ServiceInstanceName,
+		ServiceID:   cfg.InstanceID,
+		Address:     address,
+		Port:        cfg.ServicePort,
+		Version:     cfg.ServiceVersion,
+		Tags:        cfg.ServiceTags,
+		Weight:      cfg.ServiceWeight,
+		Health:      interfaces.HealthPassing,
+		Metadata: map[string]string{
+			"service_name": cfg.ServiceName,
+			"environment":  cfg.Environment,
+		},
+	}
+}
+
+// applyTimingDefaults fills zero durations with the same defaults LoadConfig uses,
+// so configs built directly in code don't end up with zero TTLs or intervals
+func applyTimingDefaults(cfg *config.Config) {
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = 30 * time.Second
+	}
+	if cfg.ServiceTTL <= 0 {
+		cfg.ServiceTTL = 90 * time.Second
+	}
+	if cfg.LeaderLeaseDuration <= 0 {
+		cfg.LeaderLeaseDuration = 15 * time.Second
+	}
+	if cfg.LeaderRenewInterval <= 0 {
+		cfg.LeaderRenewInterval = 5 * time.Second
+	}
+}
+
 // deriveSchemaName determines PostgreSQL schema based on service instance pattern
 // Singleton: market-data-simulator == market-data-simulator → "market_data"
 // Multi-instance: market-data-Coinmetrics → "market_data_coinmetrics"
@@ -254,3 +1055,18 @@ func deriveRedisNamespace(serviceName, instanceName string) string {
 	}
 	return instanceName
 }
+
+// UpdateServiceHealth reports this replica as passing, warning (degraded) or critical
+// while keeping it registered
+func (a *MarketDataAdapter) UpdateServiceHealth(ctx context.Context, status interfaces.HealthStatus, note string) error {
+	if a.registrationManager == nil {
+		return fmt.Errorf("service registration is not enabled")
+	}
+	return a.registrationManager.SetHealth(ctx, status, note)
+}
+
+// deriveInstanceID generates a replica identifier unique across pods of the same instance
+// Example: "market-data-Coinmetrics" -> "market-data-Coinmetrics-1f2e3d4c"
+func deriveInstanceID(instanceName string) string {
+	return instanceName + "-" + uuid.New().String()[:8]
+}
@@ -1,9 +1,15 @@
 package adapters
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -179,6 +185,27 @@ func TestDeriveRedisNamespace_EdgeCase_MultipartInstance(t *testing.T) {
 		"Multi-part entity names should preserve full entity identifier after colon")
 }
 
+// =============================================================================
+// Instance ID Derivation Tests
+// =============================================================================
+
+func TestDeriveInstanceID_PrefixedWithInstanceName(t *testing.T) {
+	result := deriveInstanceID("market-data-Coinmetrics")
+
+	assert.True(t, strings.HasPrefix(result, "market-data-Coinmetrics-"),
+		"Instance ID should start with the service instance name")
+	assert.Len(t, result, len("market-data-Coinmetrics-")+8,
+		"Instance ID should carry an 8 character random suffix")
+}
+
+func TestDeriveInstanceID_UniquePerReplica(t *testing.T) {
+	first := deriveInstanceID("market-data-simulator")
+	second := deriveInstanceID("market-data-simulator")
+
+	assert.NotEqual(t, first, second,
+		"Replicas of the same instance should receive distinct IDs")
+}
+
 // =============================================================================
 // Factory Integration Tests
 // =============================================================================
@@ -254,3 +281,255 @@ func TestNewMarketDataAdapter_HonorsExplicitSchemaAndNamespace(t *testing.T) {
 	assert.Equal(t, explicitNamespace, cfg.RedisNamespace,
 		"Explicit RedisNamespace should not be overridden")
 }
+
+func TestNewMarketDataAdapter_LeaderElectorRequiresRedis(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName:         "market-data-simulator",
+		ServiceInstanceName: "market-data-simulator",
+		PostgresURL:         "",
+		RedisURL:            "",
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	adapter, err := NewMarketDataAdapter(cfg, logger)
+	require.NoError(t, err)
+
+	elector, err := adapter.NewLeaderElector(interfaces.LeaderCallbacks{})
+
+	assert.Error(t, err, "Leader election should not be available without Redis")
+	assert.Nil(t, elector)
+	assert.NotEmpty(t, cfg.InstanceID, "InstanceID should be derived when not provided")
+}
+
+func TestNewMarketDataAdapter_AppliesTimingDefaults(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName:         "market-data-simulator",
+		ServiceInstanceName: "market-data-simulator",
+		ServiceTTL:          45 * time.Second,
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	_, err := NewMarketDataAdapter(cfg, logger)
+	require.NoError(t, err)
+
+	assert.Equal(t, 45*time.Second, cfg.ServiceTTL,
+		"Explicit ServiceTTL should not be overridden")
+	assert.Equal(t, 30*time.Second, cfg.HeartbeatInterval,
+		"Unset HeartbeatInterval should fall back to the default")
+	assert.Equal(t, 15*time.Second, cfg.LeaderLeaseDuration,
+		"Unset LeaderLeaseDuration should fall back to the default")
+}
+
+func TestNewMarketDataAdapter_RejectsHeartbeatLongerThanServiceTTL(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName:         "market-data-simulator",
+		ServiceInstanceName: "market-data-simulator",
+		RedisURL:            "redis://localhost:6379/0",
+		AutoRegisterService: true,
+		HeartbeatInterval:   time.Minute,
+		ServiceTTL:          30 * time.Second,
+	}
+
+	_, err := NewMarketDataAdapter(cfg, newQuietLogger())
+	assert.Error(t, err, "Registrations would expire between heartbeats")
+}
+
+func TestNewMarketDataAdapter_OutboxSinkResolution(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName:         "market-data-simulator",
+		ServiceInstanceName: "market-data-simulator",
+		OutboxEnabled:       true,
+		OutboxSink:          "callback",
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	adapter, err := NewMarketDataAdapter(cfg, logger)
+	require.NoError(t, err)
+	marketDataAdapter := adapter.(*MarketDataAdapter)
+
+	_, err = marketDataAdapter.buildEventSink()
+	assert.Error(t, err, "Callback sink should require SetEventSink")
+
+	callback := interfaces.EventSinkFunc(func(ctx context.Context, event *interfaces.OutboxEvent) error { return nil })
+	adapter.SetEventSink(callback)
+	sink, err := marketDataAdapter.buildEventSink()
+	require.NoError(t, err)
+	assert.NotNil(t, sink)
+
+	cfg.OutboxSink = "stream"
+	marketDataAdapter.eventSink = nil
+	_, err = marketDataAdapter.buildEventSink()
+	assert.Error(t, err, "Stream sink should require Redis")
+}
+
+func TestNewMarketDataAdapter_TimescaleSettings(t *testing.T) {
+	cfg := &config.Config{
+		ServiceName:              "market-data-simulator",
+		ServiceInstanceName:      "market-data-simulator",
+		TimescaleEnabled:         true,
+		TimescaleRetention:       "price_feeds=30d, candles=forever, market_snapshots=12h",
+		TimescaleAggregateSource: "1m",
+		TimescaleAggregates:      []string{"1h", "1d"},
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	adapter, err := NewMarketDataAdapter(cfg, logger)
+	require.NoError(t, err)
+	options := adapter.(*MarketDataAdapter).timescaleOptions
+	assert.Equal(t, map[string]time.Duration{"price_feeds": 30 * 24 * time.Hour, "market_snapshots": 12 * time.Hour}, options.Retention,
+		"Tables kept forever should have no retention policy")
+	assert.Equal(t, []models.CandleInterval{models.Interval1h, models.Interval1d}, options.Aggregates)
+
+	cfg.TimescaleAggregates = []string{"1m"}
+	_, err = NewMarketDataAdapter(cfg, logger)
+	assert.Error(t, err, "Aggregates must be coarser than the source interval")
+
+	cfg.TimescaleAggregates = nil
+	cfg.TimescaleRetention = "symbols=30d"
+	_, err = NewMarketDataAdapter(cfg, logger)
+	assert.Error(t, err, "Only hypertables take retention")
+
+	cfg.TimescaleRetention = ""
+	cfg.PartitioningEnabled = true
+	_, err = NewMarketDataAdapter(cfg, logger)
+	assert.Error(t, err, "Hypertables replace native partitioning")
+}
+
+func TestNewMarketDataAdapter_DatabaseURLSelectsBackend(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := &config.Config{
+		ServiceName:         "market-data-simulator",
+		ServiceInstanceName: "market-data-simulator",
+		DatabaseURL:         "sqlite::memory:",
+		PostgresURL:         "postgres://localhost/market_data",
+		AutoMigrate:         true,
+	}
+	adapter, err := NewMarketDataAdapter(cfg, logger)
+	require.NoError(t, err)
+	md := adapter.(*MarketDataAdapter)
+	assert.NotNil(t, md.sqliteDB)
+	assert.Nil(t, md.postgresDB, "A sqlite: URL replaces PostgreSQL")
+
+	ctx := context.Background()
+	require.NoError(t, adapter.Connect(ctx))
+	defer adapter.Disconnect(ctx)
+	require.NoError(t, adapter.HealthCheck(ctx))
+	_, err = adapter.PriceFeedRepository().Create(ctx, &models.PriceFeed{Symbol: "BTC-USD", Price: decimal.RequireFromString("65000.5"), Source: "test", Timestamp: time.Now()})
+	require.NoError(t, err)
+	assert.Nil(t, adapter.DataQualityRepository())
+
+	cfg = &config.Config{DatabaseURL: "postgresql://db/market_data"}
+	adapter, err = NewMarketDataAdapter(cfg, logger)
+	require.NoError(t, err)
+	assert.NotNil(t, adapter.(*MarketDataAdapter).postgresDB)
+	assert.Equal(t, "postgresql://db/market_data", cfg.PostgresURL)
+
+	_, err = NewMarketDataAdapter(&config.Config{DatabaseURL: "mysql://db/market_data"}, logger)
+	assert.ErrorContains(t, err, "unsupported scheme")
+
+	_, err = NewMarketDataAdapter(&config.Config{DatabaseURL: "sqlite::memory:", OutboxEnabled: true, ChangeFeedEnabled: true}, logger)
+	assert.ErrorContains(t, err, "OUTBOX_ENABLED, CHANGE_FEED_ENABLED", "Features that need PostgreSQL are rejected")
+}
+
+func TestNewMarketDataAdapter_FreshnessMonitorOnSQLite(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	adapter, err := NewMarketDataAdapter(&config.Config{
+		DatabaseURL:            "sqlite::memory:",
+		AutoMigrate:            true,
+		FreshnessEnabled:       true,
+		FreshnessDefaultSLA:    time.Second,
+		FreshnessCheckInterval: 10 * time.Millisecond,
+	}, logger)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, adapter.Connect(ctx))
+	defer adapter.Disconnect(ctx)
+
+	_, err = adapter.PriceFeedRepository().Create(ctx, &models.PriceFeed{
+		Symbol: "BTC-USD", Price: decimal.NewFromInt(65000), Source: "test", Timestamp: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	// Without a data quality store the stale transition is only reported
+	md := adapter.(*MarketDataAdapter)
+	require.Eventually(t, func() bool {
+		report := md.FreshnessReport()
+		return report != nil && report.Stale == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, adapter.HealthCheck(ctx), interfaces.ErrDegraded)
+}
+
+func TestNewMarketDataAdapter_RemovedSourceStopsDegradingHealth(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	adapter, err := NewMarketDataAdapter(&config.Config{
+		DatabaseURL:            "sqlite::memory:",
+		AutoMigrate:            true,
+		FreshnessEnabled:       true,
+		FreshnessDefaultSLA:    500 * time.Millisecond,
+		FreshnessCheckInterval: 10 * time.Millisecond,
+		FreshnessExpiry:        2 * time.Second,
+	}, logger)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, adapter.Connect(ctx))
+	defer adapter.Disconnect(ctx)
+
+	// The source's last price, after which it is retired
+	_, err = adapter.PriceFeedRepository().Create(ctx, &models.PriceFeed{
+		Symbol: "BTC-USD", Price: decimal.NewFromInt(65000), Source: "retired", Timestamp: time.Now().Add(-time.Second),
+	})
+	require.NoError(t, err)
+
+	md := adapter.(*MarketDataAdapter)
+	require.Eventually(t, func() bool {
+		report := md.FreshnessReport()
+		return report != nil && report.Stale == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, adapter.HealthCheck(ctx), interfaces.ErrDegraded)
+
+	require.Eventually(t, func() bool {
+		report := md.FreshnessReport()
+		return report != nil && report.Stale == 0
+	}, 3*time.Second, 10*time.Millisecond, "The retired source should expire")
+	assert.NoError(t, adapter.HealthCheck(ctx))
+}
+
+func TestNewMarketDataAdapterFromEnv_SQLiteWithDefaults(t *testing.T) {
+	t.Setenv("DATABASE_URL", "sqlite://"+t.TempDir()+"/market-data.db")
+	t.Setenv("AUTO_MIGRATE", "true")
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	// The defaults enable gap detection, which SQLite skips
+	adapter, err := NewMarketDataAdapterFromEnv(logger)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, adapter.Connect(ctx))
+	defer adapter.Disconnect(ctx)
+
+	result, err := adapter.PriceFeedRepository().Create(ctx, &models.PriceFeed{
+		Symbol: "ETH-USD", Price: decimal.RequireFromString("3200.25"), Source: "test", Timestamp: time.Now(),
+	})
+	require.NoError(t, err)
+	stored, err := adapter.PriceFeedRepository().GetByID(ctx, result.FeedID)
+	require.NoError(t, err)
+	assert.Equal(t, "3200.25", stored.Price.String())
+}
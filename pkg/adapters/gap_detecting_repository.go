@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/quality"
+	"github.com/sirupsen/logrus"
+)
+
+// GapDetectingPriceFeedRepository checks the sequence of every newly stored price feed
+// and records gaps and out-of-order arrivals as data-quality events. Duplicates are not
+// checked, and recording failures are logged rather than returned: the write already succeeded.
+// In a transaction the check waits for the commit, so a rolled-back feed is never observed.
+type GapDetectingPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	detector    *quality.GapDetector
+	qualityRepo interfaces.DataQualityRepository
+	afterCommit *afterCommit
+	logger      *logrus.Logger
+}
+
+func NewGapDetectingPriceFeedRepository(repo interfaces.PriceFeedRepository, detector *quality.GapDetector, qualityRepo interfaces.DataQualityRepository, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return newGapDetectingPriceFeedRepository(repo, detector, qualityRepo, nil, logger)
+}
+
+func newGapDetectingPriceFeedRepository(repo interfaces.PriceFeedRepository, detector *quality.GapDetector, qualityRepo interfaces.DataQualityRepository, hooks *afterCommit, logger *logrus.Logger) *GapDetectingPriceFeedRepository {
+	return &GapDetectingPriceFeedRepository{
+		PriceFeedRepository: repo,
+		detector:            detector,
+		qualityRepo:         qualityRepo,
+		afterCommit:         hooks,
+		logger:              logger,
+	}
+}
+
+func (r *GapDetectingPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	result, err := r.PriceFeedRepository.Create(ctx, feed)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.Duplicate {
+		r.check(ctx, feed)
+	}
+	return result, nil
+}
+
+func (r *GapDetectingPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results, err := r.PriceFeedRepository.CreateBatch(ctx, feeds)
+	if err != nil {
+		return results, err
+	}
+
+	for i, result := range results {
+		if !result.Duplicate && !result.Rejected {
+			r.check(ctx, feeds[i])
+		}
+	}
+	return results, nil
+}
+
+func (r *GapDetectingPriceFeedRepository) check(ctx context.Context, feed *models.PriceFeed) {
+	r.afterCommit.do(ctx, func(ctx context.Context) {
+		r.record(ctx, feed)
+	})
+}
+
+func (r *GapDetectingPriceFeedRepository) record(ctx context.Context, feed *models.PriceFeed) {
+	event := r.detector.ObserveFeed(feed)
+	if event == nil {
+		return
+	}
+
+	logger := r.logger.WithFields(logrus.Fields{
+		"type":              event.Type,
+		"source":            event.Source,
+		"symbol":            event.Symbol,
+		"expected_sequence": event.ExpectedSequence,
+		"received_sequence": event.ReceivedSequence,
+	})
+	logger.Warn("Price feed sequence anomaly detected")
+
+	if err := r.qualityRepo.Create(ctx, event); err != nil {
+		logger.WithError(err).Warn("Failed to record data quality event")
+	}
+}
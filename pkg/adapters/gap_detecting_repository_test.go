@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/quality"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDataQualityRepository records created events and fails with createErr if set
+type recordingDataQualityRepository struct {
+	interfaces.DataQualityRepository
+	createErr error
+	events    []*models.DataQualityEvent
+}
+
+func (r *recordingDataQualityRepository) Create(ctx context.Context, event *models.DataQualityEvent) error {
+	r.events = append(r.events, event)
+	return r.createErr
+}
+
+func sequencedFeed(source string, sequence int64) *models.PriceFeed {
+	return &models.PriceFeed{Symbol: "BTC-USD", Source: source, Sequence: &sequence, Timestamp: time.Now()}
+}
+
+func TestGapDetectingPriceFeedRepository_RecordsGaps(t *testing.T) {
+	qualityRepo := &recordingDataQualityRepository{}
+	repo := NewGapDetectingPriceFeedRepository(&stubPriceFeedRepository{}, quality.NewGapDetector(), qualityRepo, newQuietLogger())
+
+	_, err := repo.CreateBatch(context.Background(), []*models.PriceFeed{
+		sequencedFeed("coinbase", 1),
+		sequencedFeed("coinbase", 2),
+		sequencedFeed("coinbase", 5),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, qualityRepo.events, 1)
+	assert.Equal(t, models.QualitySequenceGap, qualityRepo.events[0].Type)
+	assert.Equal(t, int64(2), qualityRepo.events[0].Missing)
+}
+
+func TestGapDetectingPriceFeedRepository_IgnoresDuplicatesAndRecordingFailures(t *testing.T) {
+	qualityRepo := &recordingDataQualityRepository{createErr: errors.New("table missing")}
+	repo := NewGapDetectingPriceFeedRepository(&stubPriceFeedRepository{}, quality.NewGapDetector(), qualityRepo, newQuietLogger())
+
+	key := models.SequenceIdempotencyKey("coinbase", 3)
+	first := sequencedFeed("coinbase", 3)
+	first.IdempotencyKey = &key
+	replay := sequencedFeed("coinbase", 3)
+	replay.IdempotencyKey = &key
+
+	_, err := repo.Create(context.Background(), first)
+	require.NoError(t, err)
+	result, err := repo.Create(context.Background(), replay)
+	require.NoError(t, err)
+	assert.True(t, result.Duplicate)
+	assert.Empty(t, qualityRepo.events, "A replay is not an out-of-order arrival")
+
+	_, err = repo.Create(context.Background(), sequencedFeed("coinbase", 9))
+	assert.NoError(t, err, "Recording failures must not fail the write")
+	assert.Len(t, qualityRepo.events, 1)
+}
+
+func TestGapDetectingPriceFeedRepository_WaitsForCommit(t *testing.T) {
+	qualityRepo := &recordingDataQualityRepository{}
+	detector := quality.NewGapDetector()
+	ctx := context.Background()
+
+	// A rolled-back transaction never reaches the detector, so its retry is not out of order
+	rolledBack := &afterCommit{}
+	_, err := newGapDetectingPriceFeedRepository(&stubPriceFeedRepository{}, detector, qualityRepo, rolledBack, newQuietLogger()).
+		CreateBatch(ctx, []*models.PriceFeed{sequencedFeed("coinbase", 1), sequencedFeed("coinbase", 2)})
+	require.NoError(t, err)
+
+	committed := &afterCommit{}
+	_, err = newGapDetectingPriceFeedRepository(&stubPriceFeedRepository{}, detector, qualityRepo, committed, newQuietLogger()).
+		CreateBatch(ctx, []*models.PriceFeed{sequencedFeed("coinbase", 1), sequencedFeed("coinbase", 2)})
+	require.NoError(t, err)
+	committed.run(ctx)
+
+	assert.Empty(t, qualityRepo.events)
+}
@@ -0,0 +1,229 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+)
+
+type memoryPendingEntry struct {
+	consumer    string
+	deliveredAt time.Time
+}
+
+type memoryConsumerGroup struct {
+	lastDelivered int64 // Sequence of the last entry handed out with ">"
+	pending       map[int64]*memoryPendingEntry
+}
+
+type memoryStream struct {
+	entries []*interfaces.LogEvent
+	seqs    []int64
+	nextSeq int64
+	groups  map[string]*memoryConsumerGroup
+}
+
+// MemoryEventLog is an in-process EventLog with the same delivery semantics as
+// RedisEventLog (consumer groups, pending entries, claim, trimming), intended for tests
+type MemoryEventLog struct {
+	mu      sync.Mutex
+	streams map[interfaces.EventStream]*memoryStream
+	maxLen  int64
+	notify  chan struct{}
+	now     func() time.Time
+}
+
+func NewMemoryEventLog(maxLen int64) interfaces.EventLog {
+	return &MemoryEventLog{
+		streams: make(map[interfaces.EventStream]*memoryStream),
+		maxLen:  maxLen,
+		notify:  make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// stream returns the named stream, creating it if needed; m.mu must be held
+func (m *MemoryEventLog) stream(name interfaces.EventStream) *memoryStream {
+	s, ok := m.streams[name]
+	if !ok {
+		s = &memoryStream{nextSeq: 1, groups: make(map[string]*memoryConsumerGroup)}
+		m.streams[name] = s
+	}
+	return s
+}
+
+func (m *MemoryEventLog) Append(ctx context.Context, event *interfaces.LogEvent) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stream(event.Stream)
+	seq := s.nextSeq
+	s.nextSeq++
+
+	event.ID = fmt.Sprintf("%d-%d", m.now().UnixMilli(), seq)
+	stored := *event
+	s.entries = append(s.entries, &stored)
+	s.seqs = append(s.seqs, seq)
+
+	if m.maxLen > 0 {
+		m.trim(s, m.maxLen)
+	}
+
+	// Wake blocked readers
+	close(m.notify)
+	m.notify = make(chan struct{})
+
+	return event.ID, nil
+}
+
+func (m *MemoryEventLog) EnsureGroup(ctx context.Context, stream interfaces.EventStream, group string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stream(stream)
+	if _, ok := s.groups[group]; !ok {
+		s.groups[group] = &memoryConsumerGroup{pending: make(map[int64]*memoryPendingEntry)}
+	}
+	return nil
+}
+
+func (m *MemoryEventLog) Read(ctx context.Context, stream interfaces.EventStream, group, consumer string, count int, block time.Duration) ([]*interfaces.LogEvent, error) {
+	var deadline <-chan time.Time
+	if block > 0 {
+		timer := time.NewTimer(block)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		m.mu.Lock()
+		events, err := m.deliver(stream, group, consumer, count)
+		notify := m.notify
+		m.mu.Unlock()
+
+		if err != nil || len(events) > 0 || deadline == nil {
+			return events, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return []*interfaces.LogEvent{}, nil
+		case <-notify:
+		}
+	}
+}
+
+// deliver hands out entries after the group's last delivered one; m.mu must be held
+func (m *MemoryEventLog) deliver(stream interfaces.EventStream, group, consumer string, count int) ([]*interfaces.LogEvent, error) {
+	s := m.stream(stream)
+	g, ok := s.groups[group]
+	if !ok {
+		return nil, fmt.Errorf("failed to read events: consumer group %s does not exist", group)
+	}
+
+	events := []*interfaces.LogEvent{}
+	now := m.now()
+	for i, seq := range s.seqs {
+		if seq <= g.lastDelivered {
+			continue
+		}
+		if count > 0 && len(events) >= count {
+			break
+		}
+
+		g.lastDelivered = seq
+		g.pending[seq] = &memoryPendingEntry{consumer: consumer, deliveredAt: now}
+		event := *s.entries[i]
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+func (m *MemoryEventLog) Ack(ctx context.Context, stream interfaces.EventStream, group string, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, ok := m.stream(stream).groups[group]
+	if !ok {
+		return fmt.Errorf("failed to acknowledge events: consumer group %s does not exist", group)
+	}
+
+	for _, id := range ids {
+		seq, err := parseMemorySeq(id)
+		if err != nil {
+			return fmt.Errorf("failed to acknowledge events: %w", err)
+		}
+		delete(g.pending, seq)
+	}
+	return nil
+}
+
+func (m *MemoryEventLog) Claim(ctx context.Context, stream interfaces.EventStream, group, consumer string, minIdle time.Duration, count int) ([]*interfaces.LogEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stream(stream)
+	g, ok := s.groups[group]
+	if !ok {
+		return nil, fmt.Errorf("failed to claim pending events: consumer group %s does not exist", group)
+	}
+
+	events := []*interfaces.LogEvent{}
+	now := m.now()
+	for i, seq := range s.seqs {
+		if count > 0 && len(events) >= count {
+			break
+		}
+
+		entry, pending := g.pending[seq]
+		if !pending || now.Sub(entry.deliveredAt) < minIdle {
+			continue
+		}
+
+		entry.consumer = consumer
+		entry.deliveredAt = now
+		event := *s.entries[i]
+		events = append(events, &event)
+	}
+
+	// Like XAUTOCLAIM, pending entries whose data was trimmed away are dropped
+	for seq := range g.pending {
+		if len(s.seqs) == 0 || seq < s.seqs[0] {
+			delete(g.pending, seq)
+		}
+	}
+
+	return events, nil
+}
+
+func (m *MemoryEventLog) Trim(ctx context.Context, stream interfaces.EventStream, maxLen int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.trim(m.stream(stream), maxLen), nil
+}
+
+// trim drops the oldest entries beyond maxLen; m.mu must be held
+func (m *MemoryEventLog) trim(s *memoryStream, maxLen int64) int64 {
+	excess := int64(len(s.entries)) - maxLen
+	if excess <= 0 {
+		return 0
+	}
+
+	s.entries = s.entries[excess:]
+	s.seqs = s.seqs[excess:]
+	return excess
+}
+
+func parseMemorySeq(id string) (int64, error) {
+	var ms, seq int64
+	if _, err := fmt.Sscanf(id, "%d-%d", &ms, &seq); err != nil {
+		return 0, fmt.Errorf("invalid event ID %q", id)
+	}
+	return seq, nil
+}
@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendPriceFeed(t *testing.T, log interfaces.EventLog, symbol string) string {
+	t.Helper()
+
+	event, err := interfaces.NewPriceFeedEvent(&models.PriceFeed{Symbol: symbol, Price: decimal.NewFromInt(100)})
+	require.NoError(t, err)
+
+	id, err := log.Append(context.Background(), event)
+	require.NoError(t, err)
+	return id
+}
+
+func TestMemoryEventLog_GroupsDeliverEachEventOnce(t *testing.T) {
+	log := NewMemoryEventLog(0)
+	ctx := context.Background()
+
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamPriceFeeds, "analytics"))
+	appendPriceFeed(t, log, "BTC-USD")
+	appendPriceFeed(t, log, "ETH-USD")
+
+	first, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-1", 1, 0)
+	require.NoError(t, err)
+	second, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", 10, 0)
+	require.NoError(t, err)
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	assert.Equal(t, "BTC-USD", first[0].Symbol)
+	assert.Equal(t, "ETH-USD", second[0].Symbol)
+
+	var feed models.PriceFeed
+	require.NoError(t, second[0].Decode(&feed))
+	assert.True(t, feed.Price.Equal(decimal.NewFromInt(100)))
+}
+
+func TestMemoryEventLog_ReadBlocksUntilAppend(t *testing.T) {
+	log := NewMemoryEventLog(0)
+	ctx := context.Background()
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamPriceFeeds, "analytics"))
+
+	event, err := interfaces.NewPriceFeedEvent(&models.PriceFeed{Symbol: "BTC-USD"})
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = log.Append(ctx, event)
+	}()
+
+	events, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-1", 10, time.Second)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-1", 10, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, events, "Read should time out empty when nothing new arrives")
+}
+
+func TestMemoryEventLog_ClaimReclaimsUnacknowledged(t *testing.T) {
+	log := NewMemoryEventLog(0).(*MemoryEventLog)
+	ctx := context.Background()
+	now := time.Now()
+	log.now = func() time.Time { return now }
+
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamPriceFeeds, "analytics"))
+	ackedID := appendPriceFeed(t, log, "BTC-USD")
+	lostID := appendPriceFeed(t, log, "ETH-USD")
+
+	_, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-1", 10, 0)
+	require.NoError(t, err)
+	require.NoError(t, log.Ack(ctx, interfaces.StreamPriceFeeds, "analytics", ackedID))
+
+	claimed, err := log.Claim(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "Entries should not be claimable before minIdle")
+
+	now = now.Add(2 * time.Minute)
+	claimed, err = log.Claim(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, lostID, claimed[0].ID)
+}
+
+func TestMemoryEventLog_TrimsToMaxLen(t *testing.T) {
+	log := NewMemoryEventLog(2)
+	ctx := context.Background()
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamCandles, "late"))
+
+	for _, symbol := range []string{"BTC-USD", "ETH-USD", "SOL-USD"} {
+		event, err := interfaces.NewCandleEvent(&models.Candle{Symbol: symbol})
+		require.NoError(t, err)
+		_, err = log.Append(ctx, event)
+		require.NoError(t, err)
+	}
+
+	events, err := log.Read(ctx, interfaces.StreamCandles, "late", "worker-1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "ETH-USD", events[0].Symbol)
+
+	removed, err := log.Trim(ctx, interfaces.StreamCandles, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+}
@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+)
+
+type memoryLockEntry struct {
+	token     string
+	expiresAt time.Time
+}
+
+// MemoryLocker is an in-process Locker with the same semantics as RedisLocker, intended for tests
+type MemoryLocker struct {
+	mu     sync.Mutex
+	locks  map[string]memoryLockEntry
+	fences map[string]int64
+	now    func() time.Time
+}
+
+func NewMemoryLocker() interfaces.Locker {
+	return &MemoryLocker{
+		locks:  make(map[string]memoryLockEntry),
+		fences: make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+func (m *MemoryLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (*interfaces.Lock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if entry, ok := m.locks[key]; ok && now.Before(entry.expiresAt) {
+		return nil, interfaces.ErrLockNotAcquired
+	}
+
+	token := uuid.New().String()
+	m.locks[key] = memoryLockEntry{token: token, expiresAt: now.Add(ttl)}
+	m.fences[key]++
+
+	return &interfaces.Lock{
+		Key:          key,
+		Token:        token,
+		FencingToken: m.fences[key],
+		AcquiredAt:   now,
+		ExpiresAt:    now.Add(ttl),
+	}, nil
+}
+
+func (m *MemoryLocker) Release(ctx context.Context, lock *interfaces.Lock) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.holds(lock) {
+		return interfaces.ErrLockNotHeld
+	}
+
+	delete(m.locks, lock.Key)
+	return nil
+}
+
+func (m *MemoryLocker) Renew(ctx context.Context, lock *interfaces.Lock, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.holds(lock) {
+		return interfaces.ErrLockNotHeld
+	}
+
+	expiresAt := m.now().Add(ttl)
+	m.locks[lock.Key] = memoryLockEntry{token: lock.Token, expiresAt: expiresAt}
+	lock.ExpiresAt = expiresAt
+	return nil
+}
+
+// holds reports whether the lock is unexpired and still owned by the caller; m.mu must be held
+func (m *MemoryLocker) holds(lock *interfaces.Lock) bool {
+	entry, ok := m.locks[lock.Key]
+	return ok && entry.token == lock.Token && m.now().Before(entry.expiresAt)
+}
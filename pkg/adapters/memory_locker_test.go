@@ -0,0 +1,64 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLocker_AcquireIsExclusive(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	lock, err := locker.Acquire(ctx, "retention", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+
+	_, err = locker.Acquire(ctx, "retention", time.Minute)
+	assert.ErrorIs(t, err, interfaces.ErrLockNotAcquired,
+		"Second acquire should fail while the lock is held")
+
+	_, err = locker.Acquire(ctx, "materialization", time.Minute)
+	assert.NoError(t, err, "Different keys should not contend")
+}
+
+func TestMemoryLocker_FencingTokensIncrease(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	first, err := locker.Acquire(ctx, "retention", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, locker.Release(ctx, first))
+
+	second, err := locker.Acquire(ctx, "retention", time.Minute)
+	require.NoError(t, err)
+
+	assert.Greater(t, second.FencingToken, first.FencingToken,
+		"Each acquisition should receive a strictly larger fencing token")
+}
+
+func TestMemoryLocker_ExpiredLockCanBeTakenOver(t *testing.T) {
+	locker := NewMemoryLocker().(*MemoryLocker)
+	ctx := context.Background()
+
+	now := time.Now()
+	locker.now = func() time.Time { return now }
+
+	stale, err := locker.Acquire(ctx, "retention", time.Second)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Second)
+
+	fresh, err := locker.Acquire(ctx, "retention", time.Second)
+	require.NoError(t, err, "Expired lock should be acquirable")
+
+	assert.ErrorIs(t, locker.Release(ctx, stale), interfaces.ErrLockNotHeld,
+		"Previous owner must not release a lock it no longer holds")
+	assert.ErrorIs(t, locker.Renew(ctx, stale, time.Second), interfaces.ErrLockNotHeld,
+		"Previous owner must not renew a lock it no longer holds")
+	assert.NoError(t, locker.Renew(ctx, fresh, time.Second))
+}
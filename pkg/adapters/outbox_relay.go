@@ -0,0 +1,288 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+type OutboxRelayOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Retention    time.Duration // Delivered rows older than this are purged
+	MaxAttempts  int           // Failed deliveries before an event is dead-lettered
+}
+
+// OutboxRelay delivers pending outbox rows to an EventSink in insertion order.
+//
+// Delivery is at-least-once: a row is marked delivered only after the sink accepts it,
+// so a crash in between redelivers it with the same event ID. A failed delivery stops
+// the batch, keeping later events for the same symbol behind the one being retried,
+// until the event reaches MaxAttempts; it is then dead-lettered and skipped so it cannot
+// block the outbox. Dead-lettered rows are kept for inspection and never purged.
+//
+// Replicas share the outbox through a transaction-scoped advisory lock, so only one
+// relays at a time. The sink is called while that transaction is open, on purpose: the
+// lock is what stops a second replica relaying the same rows out of order, and Postgres
+// drops it if the relay dies mid-batch, where in-flight markers would need a lease and a
+// reaper. Writers only insert into the outbox, so the row locks never block them; the
+// cost is one transaction per batch that stays open for up to BatchSize sink calls.
+type OutboxRelay struct {
+	db      *sql.DB
+	table   string
+	sink    interfaces.EventSink
+	options OutboxRelayOptions
+	logger  *logrus.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewOutboxRelay(db *sql.DB, schema string, sink interfaces.EventSink, options OutboxRelayOptions, logger *logrus.Logger) *OutboxRelay {
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 10
+	}
+
+	return &OutboxRelay{
+		db:      db,
+		table:   qualifiedTable(schema, "outbox"),
+		sink:    sink,
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return fmt.Errorf("outbox relay already started")
+	}
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.relayLoop(loopCtx, r.done)
+
+	r.logger.WithFields(logrus.Fields{
+		"poll_interval": r.options.PollInterval,
+		"batch_size":    r.options.BatchSize,
+		"max_attempts":  r.options.MaxAttempts,
+	}).Info("Outbox relay started")
+	return nil
+}
+
+func (r *OutboxRelay) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	r.logger.Info("Outbox relay stopped")
+	return nil
+}
+
+func (r *OutboxRelay) relayLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.options.PollInterval)
+	defer ticker.Stop()
+
+	var lastPurge time.Time
+	for {
+		// Drain full batches back to back before waiting for the next tick
+		for {
+			delivered, err := r.RelayOnce(ctx)
+			if err != nil && ctx.Err() == nil {
+				r.logger.WithError(err).Warn("Outbox relay pass failed")
+			}
+			if err != nil || delivered < r.options.BatchSize {
+				break
+			}
+		}
+
+		if r.options.Retention > 0 && time.Since(lastPurge) >= time.Minute {
+			if _, err := r.Purge(ctx); err != nil && ctx.Err() == nil {
+				r.logger.WithError(err).Warn("Failed to purge delivered outbox events")
+			}
+			lastPurge = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce delivers up to one batch of pending events and returns how many were delivered
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin outbox transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", r.table).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock outbox: %w", err)
+	}
+	if !locked {
+		// Another replica is relaying
+		return 0, nil
+	}
+
+	pending, err := r.pending(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered, err := r.deliver(ctx, &txOutboxMarker{tx: tx, table: r.table}, pending)
+	if err != nil {
+		return delivered, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox transaction: %w", err)
+	}
+	return delivered, nil
+}
+
+// pendingOutboxEvent is an undelivered outbox row and its failed delivery count
+type pendingOutboxEvent struct {
+	id       int64
+	attempts int
+	event    *interfaces.OutboxEvent
+}
+
+// outboxMarker records delivery outcomes on outbox rows
+type outboxMarker interface {
+	markDelivered(ctx context.Context, id int64) error
+	markFailed(ctx context.Context, id int64, cause string, deadLetter bool) error
+}
+
+type txOutboxMarker struct {
+	tx    *sql.Tx
+	table string
+}
+
+func (m *txOutboxMarker) markDelivered(ctx context.Context, id int64) error {
+	_, err := m.tx.ExecContext(ctx,
+		"UPDATE "+m.table+" SET attempts = attempts + 1, delivered_at = NOW(), last_error = NULL WHERE id = $1",
+		id,
+	)
+	return err
+}
+
+func (m *txOutboxMarker) markFailed(ctx context.Context, id int64, cause string, deadLetter bool) error {
+	_, err := m.tx.ExecContext(ctx,
+		"UPDATE "+m.table+" SET attempts = attempts + 1, last_error = $2, dead_lettered_at = CASE WHEN $3 THEN NOW() END WHERE id = $1",
+		id, cause, deadLetter,
+	)
+	return err
+}
+
+// deliver hands events to the sink in order, stopping at the first failure unless that
+// failure exhausts the event's attempts
+func (r *OutboxRelay) deliver(ctx context.Context, marker outboxMarker, pending []pendingOutboxEvent) (int, error) {
+	delivered := 0
+	for _, p := range pending {
+		if err := r.sink.Deliver(ctx, p.event); err != nil {
+			deadLetter := p.attempts+1 >= r.options.MaxAttempts
+			fields := logrus.Fields{
+				"event_id":  p.event.EventID,
+				"aggregate": p.event.Aggregate,
+				"attempts":  p.attempts + 1,
+			}
+
+			if markErr := marker.markFailed(ctx, p.id, err.Error(), deadLetter); markErr != nil {
+				return delivered, fmt.Errorf("failed to record outbox delivery failure: %w", markErr)
+			}
+			if !deadLetter {
+				r.logger.WithError(err).WithFields(fields).Warn("Failed to deliver outbox event, will retry")
+				break
+			}
+			r.logger.WithError(err).WithFields(fields).Error("Failed to deliver outbox event, dead-lettering it")
+			continue
+		}
+
+		if err := marker.markDelivered(ctx, p.id); err != nil {
+			return delivered, fmt.Errorf("failed to mark outbox event delivered: %w", err)
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (r *OutboxRelay) pending(ctx context.Context, tx *sql.Tx) ([]pendingOutboxEvent, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, attempts, event_id, aggregate, aggregate_id, operation, COALESCE(symbol, ''), payload, created_at
+		FROM `+r.table+`
+		WHERE delivered_at IS NULL AND dead_lettered_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE`, r.options.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []pendingOutboxEvent
+	for rows.Next() {
+		var p pendingOutboxEvent
+		var event interfaces.OutboxEvent
+		var payload []byte
+		if err := rows.Scan(&p.id, &p.attempts, &event.EventID, &event.Aggregate, &event.AggregateID, &event.Operation,
+			&event.Symbol, &payload, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		event.Payload = payload
+		p.event = &event
+		pending = append(pending, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pending outbox events: %w", err)
+	}
+
+	return pending, nil
+}
+
+// Purge deletes delivered events older than the retention period
+func (r *OutboxRelay) Purge(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM "+r.table+" WHERE delivered_at IS NOT NULL AND delivered_at < $1",
+		time.Now().Add(-r.options.Retention),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingOutboxMarker struct {
+	delivered    []int64
+	failed       []int64
+	deadLettered []int64
+}
+
+func (m *recordingOutboxMarker) markDelivered(ctx context.Context, id int64) error {
+	m.delivered = append(m.delivered, id)
+	return nil
+}
+
+func (m *recordingOutboxMarker) markFailed(ctx context.Context, id int64, cause string, deadLetter bool) error {
+	m.failed = append(m.failed, id)
+	if deadLetter {
+		m.deadLettered = append(m.deadLettered, id)
+	}
+	return nil
+}
+
+func newPoisonedRelay(t *testing.T) (*OutboxRelay, []pendingOutboxEvent) {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sink := interfaces.EventSinkFunc(func(ctx context.Context, event *interfaces.OutboxEvent) error {
+		if event.AggregateID == "poison" {
+			return errors.New("payload rejected")
+		}
+		return nil
+	})
+	relay := NewOutboxRelay(nil, "market_data", sink, OutboxRelayOptions{MaxAttempts: 3}, logger)
+
+	pending := []pendingOutboxEvent{
+		{id: 1, event: &interfaces.OutboxEvent{EventID: "e1", AggregateID: "poison"}},
+		{id: 2, event: &interfaces.OutboxEvent{EventID: "e2", AggregateID: "feed-2"}},
+		{id: 3, event: &interfaces.OutboxEvent{EventID: "e3", AggregateID: "feed-3"}},
+	}
+	return relay, pending
+}
+
+func TestOutboxRelay_FailedEventHoldsBackLaterEvents(t *testing.T) {
+	relay, pending := newPoisonedRelay(t)
+	pending[0].attempts = 1
+	marker := &recordingOutboxMarker{}
+
+	delivered, err := relay.deliver(context.Background(), marker, pending)
+	require.NoError(t, err)
+
+	assert.Zero(t, delivered)
+	assert.Equal(t, []int64{1}, marker.failed)
+	assert.Empty(t, marker.deadLettered, "An event with attempts left should be retried")
+	assert.Empty(t, marker.delivered)
+}
+
+func TestOutboxRelay_DeadLettersPoisonEvent(t *testing.T) {
+	relay, pending := newPoisonedRelay(t)
+	pending[0].attempts = 2
+	marker := &recordingOutboxMarker{}
+
+	delivered, err := relay.deliver(context.Background(), marker, pending)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, delivered)
+	assert.Equal(t, []int64{1}, marker.deadLettered, "The last allowed attempt should dead-letter the event")
+	assert.Equal(t, []int64{2, 3}, marker.delivered, "Events behind a dead-lettered one should be delivered")
+}
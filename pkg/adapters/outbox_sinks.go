@@ -0,0 +1,101 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+// PublisherEventSink relays price feeds and snapshots over pub/sub; candle and symbol
+// events have no pub/sub representation and are acknowledged without publishing
+type PublisherEventSink struct {
+	publisher interfaces.MarketDataPublisher
+}
+
+func NewPublisherEventSink(publisher interfaces.MarketDataPublisher) interfaces.EventSink {
+	return &PublisherEventSink{publisher: publisher}
+}
+
+func (s *PublisherEventSink) Deliver(ctx context.Context, event *interfaces.OutboxEvent) error {
+	update := &interfaces.MarketDataUpdate{
+		EventID: event.EventID,
+		Symbol:  event.Symbol,
+	}
+
+	switch event.Aggregate {
+	case interfaces.AggregatePriceFeed:
+		var feed models.PriceFeed
+		if err := decodeOutboxPayload(event, &feed); err != nil {
+			return err
+		}
+		update.Type = interfaces.UpdatePriceFeed
+		update.PriceFeed = &feed
+	case interfaces.AggregateSnapshot:
+		var snapshot models.MarketSnapshot
+		if err := decodeOutboxPayload(event, &snapshot); err != nil {
+			return err
+		}
+		update.Type = interfaces.UpdateSnapshot
+		update.Snapshot = &snapshot
+	default:
+		return nil
+	}
+
+	return s.publisher.Publish(ctx, update)
+}
+
+// EventLogSink relays price feed, candle and snapshot events onto their streams;
+// symbol events are acknowledged without being logged
+type EventLogSink struct {
+	eventLog interfaces.EventLog
+}
+
+func NewEventLogSink(eventLog interfaces.EventLog) interfaces.EventSink {
+	return &EventLogSink{eventLog: eventLog}
+}
+
+func (s *EventLogSink) Deliver(ctx context.Context, event *interfaces.OutboxEvent) error {
+	var stream interfaces.EventStream
+	switch event.Aggregate {
+	case interfaces.AggregatePriceFeed:
+		stream = interfaces.StreamPriceFeeds
+	case interfaces.AggregateCandle:
+		stream = interfaces.StreamCandles
+	case interfaces.AggregateSnapshot:
+		stream = interfaces.StreamSnapshots
+	default:
+		return nil
+	}
+
+	_, err := s.eventLog.Append(ctx, &interfaces.LogEvent{
+		EventID:   event.EventID,
+		Stream:    stream,
+		Symbol:    event.Symbol,
+		Payload:   event.Payload,
+		Timestamp: event.CreatedAt,
+	})
+	return err
+}
+
+// MultiEventSink delivers to every sink in order. If one fails the event is retried on
+// all of them, so sinks before it see a duplicate with the same event ID.
+type MultiEventSink []interfaces.EventSink
+
+func (m MultiEventSink) Deliver(ctx context.Context, event *interfaces.OutboxEvent) error {
+	for _, sink := range m {
+		if err := sink.Deliver(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeOutboxPayload(event *interfaces.OutboxEvent, v interface{}) error {
+	if err := json.Unmarshal(event.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode outbox event %s: %w", event.EventID, err)
+	}
+	return nil
+}
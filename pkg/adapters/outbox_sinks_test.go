@@ -0,0 +1,86 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutboxEvent(t *testing.T, aggregate interfaces.OutboxAggregate, symbol string, payload interface{}) *interfaces.OutboxEvent {
+	t.Helper()
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return &interfaces.OutboxEvent{
+		EventID:     "3f1c2b9e-0000-4000-8000-000000000001",
+		Aggregate:   aggregate,
+		AggregateID: "aggregate-1",
+		Operation:   interfaces.OperationCreated,
+		Symbol:      symbol,
+		Payload:     data,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestPublisherEventSink_PublishesPriceFeedWithEventID(t *testing.T) {
+	publisher := &recordingPublisher{}
+	event := newOutboxEvent(t, interfaces.AggregatePriceFeed, "BTC-USD",
+		&models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", Price: decimal.NewFromInt(50000)})
+
+	require.NoError(t, NewPublisherEventSink(publisher).Deliver(context.Background(), event))
+
+	require.Len(t, publisher.updates, 1)
+	update := publisher.updates[0]
+	assert.Equal(t, interfaces.UpdatePriceFeed, update.Type)
+	assert.Equal(t, event.EventID, update.EventID, "Consumers dedupe on the outbox event ID")
+	require.NotNil(t, update.PriceFeed)
+	assert.Equal(t, "feed-1", update.PriceFeed.FeedID)
+}
+
+func TestPublisherEventSink_SkipsAggregatesWithoutPubSubForm(t *testing.T) {
+	publisher := &recordingPublisher{}
+	event := newOutboxEvent(t, interfaces.AggregateSymbol, "BTC-USD", &models.Symbol{Symbol: "BTC-USD"})
+
+	require.NoError(t, NewPublisherEventSink(publisher).Deliver(context.Background(), event))
+	assert.Empty(t, publisher.updates)
+}
+
+func TestEventLogSink_AppendsToAggregateStream(t *testing.T) {
+	log := NewMemoryEventLog(0)
+	ctx := context.Background()
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamCandles, "analytics"))
+
+	event := newOutboxEvent(t, interfaces.AggregateCandle, "ETH-USD", &models.Candle{Symbol: "ETH-USD", Interval: models.Interval1m})
+	require.NoError(t, NewEventLogSink(log).Deliver(ctx, event))
+
+	events, err := log.Read(ctx, interfaces.StreamCandles, "analytics", "worker-1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.EventID, events[0].EventID)
+	assert.Equal(t, "ETH-USD", events[0].Symbol)
+}
+
+func TestMultiEventSink_StopsAtFirstFailure(t *testing.T) {
+	var delivered []string
+	record := func(name string, err error) interfaces.EventSink {
+		return interfaces.EventSinkFunc(func(ctx context.Context, event *interfaces.OutboxEvent) error {
+			delivered = append(delivered, name)
+			return err
+		})
+	}
+	sink := MultiEventSink{record("first", nil), record("second", errors.New("redis down")), record("third", nil)}
+
+	err := sink.Deliver(context.Background(), newOutboxEvent(t, interfaces.AggregatePriceFeed, "BTC-USD", struct{}{}))
+
+	assert.Error(t, err, "A failed sink must leave the event pending")
+	assert.Equal(t, []string{"first", "second"}, delivered)
+}
@@ -0,0 +1,157 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// dropPartitions applies retention to a partitioned table by dropping the partitions that
+// have wholly expired. Rows of a partition straddling the cutoff stay until it expires too.
+// The repository's own delete then runs up to the dropped boundary, which finds no rows in
+// the table but clears anything kept alongside it.
+func dropPartitions(ctx context.Context, partitions interfaces.PartitionDropper, table string, cutoff time.Time,
+	deleteOlderThan func(ctx context.Context, timestamp time.Time) (int64, error), logger *logrus.Logger) (int64, error) {
+	dropped, boundary, err := partitions.DropBefore(ctx, table, cutoff)
+	if err != nil {
+		logger.WithError(err).WithField("table", table).Error("Failed to drop expired partitions")
+		return dropped, fmt.Errorf("failed to drop expired %s partitions: %w", table, err)
+	}
+	if boundary.IsZero() {
+		return 0, nil
+	}
+
+	deleted, err := deleteOlderThan(ctx, boundary)
+	return dropped + deleted, err
+}
+
+// PartitionedPriceFeedRepository applies retention by dropping price_feeds partitions. When
+// the partitions report their range, feeds timestamped outside it are rejected before the
+// insert, so one bad vendor timestamp cannot fail the rest of a batch.
+type PartitionedPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	partitions interfaces.PartitionDropper
+	ranger     interfaces.PartitionRanger // Nil when the partitions take any time, as hypertables do
+	logger     *logrus.Logger
+}
+
+func NewPartitionedPriceFeedRepository(repo interfaces.PriceFeedRepository, partitions interfaces.PartitionDropper, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	ranger, _ := partitions.(interfaces.PartitionRanger)
+	return &PartitionedPriceFeedRepository{
+		PriceFeedRepository: repo,
+		partitions:          partitions,
+		ranger:              ranger,
+		logger:              logger,
+	}
+}
+
+func (r *PartitionedPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	if err := r.checkRange(feed); err != nil {
+		return interfaces.CreateResult{Rejected: true}, err
+	}
+	return r.PriceFeedRepository.Create(ctx, feed)
+}
+
+// CreateBatch stores the feeds that have a partition; the others are reported as rejected
+// in their results rather than failing the batch
+func (r *PartitionedPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results := make([]interfaces.CreateResult, len(feeds))
+	var (
+		pending []*models.PriceFeed
+		indexes []int
+	)
+
+	for i, feed := range feeds {
+		if err := r.checkRange(feed); err != nil {
+			results[i] = interfaces.CreateResult{Rejected: true}
+			continue
+		}
+		pending = append(pending, feed)
+		indexes = append(indexes, i)
+	}
+
+	if len(pending) == len(feeds) {
+		return r.PriceFeedRepository.CreateBatch(ctx, feeds)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	stored, err := r.PriceFeedRepository.CreateBatch(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	for j, result := range stored {
+		results[indexes[j]] = result
+	}
+	return results, nil
+}
+
+// checkRange returns ErrOutsidePartitions if no partition covers the feed's timestamp; a
+// zero timestamp is stamped with the current time on insert and always fits
+func (r *PartitionedPriceFeedRepository) checkRange(feed *models.PriceFeed) error {
+	if r.ranger == nil || feed.Timestamp.IsZero() {
+		return nil
+	}
+	from, to, ok := r.ranger.PartitionRange("price_feeds")
+	if !ok || (!feed.Timestamp.Before(from) && feed.Timestamp.Before(to)) {
+		return nil
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"symbol":    feed.Symbol,
+		"source":    feed.Source,
+		"timestamp": feed.Timestamp,
+		"from":      from,
+		"to":        to,
+	}).Warn("Rejected price feed outside the partitions")
+	return fmt.Errorf("%w: %s at %s", interfaces.ErrOutsidePartitions, feed.Symbol, feed.Timestamp.UTC().Format(time.RFC3339Nano))
+}
+
+// DeleteOlderThan also clears the idempotency keys of the dropped feeds
+func (r *PartitionedPriceFeedRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	return dropPartitions(ctx, r.partitions, "price_feeds", timestamp, r.PriceFeedRepository.DeleteOlderThan, r.logger)
+}
+
+// PartitionedCandleRepository applies retention by dropping candles partitions. Every
+// interval shares a partition, so DeleteIntervalOlderThan still deletes rows.
+type PartitionedCandleRepository struct {
+	interfaces.CandleRepository
+	partitions interfaces.PartitionDropper
+	logger     *logrus.Logger
+}
+
+func NewPartitionedCandleRepository(repo interfaces.CandleRepository, partitions interfaces.PartitionDropper, logger *logrus.Logger) interfaces.CandleRepository {
+	return &PartitionedCandleRepository{
+		CandleRepository: repo,
+		partitions:       partitions,
+		logger:           logger,
+	}
+}
+
+func (r *PartitionedCandleRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	return dropPartitions(ctx, r.partitions, "candles", timestamp, r.CandleRepository.DeleteOlderThan, r.logger)
+}
+
+// PartitionedMarketSnapshotRepository applies retention by dropping market_snapshots partitions
+type PartitionedMarketSnapshotRepository struct {
+	interfaces.MarketSnapshotRepository
+	partitions interfaces.PartitionDropper
+	logger     *logrus.Logger
+}
+
+func NewPartitionedMarketSnapshotRepository(repo interfaces.MarketSnapshotRepository, partitions interfaces.PartitionDropper, logger *logrus.Logger) interfaces.MarketSnapshotRepository {
+	return &PartitionedMarketSnapshotRepository{
+		MarketSnapshotRepository: repo,
+		partitions:               partitions,
+		logger:                   logger,
+	}
+}
+
+func (r *PartitionedMarketSnapshotRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	return dropPartitions(ctx, r.partitions, "market_snapshots", timestamp, r.MarketSnapshotRepository.DeleteOlderThan, r.logger)
+}
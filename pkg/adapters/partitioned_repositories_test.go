@@ -0,0 +1,139 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPartitionDropper struct {
+	rows     int64
+	boundary time.Time
+	err      error
+	tables   []string
+}
+
+func (s *stubPartitionDropper) DropBefore(ctx context.Context, table string, cutoff time.Time) (int64, time.Time, error) {
+	s.tables = append(s.tables, table)
+	return s.rows, s.boundary, s.err
+}
+
+type deletingCandleRepository struct {
+	interfaces.CandleRepository
+	olderThan []time.Time
+	interval  []models.CandleInterval
+}
+
+func (r *deletingCandleRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	r.olderThan = append(r.olderThan, timestamp)
+	return 0, nil
+}
+
+func (r *deletingCandleRepository) DeleteIntervalOlderThan(ctx context.Context, interval models.CandleInterval, timestamp time.Time) (int64, error) {
+	r.interval = append(r.interval, interval)
+	return 7, nil
+}
+
+func TestPartitionedCandleRepository_DropsExpiredPartitions(t *testing.T) {
+	boundary := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	partitions := &stubPartitionDropper{rows: 120, boundary: boundary}
+	inner := &deletingCandleRepository{}
+	repo := NewPartitionedCandleRepository(inner, partitions, newQuietLogger())
+
+	deleted, err := repo.DeleteOlderThan(context.Background(), boundary.Add(36*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), deleted)
+	assert.Equal(t, []string{"candles"}, partitions.tables)
+	assert.Equal(t, []time.Time{boundary}, inner.olderThan, "Rows are only deleted up to the dropped boundary")
+
+	// Intervals share partitions, so per-interval retention still deletes rows
+	deleted, err = repo.DeleteIntervalOlderThan(context.Background(), models.Interval1m, boundary)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), deleted)
+	assert.Len(t, partitions.tables, 1)
+}
+
+func TestPartitionedCandleRepository_KeepsRowsWhenNothingExpired(t *testing.T) {
+	inner := &deletingCandleRepository{}
+	repo := NewPartitionedCandleRepository(inner, &stubPartitionDropper{}, newQuietLogger())
+
+	deleted, err := repo.DeleteOlderThan(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	assert.Empty(t, inner.olderThan)
+}
+
+func TestPartitionedCandleRepository_ReturnsDropErrors(t *testing.T) {
+	inner := &deletingCandleRepository{}
+	repo := NewPartitionedCandleRepository(inner, &stubPartitionDropper{rows: 5, err: errors.New("lock timeout")}, newQuietLogger())
+
+	deleted, err := repo.DeleteOlderThan(context.Background(), time.Now())
+	assert.ErrorContains(t, err, "lock timeout")
+	assert.Equal(t, int64(5), deleted, "Partitions dropped before the failure are counted")
+	assert.Empty(t, inner.olderThan)
+}
+
+// stubPartitionRanger also reports a partition range, as the partition manager does
+type stubPartitionRanger struct {
+	stubPartitionDropper
+	from, to time.Time
+}
+
+func (s *stubPartitionRanger) PartitionRange(table string) (time.Time, time.Time, bool) {
+	return s.from, s.to, !s.to.IsZero()
+}
+
+func TestPartitionedPriceFeedRepository_RejectsFeedsOutsidePartitions(t *testing.T) {
+	to := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	inner := &stubPriceFeedRepository{}
+	repo := NewPartitionedPriceFeedRepository(inner, &stubPartitionRanger{to: to}, newQuietLogger())
+
+	result, err := repo.Create(context.Background(), &models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", Timestamp: to})
+	assert.ErrorIs(t, err, interfaces.ErrOutsidePartitions)
+	assert.True(t, result.Rejected)
+	assert.Empty(t, inner.created, "Feeds outside the partitions must not reach the insert")
+
+	_, err = repo.Create(context.Background(), &models.PriceFeed{FeedID: "feed-2", Symbol: "BTC-USD", Timestamp: to.Add(-time.Nanosecond)})
+	require.NoError(t, err)
+	_, err = repo.Create(context.Background(), &models.PriceFeed{FeedID: "feed-3", Symbol: "BTC-USD"})
+	require.NoError(t, err, "A zero timestamp is stamped with the current time")
+	assert.Len(t, inner.created, 2)
+}
+
+func TestPartitionedPriceFeedRepository_BatchStoresFeedsThatFit(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 14)
+	inner := &stubPriceFeedRepository{}
+	publisher := &recordingPublisher{}
+	repo := NewPublishingPriceFeedRepository(
+		NewPartitionedPriceFeedRepository(inner, &stubPartitionRanger{from: from, to: to}, newQuietLogger()),
+		publisher, newQuietLogger())
+
+	early := &models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", Timestamp: from.Add(-time.Second)}
+	fits := &models.PriceFeed{FeedID: "feed-2", Symbol: "BTC-USD", Timestamp: from.AddDate(0, 0, 3)}
+	late := &models.PriceFeed{FeedID: "feed-3", Symbol: "ETH-USD", Timestamp: to.AddDate(1, 0, 0)}
+
+	results, err := repo.CreateBatch(context.Background(), []*models.PriceFeed{early, fits, late})
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.CreateResult{{Rejected: true}, {FeedID: "feed-2"}, {Rejected: true}}, results)
+	assert.Equal(t, []*models.PriceFeed{fits}, inner.created)
+	assert.Equal(t, []*models.PriceFeed{fits}, publisher.feeds, "Rejected feeds must not be fanned out")
+}
+
+func TestPartitionedPriceFeedRepository_AcceptsAnyTimeWithoutRange(t *testing.T) {
+	inner := &stubPriceFeedRepository{}
+	repo := NewPartitionedPriceFeedRepository(inner, &stubPartitionRanger{}, newQuietLogger())
+
+	results, err := repo.CreateBatch(context.Background(), []*models.PriceFeed{
+		{FeedID: "feed-1", Symbol: "BTC-USD", Timestamp: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+	assert.False(t, results[0].Rejected, "Before the partitions are known every feed is passed on")
+	assert.Len(t, inner.created, 1)
+}
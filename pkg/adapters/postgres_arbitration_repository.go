@@ -0,0 +1,128 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresArbitrationRepository struct {
+	db              dbtx
+	prioritiesTable string
+	switchesTable   string
+	logger          *logrus.Logger
+}
+
+func NewPostgresArbitrationRepository(db *sql.DB, schema string, logger *logrus.Logger) interfaces.ArbitrationRepository {
+	return &PostgresArbitrationRepository{
+		db:              asDBTX(db),
+		prioritiesTable: qualifiedTable(schema, "symbol_source_priorities"),
+		switchesTable:   qualifiedTable(schema, "source_switch_events"),
+		logger:          logger,
+	}
+}
+
+func (r *PostgresArbitrationRepository) GetSourcePriority(ctx context.Context, symbol string) ([]string, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT source FROM `+r.prioritiesTable+` WHERE symbol = $1 ORDER BY priority, source`, symbol)
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to get source priority")
+		return nil, fmt.Errorf("failed to get source priority: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []string
+	for rows.Next() {
+		var source string
+		if err := rows.Scan(&source); err != nil {
+			return nil, fmt.Errorf("failed to scan source priority: %w", err)
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read source priority: %w", err)
+	}
+	return sources, nil
+}
+
+func (r *PostgresArbitrationRepository) SetSourcePriority(ctx context.Context, symbol string, sources []string) error {
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+r.prioritiesTable+` WHERE symbol = $1`, symbol); err != nil {
+			return err
+		}
+		for priority, source := range sources {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO `+r.prioritiesTable+` (symbol, source, priority) VALUES ($1, $2, $3)`,
+				symbol, source, priority,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to set source priority")
+		return fmt.Errorf("failed to set source priority: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresArbitrationRepository) RecordSwitch(ctx context.Context, event *models.SourceSwitchEvent) error {
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+	if event.EventID == "" {
+		event.EventID = uuid.New().String()
+	}
+	if event.SwitchedAt.IsZero() {
+		event.SwitchedAt = time.Now()
+	}
+
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO `+r.switchesTable+` (event_id, symbol, from_source, to_source, reason, switched_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		event.EventID, event.Symbol, event.FromSource, event.ToSource, event.Reason, event.SwitchedAt,
+	); err != nil {
+		r.logger.WithError(err).WithField("symbol", event.Symbol).Error("Failed to record source switch")
+		return fmt.Errorf("failed to record source switch: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresArbitrationRepository) GetSwitches(ctx context.Context, symbol string, limit int) ([]*models.SourceSwitchEvent, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT event_id, symbol, from_source, to_source, reason, switched_at FROM `+r.switchesTable+`
+		WHERE symbol = $1 ORDER BY switched_at DESC`+limitClause(limit, 0), symbol)
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to get source switches")
+		return nil, fmt.Errorf("failed to get source switches: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.SourceSwitchEvent
+	for rows.Next() {
+		var event models.SourceSwitchEvent
+		if err := rows.Scan(&event.EventID, &event.Symbol, &event.FromSource, &event.ToSource, &event.Reason, &event.SwitchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan source switch: %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read source switches: %w", err)
+	}
+	return events, nil
+}
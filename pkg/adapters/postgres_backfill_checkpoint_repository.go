@@ -0,0 +1,98 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresBackfillCheckpointRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewPostgresBackfillCheckpointRepository(db *sql.DB, schema string, logger *logrus.Logger) interfaces.BackfillCheckpointRepository {
+	return &PostgresBackfillCheckpointRepository{
+		db:     asDBTX(db),
+		table:  qualifiedTable(schema, "candle_backfill_checkpoints"),
+		logger: logger,
+	}
+}
+
+func (r *PostgresBackfillCheckpointRepository) Get(ctx context.Context, symbol string, interval models.CandleInterval) (*models.BackfillCheckpoint, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var checkpoint models.BackfillCheckpoint
+	err := r.db.QueryRowContext(ctx,
+		`SELECT symbol, interval, from_time, to_time, cursor_time, filled, unfilled, updated_at
+		FROM `+r.table+` WHERE symbol = $1 AND interval = $2`, symbol, interval,
+	).Scan(&checkpoint.Symbol, &checkpoint.Interval, &checkpoint.From, &checkpoint.To, &checkpoint.Cursor,
+		&checkpoint.Filled, &checkpoint.Unfilled, &checkpoint.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("backfill checkpoint %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":   symbol,
+			"interval": interval,
+		}).Error("Failed to get backfill checkpoint")
+		return nil, fmt.Errorf("failed to get backfill checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (r *PostgresBackfillCheckpointRepository) Save(ctx context.Context, checkpoint *models.BackfillCheckpoint) error {
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+	if checkpoint.UpdatedAt.IsZero() {
+		checkpoint.UpdatedAt = time.Now()
+	}
+
+	query := `INSERT INTO ` + r.table + ` (symbol, interval, from_time, to_time, cursor_time, filled, unfilled, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (symbol, interval) DO UPDATE SET
+			from_time = EXCLUDED.from_time,
+			to_time = EXCLUDED.to_time,
+			cursor_time = EXCLUDED.cursor_time,
+			filled = EXCLUDED.filled,
+			unfilled = EXCLUDED.unfilled,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		checkpoint.Symbol, checkpoint.Interval, checkpoint.From, checkpoint.To, checkpoint.Cursor,
+		checkpoint.Filled, checkpoint.Unfilled, checkpoint.UpdatedAt,
+	); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":   checkpoint.Symbol,
+			"interval": checkpoint.Interval,
+		}).Error("Failed to save backfill checkpoint")
+		return fmt.Errorf("failed to save backfill checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresBackfillCheckpointRepository) Delete(ctx context.Context, symbol string, interval models.CandleInterval) error {
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM `+r.table+` WHERE symbol = $1 AND interval = $2`, symbol, interval,
+	); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":   symbol,
+			"interval": interval,
+		}).Error("Failed to delete backfill checkpoint")
+		return fmt.Errorf("failed to delete backfill checkpoint: %w", err)
+	}
+	return nil
+}
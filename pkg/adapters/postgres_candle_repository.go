@@ -6,43 +6,220 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
+const candleColumns = `candle_id, symbol, interval, open, high, low, close, volume, start_time, end_time, num_trades, metadata`
+
+var candleSortColumns = []string{"start_time", "symbol", "interval", "close", "volume"}
+
 type PostgresCandleRepository struct {
-	db     *sql.DB
+	db     dbtx
+	table  string
+	outbox outboxWriter
 	logger *logrus.Logger
 }
 
-func NewPostgresCandleRepository(db *sql.DB, logger *logrus.Logger) interfaces.CandleRepository {
+func NewPostgresCandleRepository(db *sql.DB, schema string, outboxEnabled bool, logger *logrus.Logger) interfaces.CandleRepository {
+	return newPostgresCandleRepository(asDBTX(db), schema, outboxEnabled, logger)
+}
+
+func newPostgresCandleRepository(db dbtx, schema string, outboxEnabled bool, logger *logrus.Logger) *PostgresCandleRepository {
 	return &PostgresCandleRepository{
 		db:     db,
+		table:  qualifiedTable(schema, "candles"),
+		outbox: newOutboxWriter(schema, outboxEnabled),
 		logger: logger,
 	}
 }
 
 func (r *PostgresCandleRepository) Upsert(ctx context.Context, candle *models.Candle) error {
-	return fmt.Errorf("not implemented: Upsert candle")
+	if candle.CandleID == "" {
+		candle.CandleID = uuid.New().String()
+	}
+
+	// xmax is zero only for freshly inserted rows, which tells created from updated
+	query := `INSERT INTO ` + r.table + ` (candle_id, symbol, interval, open, high, low, close, volume, start_time, end_time, num_trades, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (symbol, interval, start_time) DO UPDATE SET
+			open = EXCLUDED.open,
+			high = EXCLUDED.high,
+			low = EXCLUDED.low,
+			close = EXCLUDED.close,
+			volume = EXCLUDED.volume,
+			end_time = EXCLUDED.end_time,
+			num_trades = EXCLUDED.num_trades,
+			metadata = EXCLUDED.metadata,
+			updated_at = NOW()
+		RETURNING candle_id, (xmax = 0) AS inserted`
+
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		var inserted bool
+		if err := tx.QueryRowContext(ctx, query,
+			candle.CandleID, candle.Symbol, candle.Interval, candle.Open, candle.High, candle.Low,
+			candle.Close, candle.Volume, candle.StartTime, candle.EndTime, candle.NumTrades,
+			nullableJSON(candle.Metadata),
+		).Scan(&candle.CandleID, &inserted); err != nil {
+			return err
+		}
+
+		operation := interfaces.OperationUpdated
+		if inserted {
+			operation = interfaces.OperationCreated
+		}
+		return r.outbox.write(ctx, tx, interfaces.AggregateCandle, candle.CandleID, operation, candle.Symbol, candle)
+	})
+	if err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":   candle.Symbol,
+			"interval": candle.Interval,
+		}).Error("Failed to upsert candle")
+		return fmt.Errorf("failed to upsert candle: %w", err)
+	}
+	return nil
 }
 
 func (r *PostgresCandleRepository) GetByID(ctx context.Context, candleID string) (*models.Candle, error) {
-	return nil, fmt.Errorf("not implemented: GetByID candle")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	candle, err := scanCandle(r.db.QueryRowContext(ctx,
+		`SELECT `+candleColumns+` FROM `+r.table+` WHERE candle_id = $1`, candleID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candle %s: %w", candleID, err)
+	}
+	return candle, nil
 }
 
 func (r *PostgresCandleRepository) GetBySymbolAndInterval(ctx context.Context, symbol string, interval models.CandleInterval, limit int) ([]*models.Candle, error) {
-	return nil, fmt.Errorf("not implemented: GetBySymbolAndInterval")
+	return r.Query(ctx, &models.CandleQuery{Symbol: &symbol, Interval: &interval, Limit: limit})
 }
 
 func (r *PostgresCandleRepository) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
-	return nil, fmt.Errorf("not implemented: Query candles")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.Interval != nil {
+		where.add("interval =", *query.Interval)
+	}
+	if query.StartTimeFrom != nil {
+		where.add("start_time >=", *query.StartTimeFrom)
+	}
+	if query.StartTimeTo != nil {
+		where.add("start_time <", *query.StartTimeTo)
+	}
+
+	statement := `SELECT ` + candleColumns + ` FROM ` + r.table + where.String() +
+		orderClause(query.SortBy, query.SortOrder, candleSortColumns, "start_time") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query candles")
+		return nil, fmt.Errorf("failed to query candles: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []*models.Candle
+	for rows.Next() {
+		candle, err := scanCandle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read candles: %w", err)
+	}
+	return candles, nil
 }
 
 func (r *PostgresCandleRepository) GetLatest(ctx context.Context, symbol string, interval models.CandleInterval) (*models.Candle, error) {
-	return nil, fmt.Errorf("not implemented: GetLatest candle")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	candle, err := scanCandle(r.db.QueryRowContext(ctx,
+		`SELECT `+candleColumns+` FROM `+r.table+` WHERE symbol = $1 AND interval = $2 ORDER BY start_time DESC LIMIT 1`,
+		symbol, interval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest %s candle for %s: %w", interval, symbol, err)
+	}
+	return candle, nil
 }
 
+// DeleteOlderThan is retention cleanup and deliberately emits no outbox events
 func (r *PostgresCandleRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
-	return 0, fmt.Errorf("not implemented: DeleteOlderThan")
+	if r.db == nil {
+		return 0, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE start_time < $1`, timestamp)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old candles")
+		return 0, fmt.Errorf("failed to delete old candles: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresCandleRepository) GetOldestIntervalTimestamps(ctx context.Context, interval models.CandleInterval, before time.Time) ([]*models.LatestTimestamp, error) {
+	oldest, err := queryLatestTimestamps(ctx, r.db,
+		`SELECT symbol, '', MIN(start_time) FROM `+r.table+` WHERE interval = $1 AND start_time < $2 GROUP BY symbol`,
+		interval, before)
+	if err != nil {
+		r.logger.WithError(err).WithField("interval", interval).Error("Failed to get oldest candle timestamps")
+		return nil, fmt.Errorf("failed to get oldest %s candle timestamps: %w", interval, err)
+	}
+	return oldest, nil
+}
+
+// DeleteIntervalOlderThan is retention cleanup and deliberately emits no outbox events
+func (r *PostgresCandleRepository) DeleteIntervalOlderThan(ctx context.Context, interval models.CandleInterval, timestamp time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE interval = $1 AND start_time < $2`, interval, timestamp)
+	if err != nil {
+		r.logger.WithError(err).WithField("interval", interval).Error("Failed to delete old candles")
+		return 0, fmt.Errorf("failed to delete old %s candles: %w", interval, err)
+	}
+	return result.RowsAffected()
+}
+
+// scanCandle reads one row selected with candleColumns, mapping no rows to ErrNotFound
+func scanCandle(row rowScanner) (*models.Candle, error) {
+	var (
+		candle    models.Candle
+		numTrades sql.NullInt64
+		metadata  []byte
+	)
+
+	err := row.Scan(
+		&candle.CandleID, &candle.Symbol, &candle.Interval, &candle.Open, &candle.High, &candle.Low,
+		&candle.Close, &candle.Volume, &candle.StartTime, &candle.EndTime, &numTrades, &metadata,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candle %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if numTrades.Valid {
+		trades := int(numTrades.Int64)
+		candle.NumTrades = &trades
+	}
+	candle.Metadata = metadata
+
+	return &candle, nil
 }
@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const dataQualityColumns = `event_id, type, source, symbol, expected_sequence, received_sequence, missing, detected_at, metadata`
+
+type PostgresDataQualityRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewPostgresDataQualityRepository(db *sql.DB, schema string, logger *logrus.Logger) interfaces.DataQualityRepository {
+	return &PostgresDataQualityRepository{
+		db:     asDBTX(db),
+		table:  qualifiedTable(schema, "data_quality_events"),
+		logger: logger,
+	}
+}
+
+func (r *PostgresDataQualityRepository) Create(ctx context.Context, event *models.DataQualityEvent) error {
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+	if event.EventID == "" {
+		event.EventID = uuid.New().String()
+	}
+	if event.DetectedAt.IsZero() {
+		event.DetectedAt = time.Now()
+	}
+
+	query := `INSERT INTO ` + r.table + ` (` + dataQualityColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		event.EventID, event.Type, event.Source, event.Symbol, event.ExpectedSequence,
+		event.ReceivedSequence, event.Missing, event.DetectedAt, nullableJSON(event.Metadata),
+	); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"source": event.Source,
+			"symbol": event.Symbol,
+		}).Error("Failed to create data quality event")
+		return fmt.Errorf("failed to create data quality event: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresDataQualityRepository) Query(ctx context.Context, query *models.DataQualityQuery) ([]*models.DataQualityEvent, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Type != nil {
+		where.add("type =", *query.Type)
+	}
+	if query.Source != nil {
+		where.add("source =", *query.Source)
+	}
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.DetectedAtFrom != nil {
+		where.add("detected_at >=", *query.DetectedAtFrom)
+	}
+	if query.DetectedAtTo != nil {
+		where.add("detected_at <", *query.DetectedAtTo)
+	}
+
+	statement := `SELECT ` + dataQualityColumns + ` FROM ` + r.table + where.String() +
+		` ORDER BY detected_at DESC` + limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query data quality events")
+		return nil, fmt.Errorf("failed to query data quality events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.DataQualityEvent
+	for rows.Next() {
+		var event models.DataQualityEvent
+		var metadata []byte
+		if err := rows.Scan(&event.EventID, &event.Type, &event.Source, &event.Symbol, &event.ExpectedSequence,
+			&event.ReceivedSequence, &event.Missing, &event.DetectedAt, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan data quality event: %w", err)
+		}
+		event.Metadata = metadata
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read data quality events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *PostgresDataQualityRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE detected_at < $1`, timestamp)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old data quality events")
+		return 0, fmt.Errorf("failed to delete old data quality events: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,161 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+)
+
+// qualifiedTable returns schema.table with both identifiers quoted
+func qualifiedTable(schema, table string) string {
+	return pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(table)
+}
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx, so a repository runs the same statements
+// standalone or bound to a unit of work
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// asDBTX keeps a not-yet-connected pool as a nil interface rather than a typed nil
+func asDBTX(db *sql.DB) dbtx {
+	if db == nil {
+		return nil
+	}
+	return db
+}
+
+// withTx runs fn in a transaction, committing if it returns nil and rolling back otherwise.
+// When db is already a transaction, fn joins it and the owner decides the outcome.
+func withTx(ctx context.Context, db dbtx, fn func(tx dbtx) error) error {
+	switch conn := db.(type) {
+	case nil:
+		return fmt.Errorf("PostgreSQL not connected")
+	case *sql.Tx:
+		return fn(conn)
+	case *sql.DB:
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported connection type %T", db)
+	}
+}
+
+// nullableJSON maps empty metadata to NULL rather than an invalid empty JSONB value
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+// decimalPtr maps a NULL numeric column to a nil pointer
+func decimalPtr(value decimal.NullDecimal) *decimal.Decimal {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Decimal
+}
+
+// secondsToDuration converts an EXTRACT(EPOCH ...) interval to a Duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// whereClause collects filter conditions with numbered placeholders
+type whereClause struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add appends a condition such as "symbol =" compared against the next placeholder
+func (w *whereClause) add(condition string, arg interface{}) {
+	w.args = append(w.args, arg)
+	w.conditions = append(w.conditions, fmt.Sprintf("%s $%d", condition, len(w.args)))
+}
+
+func (w *whereClause) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// limitClause renders LIMIT/OFFSET, leaving either out when not positive
+func limitClause(limit, offset int) string {
+	clause := ""
+	if limit > 0 {
+		clause += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	if offset > 0 {
+		clause += fmt.Sprintf(" OFFSET %d", offset)
+	}
+	return clause
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// orderClause renders ORDER BY for a caller-supplied sort column, which must be one of
+// sortable since it cannot be passed as a placeholder; the order defaults to descending
+func orderClause(sortBy, sortOrder string, sortable []string, fallback string) string {
+	column := fallback
+	for _, allowed := range sortable {
+		if sortBy == allowed {
+			column = sortBy
+			break
+		}
+	}
+
+	direction := "DESC"
+	if strings.EqualFold(sortOrder, "asc") {
+		direction = "ASC"
+	}
+	return " ORDER BY " + pq.QuoteIdentifier(column) + " " + direction
+}
+
+// queryLatestTimestamps runs a "SELECT symbol, source, MAX(...)" style query
+func queryLatestTimestamps(ctx context.Context, db dbtx, query string, args ...interface{}) ([]*models.LatestTimestamp, error) {
+	if db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var latest []*models.LatestTimestamp
+	for rows.Next() {
+		var entry models.LatestTimestamp
+		if err := rows.Scan(&entry.Symbol, &entry.Source, &entry.Timestamp); err != nil {
+			return nil, err
+		}
+		latest = append(latest, &entry)
+	}
+	return latest, rows.Err()
+}
@@ -6,43 +6,193 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
+const snapshotColumns = `snapshot_id, symbol, last_price, bid, ask, spread, volume_24h,
+	price_change_24h, price_change_percent_24h, timestamp, metadata`
+
+var snapshotSortColumns = []string{"timestamp", "symbol", "last_price", "volume_24h", "price_change_percent_24h"}
+
 type PostgresMarketSnapshotRepository struct {
-	db     *sql.DB
+	db     dbtx
+	table  string
+	outbox outboxWriter
 	logger *logrus.Logger
 }
 
-func NewPostgresMarketSnapshotRepository(db *sql.DB, logger *logrus.Logger) interfaces.MarketSnapshotRepository {
+func NewPostgresMarketSnapshotRepository(db *sql.DB, schema string, outboxEnabled bool, logger *logrus.Logger) interfaces.MarketSnapshotRepository {
+	return newPostgresMarketSnapshotRepository(asDBTX(db), schema, outboxEnabled, logger)
+}
+
+func newPostgresMarketSnapshotRepository(db dbtx, schema string, outboxEnabled bool, logger *logrus.Logger) *PostgresMarketSnapshotRepository {
 	return &PostgresMarketSnapshotRepository{
 		db:     db,
+		table:  qualifiedTable(schema, "market_snapshots"),
+		outbox: newOutboxWriter(schema, outboxEnabled),
 		logger: logger,
 	}
 }
 
 func (r *PostgresMarketSnapshotRepository) Create(ctx context.Context, snapshot *models.MarketSnapshot) error {
-	return fmt.Errorf("not implemented: Create snapshot")
+	if snapshot.SnapshotID == "" {
+		snapshot.SnapshotID = uuid.New().String()
+	}
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+
+	query := `INSERT INTO ` + r.table + ` (snapshot_id, symbol, last_price, bid, ask, spread, volume_24h,
+			price_change_24h, price_change_percent_24h, timestamp, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			snapshot.SnapshotID, snapshot.Symbol, snapshot.LastPrice, snapshot.Bid, snapshot.Ask,
+			snapshot.Spread, snapshot.Volume24h, snapshot.PriceChange24h, snapshot.PriceChangePercent24h,
+			snapshot.Timestamp, nullableJSON(snapshot.Metadata),
+		); err != nil {
+			return err
+		}
+		return r.outbox.write(ctx, tx, interfaces.AggregateSnapshot, snapshot.SnapshotID, interfaces.OperationCreated, snapshot.Symbol, snapshot)
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", snapshot.Symbol).Error("Failed to create snapshot")
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	return nil
 }
 
 func (r *PostgresMarketSnapshotRepository) GetByID(ctx context.Context, snapshotID string) (*models.MarketSnapshot, error) {
-	return nil, fmt.Errorf("not implemented: GetByID snapshot")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	snapshot, err := scanMarketSnapshot(r.db.QueryRowContext(ctx,
+		`SELECT `+snapshotColumns+` FROM `+r.table+` WHERE snapshot_id = $1`, snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot %s: %w", snapshotID, err)
+	}
+	return snapshot, nil
 }
 
 func (r *PostgresMarketSnapshotRepository) GetLatestBySymbol(ctx context.Context, symbol string) (*models.MarketSnapshot, error) {
-	return nil, fmt.Errorf("not implemented: GetLatestBySymbol")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	snapshot, err := scanMarketSnapshot(r.db.QueryRowContext(ctx,
+		`SELECT `+snapshotColumns+` FROM `+r.table+` WHERE symbol = $1 ORDER BY timestamp DESC LIMIT 1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshot for %s: %w", symbol, err)
+	}
+	return snapshot, nil
 }
 
 func (r *PostgresMarketSnapshotRepository) GetBySymbol(ctx context.Context, symbol string, limit int) ([]*models.MarketSnapshot, error) {
-	return nil, fmt.Errorf("not implemented: GetBySymbol")
+	return r.Query(ctx, &models.MarketSnapshotQuery{Symbol: &symbol, Limit: limit})
 }
 
 func (r *PostgresMarketSnapshotRepository) Query(ctx context.Context, query *models.MarketSnapshotQuery) ([]*models.MarketSnapshot, error) {
-	return nil, fmt.Errorf("not implemented: Query snapshots")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.TimestampFrom != nil {
+		where.add("timestamp >=", *query.TimestampFrom)
+	}
+	if query.TimestampTo != nil {
+		where.add("timestamp <", *query.TimestampTo)
+	}
+
+	statement := `SELECT ` + snapshotColumns + ` FROM ` + r.table + where.String() +
+		orderClause(query.SortBy, query.SortOrder, snapshotSortColumns, "timestamp") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query snapshots")
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*models.MarketSnapshot
+	for rows.Next() {
+		snapshot, err := scanMarketSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	return snapshots, nil
 }
 
+func (r *PostgresMarketSnapshotRepository) GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error) {
+	latest, err := queryLatestTimestamps(ctx, r.db,
+		`SELECT symbol, '', MAX(timestamp) FROM `+r.table+` WHERE timestamp >= $1 GROUP BY symbol`, since)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get latest snapshot timestamps")
+		return nil, fmt.Errorf("failed to get latest snapshot timestamps: %w", err)
+	}
+	return latest, nil
+}
+
+// DeleteOlderThan is retention cleanup and deliberately emits no outbox events
 func (r *PostgresMarketSnapshotRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
-	return 0, fmt.Errorf("not implemented: DeleteOlderThan")
+	if r.db == nil {
+		return 0, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE timestamp < $1`, timestamp)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old snapshots")
+		return 0, fmt.Errorf("failed to delete old snapshots: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanMarketSnapshot reads one row selected with snapshotColumns, mapping no rows to ErrNotFound
+func scanMarketSnapshot(row rowScanner) (*models.MarketSnapshot, error) {
+	var (
+		snapshot              models.MarketSnapshot
+		bid                   decimal.NullDecimal
+		ask                   decimal.NullDecimal
+		spread                decimal.NullDecimal
+		volume24h             decimal.NullDecimal
+		priceChange24h        decimal.NullDecimal
+		priceChangePercent24h decimal.NullDecimal
+		metadata              []byte
+	)
+
+	err := row.Scan(
+		&snapshot.SnapshotID, &snapshot.Symbol, &snapshot.LastPrice, &bid, &ask, &spread, &volume24h,
+		&priceChange24h, &priceChangePercent24h, &snapshot.Timestamp, &metadata,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("snapshot %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Bid = decimalPtr(bid)
+	snapshot.Ask = decimalPtr(ask)
+	snapshot.Spread = decimalPtr(spread)
+	snapshot.Volume24h = decimalPtr(volume24h)
+	snapshot.PriceChange24h = decimalPtr(priceChange24h)
+	snapshot.PriceChangePercent24h = decimalPtr(priceChangePercent24h)
+	snapshot.Metadata = metadata
+
+	return &snapshot, nil
 }
@@ -0,0 +1,42 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+)
+
+// outboxWriter records events in the outbox table inside the caller's transaction;
+// a disabled writer is a no-op so repositories need not branch on configuration
+type outboxWriter struct {
+	table   string
+	enabled bool
+}
+
+func newOutboxWriter(schema string, enabled bool) outboxWriter {
+	return outboxWriter{
+		table:   qualifiedTable(schema, "outbox"),
+		enabled: enabled,
+	}
+}
+
+func (w outboxWriter) write(ctx context.Context, tx dbtx, aggregate interfaces.OutboxAggregate, aggregateID string, operation interfaces.OutboxOperation, symbol string, payload interface{}) error {
+	if !w.enabled {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	query := `INSERT INTO ` + w.table + ` (event_id, aggregate, aggregate_id, operation, symbol, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := tx.ExecContext(ctx, query, uuid.New().String(), aggregate, aggregateID, operation, symbol, string(data)); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
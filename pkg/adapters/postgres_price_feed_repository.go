@@ -3,46 +3,335 @@ package adapters
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
+const priceFeedColumns = `feed_id, symbol, price, bid, ask, volume_24h, source, timestamp, metadata,
+	idempotency_key, sequence, received_at, persisted_at`
+
+var priceFeedSortColumns = []string{"timestamp", "received_at", "price", "symbol", "source"}
+
 type PostgresPriceFeedRepository struct {
-	db     *sql.DB
-	logger *logrus.Logger
+	db        dbtx
+	table     string
+	keysTable string
+	outbox    outboxWriter
+	logger    *logrus.Logger
+}
+
+func NewPostgresPriceFeedRepository(db *sql.DB, schema string, outboxEnabled bool, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return newPostgresPriceFeedRepository(asDBTX(db), schema, outboxEnabled, logger)
 }
 
-func NewPostgresPriceFeedRepository(db *sql.DB, logger *logrus.Logger) interfaces.PriceFeedRepository {
+func newPostgresPriceFeedRepository(db dbtx, schema string, outboxEnabled bool, logger *logrus.Logger) *PostgresPriceFeedRepository {
 	return &PostgresPriceFeedRepository{
-		db:     db,
-		logger: logger,
+		db:        db,
+		table:     qualifiedTable(schema, "price_feeds"),
+		keysTable: qualifiedTable(schema, "price_feed_idempotency_keys"),
+		outbox:    newOutboxWriter(schema, outboxEnabled),
+		logger:    logger,
+	}
+}
+
+func (r *PostgresPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	var result interfaces.CreateResult
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		var err error
+		result, err = r.insert(ctx, tx, feed)
+		return err
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", feed.Symbol).Error("Failed to create price feed")
+		return interfaces.CreateResult{}, fmt.Errorf("failed to create price feed: %w", err)
+	}
+	return result, nil
+}
+
+func (r *PostgresPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results := make([]interfaces.CreateResult, 0, len(feeds))
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		for _, feed := range feeds {
+			result, err := r.insert(ctx, tx, feed)
+			if err != nil {
+				return fmt.Errorf("symbol %s: %w", feed.Symbol, err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("count", len(feeds)).Error("Failed to create price feed batch")
+		return nil, fmt.Errorf("failed to create price feed batch: %w", err)
 	}
+	return results, nil
 }
 
-func (r *PostgresPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) error {
-	return fmt.Errorf("not implemented: Create price feed")
+// insert stores feed unless another feed with the same idempotency key already exists.
+// The conflict is resolved by the key table's primary key, so concurrent replays are
+// caught too, whatever timestamp or partition the replay carries.
+func (r *PostgresPriceFeedRepository) insert(ctx context.Context, tx dbtx, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	if feed.FeedID == "" {
+		feed.FeedID = uuid.New().String()
+	}
+	now := time.Now()
+	if feed.Timestamp.IsZero() {
+		feed.Timestamp = now
+	}
+	if feed.ReceivedAt.IsZero() {
+		feed.ReceivedAt = now
+	}
+
+	if feed.IdempotencyKey != nil {
+		// Claim the key first; a concurrent replay blocks on it until this transaction ends
+		var feedID string
+		err := tx.QueryRowContext(ctx, `INSERT INTO `+r.keysTable+` (idempotency_key, feed_id, timestamp)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (idempotency_key) DO NOTHING
+			RETURNING feed_id`,
+			*feed.IdempotencyKey, feed.FeedID, feed.Timestamp,
+		).Scan(&feedID)
+		if errors.Is(err, sql.ErrNoRows) {
+			if err := tx.QueryRowContext(ctx,
+				`SELECT feed_id FROM `+r.keysTable+` WHERE idempotency_key = $1`, *feed.IdempotencyKey,
+			).Scan(&feedID); err != nil {
+				return interfaces.CreateResult{}, fmt.Errorf("failed to look up duplicate price feed: %w", err)
+			}
+			return interfaces.CreateResult{FeedID: feedID, Duplicate: true}, nil
+		}
+		if err != nil {
+			return interfaces.CreateResult{}, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+	}
+
+	// clock_timestamp rather than NOW, which is fixed at the start of the transaction
+	query := `INSERT INTO ` + r.table + ` (feed_id, symbol, price, bid, ask, volume_24h, source, timestamp, metadata,
+			idempotency_key, sequence, received_at, persisted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, clock_timestamp())
+		RETURNING feed_id, persisted_at`
+
+	var feedID string
+	err := tx.QueryRowContext(ctx, query,
+		feed.FeedID, feed.Symbol, feed.Price, feed.Bid, feed.Ask, feed.Volume24h,
+		feed.Source, feed.Timestamp, nullableJSON(feed.Metadata), feed.IdempotencyKey, feed.Sequence, feed.ReceivedAt,
+	).Scan(&feedID, &feed.PersistedAt)
+	if err != nil {
+		return interfaces.CreateResult{}, err
+	}
+
+	if err := r.outbox.write(ctx, tx, interfaces.AggregatePriceFeed, feed.FeedID, interfaces.OperationCreated, feed.Symbol, feed); err != nil {
+		return interfaces.CreateResult{}, err
+	}
+	return interfaces.CreateResult{FeedID: feedID}, nil
 }
 
 func (r *PostgresPriceFeedRepository) GetByID(ctx context.Context, feedID string) (*models.PriceFeed, error) {
-	return nil, fmt.Errorf("not implemented: GetByID price feed")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	feed, err := scanPriceFeed(r.db.QueryRowContext(ctx,
+		`SELECT `+priceFeedColumns+` FROM `+r.table+` WHERE feed_id = $1`, feedID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price feed %s: %w", feedID, err)
+	}
+	return feed, nil
 }
 
 func (r *PostgresPriceFeedRepository) GetLatestBySymbol(ctx context.Context, symbol string) (*models.PriceFeed, error) {
-	return nil, fmt.Errorf("not implemented: GetLatestBySymbol")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	feed, err := scanPriceFeed(r.db.QueryRowContext(ctx,
+		`SELECT `+priceFeedColumns+` FROM `+r.table+` WHERE symbol = $1 ORDER BY timestamp DESC LIMIT 1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest price feed for %s: %w", symbol, err)
+	}
+	return feed, nil
 }
 
 func (r *PostgresPriceFeedRepository) GetBySymbol(ctx context.Context, symbol string, limit int) ([]*models.PriceFeed, error) {
-	return nil, fmt.Errorf("not implemented: GetBySymbol")
+	return r.Query(ctx, &models.PriceFeedQuery{Symbol: &symbol, Limit: limit})
 }
 
 func (r *PostgresPriceFeedRepository) Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error) {
-	return nil, fmt.Errorf("not implemented: Query price feeds")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.Source != nil {
+		where.add("source =", *query.Source)
+	}
+	if query.TimestampFrom != nil {
+		where.add("timestamp >=", *query.TimestampFrom)
+	}
+	if query.TimestampTo != nil {
+		where.add("timestamp <", *query.TimestampTo)
+	}
+
+	statement := `SELECT ` + priceFeedColumns + ` FROM ` + r.table + where.String() +
+		orderClause(query.SortBy, query.SortOrder, priceFeedSortColumns, "timestamp") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query price feeds")
+		return nil, fmt.Errorf("failed to query price feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []*models.PriceFeed
+	for rows.Next() {
+		feed, err := scanPriceFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+func (r *PostgresPriceFeedRepository) GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error) {
+	latest, err := queryLatestTimestamps(ctx, r.db,
+		`SELECT symbol, source, MAX(timestamp) FROM `+r.table+` WHERE timestamp >= $1 GROUP BY symbol, source`, since)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get latest price feed timestamps")
+		return nil, fmt.Errorf("failed to get latest price feed timestamps: %w", err)
+	}
+	return latest, nil
+}
+
+func (r *PostgresPriceFeedRepository) GetOldestTimestamps(ctx context.Context, before time.Time) ([]*models.LatestTimestamp, error) {
+	oldest, err := queryLatestTimestamps(ctx, r.db,
+		`SELECT symbol, '', MIN(timestamp) FROM `+r.table+` WHERE timestamp < $1 GROUP BY symbol`, before)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get oldest price feed timestamps")
+		return nil, fmt.Errorf("failed to get oldest price feed timestamps: %w", err)
+	}
+	return oldest, nil
+}
+
+// LatencyStats computes percentiles in the database so only one row per source is returned;
+// feeds written before receive and persist times were recorded are skipped
+func (r *PostgresPriceFeedRepository) LatencyStats(ctx context.Context, from, to time.Time) ([]*models.LatencyStats, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query := `SELECT source, COUNT(*),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM received_at - timestamp)),
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM received_at - timestamp)),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM persisted_at - received_at)),
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM persisted_at - received_at)),
+			GREATEST(MAX(EXTRACT(EPOCH FROM timestamp - received_at)), 0)
+		FROM ` + r.table + `
+		WHERE received_at >= $1 AND received_at < $2 AND persisted_at IS NOT NULL
+		GROUP BY source
+		ORDER BY source`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query price feed latency")
+		return nil, fmt.Errorf("failed to query price feed latency: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.LatencyStats
+	for rows.Next() {
+		var stat models.LatencyStats
+		var feedP50, feedP99, persistP50, persistP99, skew float64
+		if err := rows.Scan(&stat.Source, &stat.Samples, &feedP50, &feedP99, &persistP50, &persistP99, &skew); err != nil {
+			return nil, fmt.Errorf("failed to scan price feed latency: %w", err)
+		}
+		stat.FeedLatencyP50 = secondsToDuration(feedP50)
+		stat.FeedLatencyP99 = secondsToDuration(feedP99)
+		stat.PersistLatencyP50 = secondsToDuration(persistP50)
+		stat.PersistLatencyP99 = secondsToDuration(persistP99)
+		stat.ClockSkew = secondsToDuration(skew)
+		stats = append(stats, &stat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price feed latency: %w", err)
+	}
+	return stats, nil
 }
 
+// DeleteOlderThan is retention cleanup and deliberately emits no outbox events. The
+// idempotency keys of deleted feeds go too, so a replay that old is stored again.
 func (r *PostgresPriceFeedRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
-	return 0, fmt.Errorf("not implemented: DeleteOlderThan")
+	var deleted int64
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE timestamp < $1`, timestamp)
+		if err != nil {
+			return err
+		}
+		if deleted, err = result.RowsAffected(); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM `+r.keysTable+` WHERE timestamp < $1`, timestamp)
+		return err
+	})
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old price feeds")
+		return 0, fmt.Errorf("failed to delete old price feeds: %w", err)
+	}
+	return deleted, nil
+}
+
+// scanPriceFeed reads one row selected with priceFeedColumns, mapping no rows to ErrNotFound.
+// Rows written before receive and persist times were recorded leave them zero.
+func scanPriceFeed(row rowScanner) (*models.PriceFeed, error) {
+	var (
+		feed           models.PriceFeed
+		bid            decimal.NullDecimal
+		ask            decimal.NullDecimal
+		volume24h      decimal.NullDecimal
+		metadata       []byte
+		idempotencyKey sql.NullString
+		sequence       sql.NullInt64
+		receivedAt     sql.NullTime
+		persistedAt    sql.NullTime
+	)
+
+	err := row.Scan(
+		&feed.FeedID, &feed.Symbol, &feed.Price, &bid, &ask, &volume24h, &feed.Source, &feed.Timestamp, &metadata,
+		&idempotencyKey, &sequence, &receivedAt, &persistedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("price feed %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	feed.Bid = decimalPtr(bid)
+	feed.Ask = decimalPtr(ask)
+	feed.Volume24h = decimalPtr(volume24h)
+	feed.Metadata = metadata
+	if idempotencyKey.Valid {
+		feed.IdempotencyKey = &idempotencyKey.String
+	}
+	if sequence.Valid {
+		feed.Sequence = &sequence.Int64
+	}
+	feed.ReceivedAt = receivedAt.Time
+	feed.PersistedAt = persistedAt.Time
+
+	return &feed, nil
 }
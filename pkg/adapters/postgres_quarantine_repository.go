@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const quarantineColumns = `quarantine_id, feed, findings, quarantined_at`
+
+// PostgresQuarantineRepository keeps quarantined price feeds whole as JSON, so a reviewer
+// can release one by passing its Feed back to the price feed repository
+type PostgresQuarantineRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewPostgresQuarantineRepository(db *sql.DB, schema string, logger *logrus.Logger) interfaces.QuarantineRepository {
+	return newPostgresQuarantineRepository(asDBTX(db), schema, logger)
+}
+
+func newPostgresQuarantineRepository(db dbtx, schema string, logger *logrus.Logger) *PostgresQuarantineRepository {
+	return &PostgresQuarantineRepository{
+		db:     db,
+		table:  qualifiedTable(schema, "price_feed_quarantine"),
+		logger: logger,
+	}
+}
+
+func (r *PostgresQuarantineRepository) Create(ctx context.Context, quarantined *models.QuarantinedFeed) error {
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+	if quarantined.QuarantineID == "" {
+		quarantined.QuarantineID = uuid.New().String()
+	}
+	if quarantined.QuarantinedAt.IsZero() {
+		quarantined.QuarantinedAt = time.Now()
+	}
+
+	feed, err := json.Marshal(quarantined.Feed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quarantined feed: %w", err)
+	}
+	findings, err := json.Marshal(quarantined.Findings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal anomaly findings: %w", err)
+	}
+
+	query := `INSERT INTO ` + r.table + ` (quarantine_id, symbol, source, price, feed, findings, quarantined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		quarantined.QuarantineID, quarantined.Feed.Symbol, quarantined.Feed.Source, quarantined.Feed.Price,
+		feed, findings, quarantined.QuarantinedAt,
+	); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol": quarantined.Feed.Symbol,
+			"source": quarantined.Feed.Source,
+		}).Error("Failed to quarantine price feed")
+		return fmt.Errorf("failed to quarantine price feed: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresQuarantineRepository) GetByID(ctx context.Context, quarantineID string) (*models.QuarantinedFeed, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	query := `SELECT ` + quarantineColumns + ` FROM ` + r.table + ` WHERE quarantine_id = $1`
+
+	quarantined, err := scanQuarantinedFeed(r.db.QueryRowContext(ctx, query, quarantineID))
+	if err != nil {
+		r.logger.WithError(err).WithField("quarantine_id", quarantineID).Error("Failed to get quarantined feed")
+		return nil, fmt.Errorf("failed to get quarantined feed: %w", err)
+	}
+	return quarantined, nil
+}
+
+func (r *PostgresQuarantineRepository) Query(ctx context.Context, query *models.QuarantineQuery) ([]*models.QuarantinedFeed, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.Source != nil {
+		where.add("source =", *query.Source)
+	}
+	if query.QuarantinedAtFrom != nil {
+		where.add("quarantined_at >=", *query.QuarantinedAtFrom)
+	}
+	if query.QuarantinedAtTo != nil {
+		where.add("quarantined_at <", *query.QuarantinedAtTo)
+	}
+
+	statement := `SELECT ` + quarantineColumns + ` FROM ` + r.table + where.String() +
+		` ORDER BY quarantined_at DESC` + limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query quarantined feeds")
+		return nil, fmt.Errorf("failed to query quarantined feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.QuarantinedFeed
+	for rows.Next() {
+		quarantined, err := scanQuarantinedFeed(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, quarantined)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quarantined feeds: %w", err)
+	}
+	return result, nil
+}
+
+func (r *PostgresQuarantineRepository) Delete(ctx context.Context, quarantineID string) error {
+	if r.db == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE quarantine_id = $1`, quarantineID)
+	if err == nil {
+		var deleted int64
+		if deleted, err = result.RowsAffected(); err == nil && deleted == 0 {
+			err = fmt.Errorf("quarantined feed %w", interfaces.ErrNotFound)
+		}
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("quarantine_id", quarantineID).Error("Failed to delete quarantined feed")
+		return fmt.Errorf("failed to delete quarantined feed: %w", err)
+	}
+	return nil
+}
+
+// scanQuarantinedFeed reads one row selected with quarantineColumns, mapping no rows to ErrNotFound
+func scanQuarantinedFeed(row rowScanner) (*models.QuarantinedFeed, error) {
+	var (
+		quarantined models.QuarantinedFeed
+		feed        []byte
+		findings    []byte
+	)
+
+	err := row.Scan(&quarantined.QuarantineID, &feed, &findings, &quarantined.QuarantinedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quarantined feed %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan quarantined feed: %w", err)
+	}
+
+	if err := json.Unmarshal(feed, &quarantined.Feed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quarantined feed: %w", err)
+	}
+	if err := json.Unmarshal(findings, &quarantined.Findings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal anomaly findings: %w", err)
+	}
+	return &quarantined, nil
+}
@@ -4,52 +4,244 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
+const symbolColumns = `symbol_id, symbol, base_currency, quote_currency, display_name, is_active,
+	min_price_movement, min_order_size, max_order_size, created_at, updated_at, metadata`
+
+var symbolSortColumns = []string{"symbol", "base_currency", "quote_currency", "created_at", "updated_at"}
+
 type PostgresSymbolRepository struct {
-	db     *sql.DB
+	db     dbtx
+	table  string
+	outbox outboxWriter
 	logger *logrus.Logger
 }
 
-func NewPostgresSymbolRepository(db *sql.DB, logger *logrus.Logger) interfaces.SymbolRepository {
+func NewPostgresSymbolRepository(db *sql.DB, schema string, outboxEnabled bool, logger *logrus.Logger) interfaces.SymbolRepository {
+	return newPostgresSymbolRepository(asDBTX(db), schema, outboxEnabled, logger)
+}
+
+func newPostgresSymbolRepository(db dbtx, schema string, outboxEnabled bool, logger *logrus.Logger) *PostgresSymbolRepository {
 	return &PostgresSymbolRepository{
 		db:     db,
+		table:  qualifiedTable(schema, "symbols"),
+		outbox: newOutboxWriter(schema, outboxEnabled),
 		logger: logger,
 	}
 }
 
 func (r *PostgresSymbolRepository) Create(ctx context.Context, symbol *models.Symbol) error {
-	return fmt.Errorf("not implemented: Create symbol")
+	if symbol.SymbolID == "" {
+		symbol.SymbolID = uuid.New().String()
+	}
+	var createdAt *time.Time
+	if !symbol.CreatedAt.IsZero() {
+		createdAt = &symbol.CreatedAt
+	}
+
+	// updated_at comes from the database clock, the same one the change feed resyncs by
+	query := `INSERT INTO ` + r.table + ` (` + symbolColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW()), NOW(), $11)
+		RETURNING created_at, updated_at`
+
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		if err := tx.QueryRowContext(ctx, query,
+			symbol.SymbolID, symbol.Symbol, symbol.BaseCurrency, symbol.QuoteCurrency, symbol.DisplayName,
+			symbol.IsActive, symbol.MinPriceMovement, symbol.MinOrderSize, symbol.MaxOrderSize,
+			createdAt, nullableJSON(symbol.Metadata),
+		).Scan(&symbol.CreatedAt, &symbol.UpdatedAt); err != nil {
+			return err
+		}
+		return r.outbox.write(ctx, tx, interfaces.AggregateSymbol, symbol.SymbolID, interfaces.OperationCreated, symbol.Symbol, symbol)
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol.Symbol).Error("Failed to create symbol")
+		return fmt.Errorf("failed to create symbol: %w", err)
+	}
+	return nil
 }
 
 func (r *PostgresSymbolRepository) GetByID(ctx context.Context, symbolID string) (*models.Symbol, error) {
-	return nil, fmt.Errorf("not implemented: GetByID symbol")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	symbol, err := scanSymbol(r.db.QueryRowContext(ctx,
+		`SELECT `+symbolColumns+` FROM `+r.table+` WHERE symbol_id = $1`, symbolID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol %s: %w", symbolID, err)
+	}
+	return symbol, nil
 }
 
 func (r *PostgresSymbolRepository) GetBySymbol(ctx context.Context, symbol string) (*models.Symbol, error) {
-	return nil, fmt.Errorf("not implemented: GetBySymbol")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	found, err := scanSymbol(r.db.QueryRowContext(ctx,
+		`SELECT `+symbolColumns+` FROM `+r.table+` WHERE symbol = $1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol %s: %w", symbol, err)
+	}
+	return found, nil
 }
 
 func (r *PostgresSymbolRepository) Query(ctx context.Context, query *models.SymbolQuery) ([]*models.Symbol, error) {
-	return nil, fmt.Errorf("not implemented: Query symbols")
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.BaseCurrency != nil {
+		where.add("base_currency =", *query.BaseCurrency)
+	}
+	if query.QuoteCurrency != nil {
+		where.add("quote_currency =", *query.QuoteCurrency)
+	}
+	if query.IsActive != nil {
+		where.add("is_active =", *query.IsActive)
+	}
+
+	sortOrder := query.SortOrder
+	if query.SortBy == "" && sortOrder == "" {
+		sortOrder = "asc"
+	}
+	statement := `SELECT ` + symbolColumns + ` FROM ` + r.table + where.String() +
+		orderClause(query.SortBy, sortOrder, symbolSortColumns, "symbol") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query symbols")
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []*models.Symbol
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols: %w", err)
+	}
+	return symbols, nil
 }
 
 func (r *PostgresSymbolRepository) Update(ctx context.Context, symbol *models.Symbol) error {
-	return fmt.Errorf("not implemented: Update symbol")
+	query := `UPDATE ` + r.table + ` SET
+			symbol = $2, base_currency = $3, quote_currency = $4, display_name = $5, is_active = $6,
+			min_price_movement = $7, min_order_size = $8, max_order_size = $9, metadata = $10,
+			updated_at = NOW()
+		WHERE symbol_id = $1
+		RETURNING ` + symbolColumns
+
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		updated, err := scanSymbol(tx.QueryRowContext(ctx, query,
+			symbol.SymbolID, symbol.Symbol, symbol.BaseCurrency, symbol.QuoteCurrency, symbol.DisplayName,
+			symbol.IsActive, symbol.MinPriceMovement, symbol.MinOrderSize, symbol.MaxOrderSize,
+			nullableJSON(symbol.Metadata),
+		))
+		if err != nil {
+			return err
+		}
+
+		*symbol = *updated
+		return r.outbox.write(ctx, tx, interfaces.AggregateSymbol, symbol.SymbolID, interfaces.OperationUpdated, symbol.Symbol, symbol)
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol_id", symbol.SymbolID).Error("Failed to update symbol")
+		return fmt.Errorf("failed to update symbol: %w", err)
+	}
+	return nil
 }
 
 func (r *PostgresSymbolRepository) UpdateActiveStatus(ctx context.Context, symbolID string, isActive bool) error {
-	return fmt.Errorf("not implemented: UpdateActiveStatus")
+	query := `UPDATE ` + r.table + ` SET is_active = $2, updated_at = NOW()
+		WHERE symbol_id = $1
+		RETURNING ` + symbolColumns
+
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		updated, err := scanSymbol(tx.QueryRowContext(ctx, query, symbolID, isActive))
+		if err != nil {
+			return err
+		}
+		return r.outbox.write(ctx, tx, interfaces.AggregateSymbol, symbolID, interfaces.OperationUpdated, updated.Symbol, updated)
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol_id", symbolID).Error("Failed to update symbol active status")
+		return fmt.Errorf("failed to update symbol active status: %w", err)
+	}
+	return nil
 }
 
 func (r *PostgresSymbolRepository) GetActive(ctx context.Context) ([]*models.Symbol, error) {
-	return nil, fmt.Errorf("not implemented: GetActive")
+	active := true
+	return r.Query(ctx, &models.SymbolQuery{IsActive: &active})
 }
 
 func (r *PostgresSymbolRepository) Delete(ctx context.Context, symbolID string) error {
-	return fmt.Errorf("not implemented: Delete symbol")
+	query := `DELETE FROM ` + r.table + ` WHERE symbol_id = $1 RETURNING ` + symbolColumns
+
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		deleted, err := scanSymbol(tx.QueryRowContext(ctx, query, symbolID))
+		if err != nil {
+			return err
+		}
+		return r.outbox.write(ctx, tx, interfaces.AggregateSymbol, symbolID, interfaces.OperationDeleted, deleted.Symbol, deleted)
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol_id", symbolID).Error("Failed to delete symbol")
+		return fmt.Errorf("failed to delete symbol: %w", err)
+	}
+	return nil
+}
+
+// scanSymbol reads one row selected with symbolColumns, mapping no rows to ErrNotFound
+func scanSymbol(row rowScanner) (*models.Symbol, error) {
+	var (
+		symbol           models.Symbol
+		displayName      sql.NullString
+		minPriceMovement decimal.NullDecimal
+		minOrderSize     decimal.NullDecimal
+		maxOrderSize     decimal.NullDecimal
+		metadata         []byte
+	)
+
+	err := row.Scan(
+		&symbol.SymbolID, &symbol.Symbol, &symbol.BaseCurrency, &symbol.QuoteCurrency, &displayName,
+		&symbol.IsActive, &minPriceMovement, &minOrderSize, &maxOrderSize,
+		&symbol.CreatedAt, &symbol.UpdatedAt, &metadata,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("symbol %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if displayName.Valid {
+		symbol.DisplayName = &displayName.String
+	}
+	symbol.MinPriceMovement = decimalPtr(minPriceMovement)
+	symbol.MinOrderSize = decimalPtr(minOrderSize)
+	symbol.MaxOrderSize = decimalPtr(maxOrderSize)
+	symbol.Metadata = metadata
+
+	return &symbol, nil
 }
@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// PublishingPriceFeedRepository publishes every newly created price feed; replays
+// ignored as duplicates are not republished. Publish failures are logged rather than
+// returned: the write already succeeded.
+type PublishingPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	publisher interfaces.MarketDataPublisher
+	logger    *logrus.Logger
+}
+
+func NewPublishingPriceFeedRepository(repo interfaces.PriceFeedRepository, publisher interfaces.MarketDataPublisher, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return &PublishingPriceFeedRepository{
+		PriceFeedRepository: repo,
+		publisher:           publisher,
+		logger:              logger,
+	}
+}
+
+func (r *PublishingPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	result, err := r.PriceFeedRepository.Create(ctx, feed)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.Duplicate {
+		r.publish(ctx, feed)
+	}
+	return result, nil
+}
+
+func (r *PublishingPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results, err := r.PriceFeedRepository.CreateBatch(ctx, feeds)
+	if err != nil {
+		return results, err
+	}
+
+	for i, result := range results {
+		if !result.Duplicate && !result.Rejected {
+			r.publish(ctx, feeds[i])
+		}
+	}
+	return results, nil
+}
+
+func (r *PublishingPriceFeedRepository) publish(ctx context.Context, feed *models.PriceFeed) {
+	if err := r.publisher.PublishPriceFeed(ctx, feed); err != nil {
+		r.logger.WithError(err).WithField("feed_id", feed.FeedID).Warn("Price feed stored but not published")
+	}
+}
+
+// PublishingMarketSnapshotRepository publishes every successfully created snapshot.
+// Publish failures are logged rather than returned: the write already succeeded.
+type PublishingMarketSnapshotRepository struct {
+	interfaces.MarketSnapshotRepository
+	publisher interfaces.MarketDataPublisher
+	logger    *logrus.Logger
+}
+
+func NewPublishingMarketSnapshotRepository(repo interfaces.MarketSnapshotRepository, publisher interfaces.MarketDataPublisher, logger *logrus.Logger) interfaces.MarketSnapshotRepository {
+	return &PublishingMarketSnapshotRepository{
+		MarketSnapshotRepository: repo,
+		publisher:                publisher,
+		logger:                   logger,
+	}
+}
+
+func (r *PublishingMarketSnapshotRepository) Create(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	if err := r.MarketSnapshotRepository.Create(ctx, snapshot); err != nil {
+		return err
+	}
+
+	if err := r.publisher.PublishSnapshot(ctx, snapshot); err != nil {
+		r.logger.WithError(err).WithField("snapshot_id", snapshot.SnapshotID).Warn("Snapshot stored but not published")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubPriceFeedRepository fails Create with createErr and reports repeated idempotency
+// keys as duplicates; other methods fall through to the nil interface
+type stubPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	createErr error
+	created   []*models.PriceFeed
+}
+
+func (s *stubPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	if s.createErr != nil {
+		return interfaces.CreateResult{}, s.createErr
+	}
+	for _, existing := range s.created {
+		if feed.IdempotencyKey != nil && existing.IdempotencyKey != nil && *existing.IdempotencyKey == *feed.IdempotencyKey {
+			return interfaces.CreateResult{FeedID: existing.FeedID, Duplicate: true}, nil
+		}
+	}
+	s.created = append(s.created, feed)
+	return interfaces.CreateResult{FeedID: feed.FeedID}, nil
+}
+
+func (s *stubPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	var results []interfaces.CreateResult
+	for _, feed := range feeds {
+		result, err := s.Create(ctx, feed)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// recordingPublisher records published updates and fails with publishErr if set
+type recordingPublisher struct {
+	interfaces.MarketDataPublisher
+	publishErr error
+	feeds      []*models.PriceFeed
+	updates    []*interfaces.MarketDataUpdate
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, update *interfaces.MarketDataUpdate) error {
+	p.updates = append(p.updates, update)
+	return p.publishErr
+}
+
+func (p *recordingPublisher) PublishPriceFeed(ctx context.Context, feed *models.PriceFeed) error {
+	p.feeds = append(p.feeds, feed)
+	return p.publishErr
+}
+
+func newQuietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	return logger
+}
+
+func TestPublishingPriceFeedRepository_PublishesAfterWrite(t *testing.T) {
+	repo := &stubPriceFeedRepository{}
+	publisher := &recordingPublisher{}
+	feed := &models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", Price: decimal.NewFromInt(50000)}
+
+	_, err := NewPublishingPriceFeedRepository(repo, publisher, newQuietLogger()).Create(context.Background(), feed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.PriceFeed{feed}, repo.created)
+	assert.Equal(t, []*models.PriceFeed{feed}, publisher.feeds)
+}
+
+func TestPublishingPriceFeedRepository_SkipsPublishWhenWriteFails(t *testing.T) {
+	repo := &stubPriceFeedRepository{createErr: errors.New("insert failed")}
+	publisher := &recordingPublisher{}
+
+	_, err := NewPublishingPriceFeedRepository(repo, publisher, newQuietLogger()).Create(context.Background(), &models.PriceFeed{Symbol: "BTC-USD"})
+
+	assert.Error(t, err)
+	assert.Empty(t, publisher.feeds, "Failed writes must not be published")
+}
+
+func TestPublishingPriceFeedRepository_PublishFailureDoesNotFailWrite(t *testing.T) {
+	repo := &stubPriceFeedRepository{}
+	publisher := &recordingPublisher{publishErr: errors.New("redis down")}
+
+	_, err := NewPublishingPriceFeedRepository(repo, publisher, newQuietLogger()).Create(context.Background(), &models.PriceFeed{Symbol: "BTC-USD"})
+
+	assert.NoError(t, err, "The write succeeded, so Create should too")
+	assert.Len(t, repo.created, 1)
+}
+
+func TestPublishingPriceFeedRepository_SkipsPublishForDuplicates(t *testing.T) {
+	repo := &stubPriceFeedRepository{}
+	publisher := &recordingPublisher{}
+	decorated := NewPublishingPriceFeedRepository(repo, publisher, newQuietLogger())
+
+	key := models.SequenceIdempotencyKey("coinbase", 42)
+	first := &models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", IdempotencyKey: &key}
+	replay := &models.PriceFeed{FeedID: "feed-2", Symbol: "BTC-USD", IdempotencyKey: &key}
+	other := &models.PriceFeed{FeedID: "feed-3", Symbol: "ETH-USD"}
+
+	results, err := decorated.CreateBatch(context.Background(), []*models.PriceFeed{first, replay, other})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interfaces.CreateResult{
+		{FeedID: "feed-1"},
+		{FeedID: "feed-1", Duplicate: true},
+		{FeedID: "feed-3"},
+	}, results)
+	assert.Equal(t, []*models.PriceFeed{first, other}, publisher.feeds, "Replays must not be republished")
+
+	result, err := decorated.Create(context.Background(), replay)
+	assert.NoError(t, err)
+	assert.True(t, result.Duplicate)
+	assert.Len(t, publisher.feeds, 2)
+}
@@ -0,0 +1,161 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+type RedisEventLog struct {
+	client    *redis.Client
+	namespace string
+	maxLen    int64
+	logger    *logrus.Logger
+}
+
+func NewRedisEventLog(client *redis.Client, namespace string, maxLen int64, logger *logrus.Logger) interfaces.EventLog {
+	return &RedisEventLog{
+		client:    client,
+		namespace: namespace,
+		maxLen:    maxLen,
+		logger:    logger,
+	}
+}
+
+func (r *RedisEventLog) streamKey(stream interfaces.EventStream) string {
+	return fmt.Sprintf("%s:stream:%s", r.namespace, stream)
+}
+
+func (r *RedisEventLog) Append(ctx context.Context, event *interfaces.LogEvent) (string, error) {
+	values := map[string]interface{}{
+		"symbol":    event.Symbol,
+		"payload":   string(event.Payload),
+		"timestamp": event.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+	if event.EventID != "" {
+		values["event_id"] = event.EventID
+	}
+
+	args := &redis.XAddArgs{
+		Stream: r.streamKey(event.Stream),
+		Values: values,
+	}
+	if r.maxLen > 0 {
+		// Approximate trimming lets Redis drop whole macro nodes, which is far cheaper
+		args.MaxLen = r.maxLen
+		args.Approx = true
+	}
+
+	id, err := r.client.XAdd(ctx, args).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("stream", event.Stream).Error("Failed to append event")
+		return "", fmt.Errorf("failed to append event: %w", err)
+	}
+
+	event.ID = id
+	return id, nil
+}
+
+func (r *RedisEventLog) EnsureGroup(ctx context.Context, stream interfaces.EventStream, group string) error {
+	err := r.client.XGroupCreateMkStream(ctx, r.streamKey(stream), group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		r.logger.WithError(err).WithField("group", group).Error("Failed to create consumer group")
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisEventLog) Read(ctx context.Context, stream interfaces.EventStream, group, consumer string, count int, block time.Duration) ([]*interfaces.LogEvent, error) {
+	if block <= 0 {
+		// go-redis omits BLOCK for negative durations; zero would block forever
+		block = -1
+	}
+
+	result, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{r.streamKey(stream), ">"},
+		Count:    int64(count),
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return []*interfaces.LogEvent{}, nil
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("group", group).Error("Failed to read events")
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	events := []*interfaces.LogEvent{}
+	for _, s := range result {
+		events = append(events, r.toEvents(stream, s.Messages)...)
+	}
+	return events, nil
+}
+
+func (r *RedisEventLog) Ack(ctx context.Context, stream interfaces.EventStream, group string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := r.client.XAck(ctx, r.streamKey(stream), group, ids...).Err(); err != nil {
+		r.logger.WithError(err).WithField("group", group).Error("Failed to acknowledge events")
+		return fmt.Errorf("failed to acknowledge events: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisEventLog) Claim(ctx context.Context, stream interfaces.EventStream, group, consumer string, minIdle time.Duration, count int) ([]*interfaces.LogEvent, error) {
+	messages, _, err := r.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   r.streamKey(stream),
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    int64(count),
+	}).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("group", group).Error("Failed to claim pending events")
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+
+	return r.toEvents(stream, messages), nil
+}
+
+func (r *RedisEventLog) Trim(ctx context.Context, stream interfaces.EventStream, maxLen int64) (int64, error) {
+	removed, err := r.client.XTrimMaxLen(ctx, r.streamKey(stream), maxLen).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("stream", stream).Error("Failed to trim stream")
+		return 0, fmt.Errorf("failed to trim stream: %w", err)
+	}
+	return removed, nil
+}
+
+func (r *RedisEventLog) toEvents(stream interfaces.EventStream, messages []redis.XMessage) []*interfaces.LogEvent {
+	events := make([]*interfaces.LogEvent, 0, len(messages))
+	for _, msg := range messages {
+		event := &interfaces.LogEvent{
+			ID:     msg.ID,
+			Stream: stream,
+		}
+		if eventID, ok := msg.Values["event_id"].(string); ok {
+			event.EventID = eventID
+		}
+		if symbol, ok := msg.Values["symbol"].(string); ok {
+			event.Symbol = symbol
+		}
+		if payload, ok := msg.Values["payload"].(string); ok {
+			event.Payload = []byte(payload)
+		}
+		if ts, ok := msg.Values["timestamp"].(string); ok {
+			event.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
+		}
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,127 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisEventLog_GroupsDeliverEachEventOnce(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	log := NewRedisEventLog(client, "market_data", 0, newQuietLogger())
+	ctx := context.Background()
+
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamPriceFeeds, "analytics"))
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamPriceFeeds, "analytics"), "An existing group should not be an error")
+
+	event, err := interfaces.NewPriceFeedEvent(&models.PriceFeed{Symbol: "BTC-USD", Price: decimal.NewFromInt(100)})
+	require.NoError(t, err)
+	event.EventID = "evt-1"
+	id, err := log.Append(ctx, event)
+	require.NoError(t, err)
+	assert.Equal(t, id, event.ID)
+	appendPriceFeed(t, log, "ETH-USD")
+
+	first, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-1", 1, 0)
+	require.NoError(t, err)
+	second, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", 10, 0)
+	require.NoError(t, err)
+	third, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", 10, 0)
+	require.NoError(t, err)
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	assert.Empty(t, third, "A non-blocking read with nothing new should return empty")
+
+	assert.Equal(t, id, first[0].ID)
+	assert.Equal(t, "evt-1", first[0].EventID)
+	assert.Equal(t, interfaces.StreamPriceFeeds, first[0].Stream)
+	assert.Equal(t, "BTC-USD", first[0].Symbol)
+	assert.WithinDuration(t, event.Timestamp, first[0].Timestamp, time.Microsecond)
+	assert.Equal(t, "ETH-USD", second[0].Symbol)
+
+	var feed models.PriceFeed
+	require.NoError(t, first[0].Decode(&feed))
+	assert.True(t, feed.Price.Equal(decimal.NewFromInt(100)))
+}
+
+func TestRedisEventLog_ReadWithoutGroupFails(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	log := NewRedisEventLog(client, "market_data", 0, newQuietLogger())
+	appendPriceFeed(t, log, "BTC-USD")
+
+	_, err := log.Read(context.Background(), interfaces.StreamPriceFeeds, "missing", "worker-1", 10, 0)
+
+	assert.ErrorContains(t, err, "failed to read events")
+}
+
+func TestRedisEventLog_ClaimReclaimsUnacknowledged(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	log := NewRedisEventLog(client, "market_data", 0, newQuietLogger())
+	ctx := context.Background()
+
+	require.NoError(t, log.EnsureGroup(ctx, interfaces.StreamPriceFeeds, "analytics"))
+	ackedID := appendPriceFeed(t, log, "BTC-USD")
+	lostID := appendPriceFeed(t, log, "ETH-USD")
+
+	_, err := log.Read(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-1", 10, 0)
+	require.NoError(t, err)
+	require.NoError(t, log.Ack(ctx, interfaces.StreamPriceFeeds, "analytics", ackedID))
+	require.NoError(t, log.Ack(ctx, interfaces.StreamPriceFeeds, "analytics"), "Acking nothing should be a no-op")
+
+	claimed, err := log.Claim(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "Entries should not be claimable before minIdle")
+
+	time.Sleep(20 * time.Millisecond)
+	claimed, err = log.Claim(ctx, interfaces.StreamPriceFeeds, "analytics", "worker-2", 10*time.Millisecond, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, lostID, claimed[0].ID)
+	assert.Equal(t, "ETH-USD", claimed[0].Symbol)
+}
+
+func TestRedisEventLog_TrimsToMaxLen(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	log := NewRedisEventLog(client, "market_data", 2, newQuietLogger())
+	ctx := context.Background()
+
+	for _, symbol := range []string{"BTC-USD", "ETH-USD", "SOL-USD"} {
+		event, err := interfaces.NewCandleEvent(&models.Candle{Symbol: symbol})
+		require.NoError(t, err)
+		_, err = log.Append(ctx, event)
+		require.NoError(t, err)
+	}
+
+	entries, err := server.Stream("market_data:stream:" + string(interfaces.StreamCandles))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "Appends should pass the MAXLEN bound to Redis")
+
+	removed, err := log.Trim(ctx, interfaces.StreamCandles, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	entries, err = server.Stream("market_data:stream:" + string(interfaces.StreamCandles))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0].Values, "SOL-USD", "Trimming should keep the newest entries")
+}
+
+func TestRedisEventLog_AppendReturnsRedisErrors(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	log := NewRedisEventLog(client, "market_data", 0, newQuietLogger())
+	server.SetError("NOPERM this user has no permissions to access the stream")
+
+	event, err := interfaces.NewPriceFeedEvent(&models.PriceFeed{Symbol: "BTC-USD"})
+	require.NoError(t, err)
+	_, err = log.Append(context.Background(), event)
+
+	assert.ErrorContains(t, err, "failed to append event")
+	assert.Empty(t, event.ID, "A failed append should not assign an ID")
+}
@@ -0,0 +1,209 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// RedisLeaderElector elects a single leader among replicas sharing an election name.
+// The leader key holds the leader's identity with a lease TTL; the leader renews it
+// every renewInterval and followers retry acquisition on the same schedule.
+type RedisLeaderElector struct {
+	client        *redis.Client
+	namespace     string
+	election      string
+	identity      string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	callbacks     interfaces.LeaderCallbacks
+	logger        *logrus.Logger
+	now           func() time.Time
+
+	mu             sync.Mutex
+	isLeader       bool
+	leaseDeadline  time.Time
+	observedLeader string
+	cancelLeading  context.CancelFunc
+}
+
+func NewRedisLeaderElector(client *redis.Client, namespace, election, identity string, leaseDuration, renewInterval time.Duration, callbacks interfaces.LeaderCallbacks, logger *logrus.Logger) interfaces.LeaderElector {
+	return &RedisLeaderElector{
+		client:        client,
+		namespace:     namespace,
+		election:      election,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		renewInterval: renewInterval,
+		callbacks:     callbacks,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+func (r *RedisLeaderElector) leaderKey() string {
+	return fmt.Sprintf("%s:leader:%s", r.namespace, r.election)
+}
+
+func (r *RedisLeaderElector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.renewInterval)
+	defer ticker.Stop()
+
+	r.logger.WithFields(logrus.Fields{
+		"election": r.election,
+		"identity": r.identity,
+	}).Info("Leader election started")
+
+	for {
+		r.tryAcquireOrRenew(ctx)
+
+		select {
+		case <-ctx.Done():
+			// Resign with a fresh context so the key is released even though ctx is done
+			resignCtx, cancel := context.WithTimeout(context.Background(), r.renewInterval)
+			err := r.Resign(resignCtx)
+			cancel()
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *RedisLeaderElector) tryAcquireOrRenew(ctx context.Context) {
+	// Measure the lease from before the request: Redis starts its TTL somewhere in the
+	// round trip, and the deadline we keep must not end after the one Redis enforces
+	sent := r.now()
+
+	if r.IsLeader() {
+		renewed, err := renewLockScript.Run(ctx, r.client, []string{r.leaderKey()}, r.identity, r.leaseDuration.Milliseconds()).Int64()
+		switch {
+		case err != nil:
+			r.logger.WithError(err).WithField("election", r.election).Warn("Failed to renew leadership lease")
+			// Keep leading until the lease we last confirmed runs out; campaigning meanwhile
+			// would only fail against our own key
+			r.mu.Lock()
+			expired := r.now().After(r.leaseDeadline)
+			r.mu.Unlock()
+			if !expired {
+				return
+			}
+			r.stopLeading()
+		case renewed == 0:
+			r.logger.WithField("election", r.election).Warn("Leadership lease lost")
+			r.stopLeading()
+		default:
+			r.mu.Lock()
+			r.leaseDeadline = sent.Add(r.leaseDuration)
+			r.mu.Unlock()
+			return
+		}
+		sent = r.now()
+	}
+
+	acquired, err := r.client.SetNX(ctx, r.leaderKey(), r.identity, r.leaseDuration).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("election", r.election).Warn("Failed to campaign for leadership")
+		return
+	}
+	if acquired {
+		r.startLeading(sent.Add(r.leaseDuration))
+		r.observeLeader(r.identity)
+		return
+	}
+
+	leader, err := r.Leader(ctx)
+	if err == nil {
+		r.observeLeader(leader)
+	}
+}
+
+func (r *RedisLeaderElector) startLeading(leaseDeadline time.Time) {
+	r.mu.Lock()
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	r.isLeader = true
+	r.leaseDeadline = leaseDeadline
+	r.cancelLeading = cancel
+	r.mu.Unlock()
+
+	r.logger.WithFields(logrus.Fields{
+		"election": r.election,
+		"identity": r.identity,
+	}).Info("Leadership acquired")
+
+	if r.callbacks.OnStartedLeading != nil {
+		go r.callbacks.OnStartedLeading(leaderCtx)
+	}
+}
+
+func (r *RedisLeaderElector) stopLeading() {
+	r.mu.Lock()
+	if !r.isLeader {
+		r.mu.Unlock()
+		return
+	}
+	r.isLeader = false
+	r.cancelLeading()
+	r.cancelLeading = nil
+	r.mu.Unlock()
+
+	r.logger.WithFields(logrus.Fields{
+		"election": r.election,
+		"identity": r.identity,
+	}).Info("Leadership lost")
+
+	if r.callbacks.OnStoppedLeading != nil {
+		r.callbacks.OnStoppedLeading()
+	}
+}
+
+func (r *RedisLeaderElector) observeLeader(identity string) {
+	r.mu.Lock()
+	changed := identity != r.observedLeader
+	r.observedLeader = identity
+	r.mu.Unlock()
+
+	if changed && r.callbacks.OnNewLeader != nil {
+		r.callbacks.OnNewLeader(identity)
+	}
+}
+
+func (r *RedisLeaderElector) IsLeader() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.isLeader
+}
+
+func (r *RedisLeaderElector) Leader(ctx context.Context) (string, error) {
+	leader, err := r.client.Get(ctx, r.leaderKey()).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("no leader elected: %s", r.election)
+	}
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get leader")
+		return "", fmt.Errorf("failed to get leader: %w", err)
+	}
+	return leader, nil
+}
+
+func (r *RedisLeaderElector) Identity() string {
+	return r.identity
+}
+
+func (r *RedisLeaderElector) Resign(ctx context.Context) error {
+	if !r.IsLeader() {
+		return nil
+	}
+
+	r.stopLeading()
+
+	if err := releaseLockScript.Run(ctx, r.client, []string{r.leaderKey()}, r.identity).Err(); err != nil {
+		r.logger.WithError(err).WithField("election", r.election).Error("Failed to release leadership")
+		return fmt.Errorf("failed to release leadership: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leaderEvents records the callbacks an elector fires
+type leaderEvents struct {
+	mu      sync.Mutex
+	started int
+	stopped int
+	leaders []string
+}
+
+func (e *leaderEvents) callbacks() interfaces.LeaderCallbacks {
+	return interfaces.LeaderCallbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.started++
+		},
+		OnStoppedLeading: func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.stopped++
+		},
+		OnNewLeader: func(identity string) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.leaders = append(e.leaders, identity)
+		},
+	}
+}
+
+func (e *leaderEvents) startedCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.started
+}
+
+func (e *leaderEvents) stoppedCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.stopped
+}
+
+func newTestLeaderElector(t *testing.T, client *redis.Client, identity string, logger *logrus.Logger) (*RedisLeaderElector, *leaderEvents) {
+	t.Helper()
+
+	events := &leaderEvents{}
+	elector := NewRedisLeaderElector(client, "market-data", "retention", identity,
+		10*time.Second, time.Second, events.callbacks(), logger).(*RedisLeaderElector)
+	return elector, events
+}
+
+func TestRedisLeaderElector_AcquireRenewLoseReacquire(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	first, firstEvents := newTestLeaderElector(t, client, "replica-1", newQuietLogger())
+	second, secondEvents := newTestLeaderElector(t, client, "replica-2", newQuietLogger())
+	key := first.leaderKey()
+
+	first.tryAcquireOrRenew(ctx)
+	second.tryAcquireOrRenew(ctx)
+
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+	assert.Equal(t, []string{"replica-1"}, secondEvents.leaders, "Followers should observe the current leader")
+
+	// Renewal pushes the lease back out
+	server.FastForward(8 * time.Second)
+	first.tryAcquireOrRenew(ctx)
+	assert.True(t, first.IsLeader())
+	assert.Equal(t, 10*time.Second, server.TTL(key))
+
+	// Another replica took the key, e.g. after a partition let the lease expire
+	require.NoError(t, server.Set(key, "replica-2"))
+	first.tryAcquireOrRenew(ctx)
+	assert.False(t, first.IsLeader())
+	assert.Equal(t, 1, firstEvents.stoppedCount())
+
+	// Once the key is free again the old leader can win it back
+	server.Del(key)
+	first.tryAcquireOrRenew(ctx)
+	assert.True(t, first.IsLeader())
+	assert.Eventually(t, func() bool { return firstEvents.startedCount() == 2 }, time.Second, 10*time.Millisecond,
+		"OnStartedLeading runs again for the new term")
+
+	require.NoError(t, first.Resign(ctx))
+	assert.False(t, server.Exists(key), "Resigning should release the leader key")
+}
+
+func TestRedisLeaderElector_KeepsLeadingThroughRenewErrorsUntilLeaseExpires(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	ctx := context.Background()
+
+	logger, hook := test.NewNullLogger()
+	elector, events := newTestLeaderElector(t, client, "replica-1", logger)
+
+	elector.tryAcquireOrRenew(ctx)
+	require.True(t, elector.IsLeader())
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	hook.Reset()
+	elector.tryAcquireOrRenew(ctx)
+
+	assert.True(t, elector.IsLeader(), "A renew error within the lease should not give up leadership")
+	assert.Zero(t, events.stoppedCount())
+	for _, entry := range hook.AllEntries() {
+		assert.NotEqual(t, "Failed to campaign for leadership", entry.Message, "A leader should not campaign against its own key")
+	}
+
+	elector.mu.Lock()
+	elector.leaseDeadline = time.Now().Add(-time.Second)
+	elector.mu.Unlock()
+	elector.tryAcquireOrRenew(ctx)
+
+	assert.False(t, elector.IsLeader(), "Leadership should end once the confirmed lease runs out")
+	assert.Equal(t, 1, events.stoppedCount())
+}
+
+func TestRedisLeaderElector_LeaseIsMeasuredFromBeforeTheRoundTrip(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	ctx := context.Background()
+	elector, _ := newTestLeaderElector(t, client, "replica-1", newQuietLogger())
+
+	// Every clock reading lands a second later, as if each round trip took that long
+	start := time.Now()
+	readings := 0
+	elector.now = func() time.Time {
+		readings++
+		return start.Add(time.Duration(readings-1) * time.Second)
+	}
+
+	elector.tryAcquireOrRenew(ctx)
+	require.True(t, elector.IsLeader())
+	assert.Equal(t, start.Add(elector.leaseDuration), elector.leaseDeadline, "Acquisition should start the lease when SET was sent")
+
+	sent := start.Add(time.Duration(readings) * time.Second)
+	elector.tryAcquireOrRenew(ctx)
+	assert.Equal(t, sent.Add(elector.leaseDuration), elector.leaseDeadline, "Renewal should start the lease when the renew was sent")
+}
@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMiniredisClient starts an in-process Redis and a client connected to it
+func newMiniredisClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisLocker_AcquireIsExclusive(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	locker := NewRedisLocker(client, "market-data", newQuietLogger())
+	ctx := context.Background()
+
+	lock, err := locker.Acquire(ctx, "retention", time.Minute)
+	require.NoError(t, err)
+
+	_, err = locker.Acquire(ctx, "retention", time.Minute)
+	assert.ErrorIs(t, err, interfaces.ErrLockNotAcquired, "Second acquire should fail while the lock is held")
+
+	_, err = locker.Acquire(ctx, "materialization", time.Minute)
+	assert.NoError(t, err, "Different keys should not contend")
+
+	require.NoError(t, locker.Release(ctx, lock))
+	_, err = locker.Acquire(ctx, "retention", time.Minute)
+	assert.NoError(t, err, "A released lock should be acquirable")
+}
+
+func TestRedisLocker_OnlyOwnerReleasesOrRenews(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	locker := NewRedisLocker(client, "market-data", newQuietLogger())
+	ctx := context.Background()
+
+	lock, err := locker.Acquire(ctx, "retention", time.Minute)
+	require.NoError(t, err)
+
+	impostor := *lock
+	impostor.Token = "someone-else"
+	assert.ErrorIs(t, locker.Release(ctx, &impostor), interfaces.ErrLockNotHeld)
+	assert.ErrorIs(t, locker.Renew(ctx, &impostor, time.Hour), interfaces.ErrLockNotHeld)
+
+	held, err := server.Get("market-data:lock:retention")
+	require.NoError(t, err)
+	assert.Equal(t, lock.Token, held, "A non-owner must not disturb the lock")
+	assert.Equal(t, time.Minute, server.TTL("market-data:lock:retention"))
+
+	require.NoError(t, locker.Renew(ctx, lock, time.Hour))
+	assert.Equal(t, time.Hour, server.TTL("market-data:lock:retention"))
+}
+
+func TestRedisLocker_ExpiredLockCanBeTakenOver(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	locker := NewRedisLocker(client, "market-data", newQuietLogger())
+	ctx := context.Background()
+
+	stale, err := locker.Acquire(ctx, "retention", time.Second)
+	require.NoError(t, err)
+
+	server.FastForward(2 * time.Second)
+
+	fresh, err := locker.Acquire(ctx, "retention", time.Second)
+	require.NoError(t, err, "Expired lock should be acquirable")
+
+	assert.ErrorIs(t, locker.Release(ctx, stale), interfaces.ErrLockNotHeld,
+		"Previous owner must not release a lock it no longer holds")
+	assert.ErrorIs(t, locker.Renew(ctx, stale, time.Minute), interfaces.ErrLockNotHeld)
+	assert.NoError(t, locker.Release(ctx, fresh))
+}
+
+func TestRedisLocker_FencingTokensIncrease(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	locker := NewRedisLocker(client, "market-data", newQuietLogger())
+	ctx := context.Background()
+
+	var previous int64
+	for i := 0; i < 3; i++ {
+		lock, err := locker.Acquire(ctx, "retention", time.Second)
+		require.NoError(t, err)
+		assert.Greater(t, lock.FencingToken, previous, "Each acquisition should receive a strictly larger fencing token")
+		previous = lock.FencingToken
+
+		// Alternate between releasing and letting the lease lapse
+		if i%2 == 0 {
+			require.NoError(t, locker.Release(ctx, lock))
+		} else {
+			server.FastForward(2 * time.Second)
+		}
+	}
+
+	_, err := locker.Acquire(ctx, "retention", time.Second)
+	require.NoError(t, err)
+	_, err = locker.Acquire(ctx, "retention", time.Second)
+	require.ErrorIs(t, err, interfaces.ErrLockNotAcquired)
+
+	fence, err := server.Get("market-data:lock:retention:fence")
+	require.NoError(t, err)
+	assert.Equal(t, "4", fence, "Failed acquisitions must not consume fencing tokens")
+}
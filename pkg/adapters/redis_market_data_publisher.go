@@ -0,0 +1,112 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+type RedisMarketDataPublisher struct {
+	client    *redis.Client
+	namespace string
+	logger    *logrus.Logger
+}
+
+func NewRedisMarketDataPublisher(client *redis.Client, namespace string, logger *logrus.Logger) interfaces.MarketDataPublisher {
+	return &RedisMarketDataPublisher{
+		client:    client,
+		namespace: namespace,
+		logger:    logger,
+	}
+}
+
+func (r *RedisMarketDataPublisher) symbolChannel(symbol string) string {
+	return fmt.Sprintf("%s:market:%s", r.namespace, symbol)
+}
+
+func (r *RedisMarketDataPublisher) PublishPriceFeed(ctx context.Context, feed *models.PriceFeed) error {
+	return r.Publish(ctx, &interfaces.MarketDataUpdate{
+		Type:      interfaces.UpdatePriceFeed,
+		Symbol:    feed.Symbol,
+		PriceFeed: feed,
+	})
+}
+
+func (r *RedisMarketDataPublisher) PublishSnapshot(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	return r.Publish(ctx, &interfaces.MarketDataUpdate{
+		Type:     interfaces.UpdateSnapshot,
+		Symbol:   snapshot.Symbol,
+		Snapshot: snapshot,
+	})
+}
+
+func (r *RedisMarketDataPublisher) Publish(ctx context.Context, update *interfaces.MarketDataUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to marshal market data update")
+		return fmt.Errorf("failed to marshal market data update: %w", err)
+	}
+
+	if err := r.client.Publish(ctx, r.symbolChannel(update.Symbol), data).Err(); err != nil {
+		r.logger.WithError(err).WithField("symbol", update.Symbol).Error("Failed to publish market data update")
+		return fmt.Errorf("failed to publish market data update: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisMarketDataPublisher) Subscribe(ctx context.Context, symbols ...string) (<-chan *interfaces.MarketDataUpdate, error) {
+	var pubsub *redis.PubSub
+	if len(symbols) == 0 {
+		pubsub = r.client.PSubscribe(ctx, r.symbolChannel("*"))
+	} else {
+		channels := make([]string, len(symbols))
+		for i, symbol := range symbols {
+			channels[i] = r.symbolChannel(symbol)
+		}
+		pubsub = r.client.Subscribe(ctx, channels...)
+	}
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		r.logger.WithError(err).Error("Failed to subscribe to market data")
+		return nil, fmt.Errorf("failed to subscribe to market data: %w", err)
+	}
+
+	updates := make(chan *interfaces.MarketDataUpdate, 256)
+	go func() {
+		defer close(updates)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var update interfaces.MarketDataUpdate
+				if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+					r.logger.WithError(err).WithField("channel", msg.Channel).Warn("Failed to unmarshal market data update")
+					continue
+				}
+
+				select {
+				case updates <- &update:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextMarketDataUpdate waits for the next update, failing the test if none arrives
+func nextMarketDataUpdate(t *testing.T, updates <-chan *interfaces.MarketDataUpdate) *interfaces.MarketDataUpdate {
+	t.Helper()
+
+	select {
+	case update, ok := <-updates:
+		require.True(t, ok, "Update channel closed unexpectedly")
+		return update
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "Timed out waiting for a market data update")
+		return nil
+	}
+}
+
+func TestRedisMarketDataPublisher_SubscribeFiltersBySymbol(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	publisher := NewRedisMarketDataPublisher(client, "market_data", newQuietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := publisher.Subscribe(ctx, "BTC-USD")
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.PublishPriceFeed(ctx, &models.PriceFeed{Symbol: "ETH-USD", Price: decimal.RequireFromString("3000")}))
+	require.NoError(t, publisher.PublishPriceFeed(ctx, &models.PriceFeed{Symbol: "BTC-USD", Price: decimal.RequireFromString("65000")}))
+
+	update := nextMarketDataUpdate(t, updates)
+	assert.Equal(t, interfaces.UpdatePriceFeed, update.Type)
+	assert.Equal(t, "BTC-USD", update.Symbol, "Other symbols should not be delivered")
+	require.NotNil(t, update.PriceFeed)
+	assert.True(t, decimal.RequireFromString("65000").Equal(update.PriceFeed.Price))
+	assert.Nil(t, update.Snapshot)
+}
+
+func TestRedisMarketDataPublisher_SubscribeAllSymbols(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	publisher := NewRedisMarketDataPublisher(client, "market_data", newQuietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := publisher.Subscribe(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.PublishPriceFeed(ctx, &models.PriceFeed{Symbol: "ETH-USD", Price: decimal.RequireFromString("3000")}))
+	require.NoError(t, publisher.PublishSnapshot(ctx, &models.MarketSnapshot{Symbol: "BTC-USD", LastPrice: decimal.RequireFromString("65000")}))
+	require.NoError(t, publisher.Publish(ctx, &interfaces.MarketDataUpdate{
+		Type:      interfaces.UpdatePriceFeed,
+		EventID:   "evt-1",
+		Symbol:    "SOL-USD",
+		PriceFeed: &models.PriceFeed{Symbol: "SOL-USD", Price: decimal.RequireFromString("150")},
+	}))
+
+	update := nextMarketDataUpdate(t, updates)
+	assert.Equal(t, "ETH-USD", update.Symbol)
+
+	update = nextMarketDataUpdate(t, updates)
+	assert.Equal(t, interfaces.UpdateSnapshot, update.Type)
+	assert.Equal(t, "BTC-USD", update.Symbol)
+	require.NotNil(t, update.Snapshot)
+	assert.Nil(t, update.PriceFeed)
+
+	update = nextMarketDataUpdate(t, updates)
+	assert.Equal(t, "SOL-USD", update.Symbol)
+	assert.Equal(t, "evt-1", update.EventID, "Prepared updates should keep their outbox event ID")
+}
+
+func TestRedisMarketDataPublisher_SkipsMalformedUpdates(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	publisher := NewRedisMarketDataPublisher(client, "market_data", newQuietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := publisher.Subscribe(ctx, "BTC-USD")
+	require.NoError(t, err)
+
+	server.Publish("market_data:market:BTC-USD", "not json")
+	require.NoError(t, publisher.PublishPriceFeed(ctx, &models.PriceFeed{Symbol: "BTC-USD", Price: decimal.RequireFromString("65000")}))
+
+	update := nextMarketDataUpdate(t, updates)
+	assert.Equal(t, "BTC-USD", update.Symbol, "A malformed payload should be skipped, not end the subscription")
+}
+
+func TestRedisMarketDataPublisher_SubscriptionClosesWithContext(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	publisher := NewRedisMarketDataPublisher(client, "market_data", newQuietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	updates, err := publisher.Subscribe(ctx, "BTC-USD")
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok, "The channel should close once the context is done")
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "Subscription did not close after cancel")
+	}
+}
+
+func TestRedisMarketDataPublisher_PublishReturnsRedisErrors(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	publisher := NewRedisMarketDataPublisher(client, "market_data", newQuietLogger())
+	server.SetError("NOPERM this user has no permissions to access the channel")
+
+	err := publisher.PublishPriceFeed(context.Background(), &models.PriceFeed{Symbol: "BTC-USD", Price: decimal.RequireFromString("65000")})
+
+	assert.ErrorContains(t, err, "failed to publish market data update")
+}
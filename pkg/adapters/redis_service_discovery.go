@@ -4,24 +4,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/semver"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
 type RedisServiceDiscovery struct {
-	client    *redis.Client
-	namespace string
-	logger    *logrus.Logger
+	client             *redis.Client
+	namespace          string
+	ttl                time.Duration
+	configureKeyspaces bool // Watch may CONFIG SET notify-keyspace-events
+	logger             *logrus.Logger
+
+	notificationsOnce sync.Once
 }
 
-func NewRedisServiceDiscovery(client *redis.Client, namespace string, logger *logrus.Logger) interfaces.ServiceDiscoveryRepository {
+func NewRedisServiceDiscovery(client *redis.Client, namespace string, ttl time.Duration, configureKeyspaces bool, logger *logrus.Logger) interfaces.ServiceDiscoveryRepository {
 	return &RedisServiceDiscovery{
-		client:    client,
-		namespace: namespace,
-		logger:    logger,
+		client:             client,
+		namespace:          namespace,
+		ttl:                ttl,
+		configureKeyspaces: configureKeyspaces,
+		logger:             logger,
 	}
 }
 
@@ -33,28 +42,51 @@ func (r *RedisServiceDiscovery) heartbeatKey(serviceID string) string {
 	return fmt.Sprintf("%s:heartbeat:%s", r.namespace, serviceID)
 }
 
+func (r *RedisServiceDiscovery) eventsChannel() string {
+	return fmt.Sprintf("%s:events", r.namespace)
+}
+
+// expiredChannel is the keyevent channel Redis publishes expirations on for this client's DB
+func (r *RedisServiceDiscovery) expiredChannel() string {
+	return fmt.Sprintf("__keyevent@%d__:expired", r.client.Options().DB)
+}
+
 func (r *RedisServiceDiscovery) Register(ctx context.Context, info *interfaces.ServiceInfo) error {
 	key := r.serviceKey(info.ServiceID)
 	heartbeatKey := r.heartbeatKey(info.ServiceID)
 
+	now := time.Now()
+	if info.RegisteredAt.IsZero() {
+		info.RegisteredAt = now
+	}
+	info.LastHeartbeat = now
+
 	data, err := json.Marshal(info)
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to marshal service info")
 		return fmt.Errorf("failed to marshal service info: %w", err)
 	}
 
-	// Set service info with 90s TTL
-	if err := r.client.Set(ctx, key, data, 90*time.Second).Err(); err != nil {
+	// Set service info with the configured TTL
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
 		r.logger.WithError(err).Error("Failed to register service")
 		return fmt.Errorf("failed to register service: %w", err)
 	}
 
 	// Set initial heartbeat
-	if err := r.client.Set(ctx, heartbeatKey, time.Now().Unix(), 90*time.Second).Err(); err != nil {
+	if err := r.client.Set(ctx, heartbeatKey, now.Unix(), r.ttl).Err(); err != nil {
 		r.logger.WithError(err).Error("Failed to set heartbeat")
 		return fmt.Errorf("failed to set heartbeat: %w", err)
 	}
 
+	r.publishEvent(ctx, &interfaces.ServiceEvent{
+		Type:        interfaces.ServiceRegistered,
+		ServiceName: info.ServiceName,
+		ServiceID:   info.ServiceID,
+		Service:     info,
+		Timestamp:   now,
+	})
+
 	r.logger.WithField("service_id", info.ServiceID).Info("Service registered")
 	return nil
 }
@@ -63,11 +95,24 @@ func (r *RedisServiceDiscovery) Deregister(ctx context.Context, serviceID string
 	key := r.serviceKey(serviceID)
 	heartbeatKey := r.heartbeatKey(serviceID)
 
+	// Look up the name before deleting so watchers can filter the event
+	var serviceName string
+	if info, err := r.loadServiceInfo(ctx, key); err == nil {
+		serviceName = info.ServiceName
+	}
+
 	if err := r.client.Del(ctx, key, heartbeatKey).Err(); err != nil {
 		r.logger.WithError(err).Error("Failed to deregister service")
 		return fmt.Errorf("failed to deregister service: %w", err)
 	}
 
+	r.publishEvent(ctx, &interfaces.ServiceEvent{
+		Type:        interfaces.ServiceDeregistered,
+		ServiceName: serviceName,
+		ServiceID:   serviceID,
+		Timestamp:   time.Now(),
+	})
+
 	r.logger.WithField("service_id", serviceID).Info("Service deregistered")
 	return nil
 }
@@ -76,56 +121,126 @@ func (r *RedisServiceDiscovery) Heartbeat(ctx context.Context, serviceID string)
 	heartbeatKey := r.heartbeatKey(serviceID)
 	serviceKey := r.serviceKey(serviceID)
 
+	// Refresh service key TTL; a missing key means the registration already expired
+	refreshed, err := r.client.Expire(ctx, serviceKey, r.ttl).Result()
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to refresh service TTL")
+		return fmt.Errorf("failed to refresh service TTL: %w", err)
+	}
+	if !refreshed {
+		return fmt.Errorf("%w: %s", interfaces.ErrServiceNotRegistered, serviceID)
+	}
+
 	// Update heartbeat timestamp
-	if err := r.client.Set(ctx, heartbeatKey, time.Now().Unix(), 90*time.Second).Err(); err != nil {
+	if err := r.client.Set(ctx, heartbeatKey, time.Now().Unix(), r.ttl).Err(); err != nil {
 		r.logger.WithError(err).Error("Failed to update heartbeat")
 		return fmt.Errorf("failed to update heartbeat: %w", err)
 	}
 
-	// Refresh service key TTL
-	if err := r.client.Expire(ctx, serviceKey, 90*time.Second).Err(); err != nil {
-		r.logger.WithError(err).Error("Failed to refresh service TTL")
-		return fmt.Errorf("failed to refresh service TTL: %w", err)
-	}
-
 	return nil
 }
 
 func (r *RedisServiceDiscovery) Discover(ctx context.Context, serviceName string) ([]*interfaces.ServiceInfo, error) {
-	pattern := fmt.Sprintf("%s:service:*", r.namespace)
-
-	keys, err := r.client.Keys(ctx, pattern).Result()
+	all, err := r.loadServices(ctx)
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to discover services")
 		return nil, fmt.Errorf("failed to discover services: %w", err)
 	}
 
 	services := []*interfaces.ServiceInfo{}
-	for _, key := range keys {
-		data, err := r.client.Get(ctx, key).Result()
+	for _, info := range all {
+		if info.ServiceName == serviceName {
+			services = append(services, info)
+		}
+	}
+
+	return services, nil
+}
+
+func (r *RedisServiceDiscovery) Query(ctx context.Context, query *interfaces.ServiceQuery) ([]*interfaces.ServiceInfo, error) {
+	var constraint *semver.Constraint
+	if query.VersionConstraint != "" {
+		parsed, err := semver.ParseConstraint(query.VersionConstraint)
 		if err != nil {
-			r.logger.WithError(err).WithField("key", key).Warn("Failed to get service data")
-			continue
+			return nil, err
 		}
+		constraint = parsed
+	}
+
+	candidates, err := r.Discover(ctx, query.ServiceName)
+	if err != nil {
+		return nil, err
+	}
 
-		var info interfaces.ServiceInfo
-		if err := json.Unmarshal([]byte(data), &info); err != nil {
-			r.logger.WithError(err).WithField("key", key).Warn("Failed to unmarshal service info")
+	services := []*interfaces.ServiceInfo{}
+	for _, info := range candidates {
+		if query.HealthyOnly && info.EffectiveHealth() != interfaces.HealthPassing {
 			continue
 		}
-
-		if info.ServiceName == serviceName {
-			services = append(services, &info)
+		if !info.HasTags(query.Tags...) {
+			continue
+		}
+		if constraint != nil && !constraint.CheckString(info.Version) {
+			continue
 		}
+		services = append(services, info)
 	}
 
 	return services, nil
 }
 
-func (r *RedisServiceDiscovery) GetServiceInfo(ctx context.Context, serviceID string) (*interfaces.ServiceInfo, error) {
+func (r *RedisServiceDiscovery) UpdateHealth(ctx context.Context, serviceID string, status interfaces.HealthStatus, note string) error {
 	key := r.serviceKey(serviceID)
 
-	data, err := r.client.Get(ctx, key).Result()
+	info, err := r.loadServiceInfo(ctx, key)
+	if err == redis.Nil {
+		return fmt.Errorf("%w: %s", interfaces.ErrServiceNotRegistered, serviceID)
+	}
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to load service info")
+		return fmt.Errorf("failed to load service info: %w", err)
+	}
+
+	previous := info.EffectiveHealth()
+	info.Health = status
+	info.HealthNote = note
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to marshal service info")
+		return fmt.Errorf("failed to marshal service info: %w", err)
+	}
+
+	// Keep the remaining TTL so a health update never extends a dead registration
+	if err := r.client.SetArgs(ctx, key, data, redis.SetArgs{KeepTTL: true, Mode: "XX"}).Err(); err != nil {
+		if err == redis.Nil {
+			return fmt.Errorf("%w: %s", interfaces.ErrServiceNotRegistered, serviceID)
+		}
+		r.logger.WithError(err).Error("Failed to update service health")
+		return fmt.Errorf("failed to update service health: %w", err)
+	}
+
+	if previous != status {
+		r.publishEvent(ctx, &interfaces.ServiceEvent{
+			Type:        interfaces.ServiceHealthChange,
+			ServiceName: info.ServiceName,
+			ServiceID:   serviceID,
+			Service:     info,
+			Timestamp:   time.Now(),
+		})
+
+		r.logger.WithFields(logrus.Fields{
+			"service_id": serviceID,
+			"health":     status,
+			"note":       note,
+		}).Info("Service health changed")
+	}
+
+	return nil
+}
+
+func (r *RedisServiceDiscovery) GetServiceInfo(ctx context.Context, serviceID string) (*interfaces.ServiceInfo, error) {
+	info, err := r.loadServiceInfo(ctx, r.serviceKey(serviceID))
 	if err == redis.Nil {
 		return nil, fmt.Errorf("service not found: %s", serviceID)
 	}
@@ -134,42 +249,191 @@ func (r *RedisServiceDiscovery) GetServiceInfo(ctx context.Context, serviceID st
 		return nil, fmt.Errorf("failed to get service info: %w", err)
 	}
 
-	var info interfaces.ServiceInfo
-	if err := json.Unmarshal([]byte(data), &info); err != nil {
-		r.logger.WithError(err).Error("Failed to unmarshal service info")
-		return nil, fmt.Errorf("failed to unmarshal service info: %w", err)
+	return info, nil
+}
+
+func (r *RedisServiceDiscovery) ListServices(ctx context.Context) ([]*interfaces.ServiceInfo, error) {
+	services, err := r.loadServices(ctx)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to list services")
+		return nil, fmt.Errorf("failed to list services: %w", err)
 	}
 
-	return &info, nil
+	return services, nil
 }
 
-func (r *RedisServiceDiscovery) ListServices(ctx context.Context) ([]*interfaces.ServiceInfo, error) {
+// loadServices reads every registered service in the namespace, skipping unreadable entries
+func (r *RedisServiceDiscovery) loadServices(ctx context.Context) ([]*interfaces.ServiceInfo, error) {
 	pattern := fmt.Sprintf("%s:service:*", r.namespace)
 
 	keys, err := r.client.Keys(ctx, pattern).Result()
 	if err != nil {
-		r.logger.WithError(err).Error("Failed to list services")
-		return nil, fmt.Errorf("failed to list services: %w", err)
+		return nil, err
 	}
 
 	services := []*interfaces.ServiceInfo{}
 	for _, key := range keys {
-		data, err := r.client.Get(ctx, key).Result()
+		info, err := r.loadServiceInfo(ctx, key)
 		if err != nil {
-			r.logger.WithError(err).WithField("key", key).Warn("Failed to get service data")
+			r.logger.WithError(err).WithField("key", key).Warn("Failed to load service info")
 			continue
 		}
 
-		var info interfaces.ServiceInfo
-		if err := json.Unmarshal([]byte(data), &info); err != nil {
-			r.logger.WithError(err).WithField("key", key).Warn("Failed to unmarshal service info")
-			continue
+		services = append(services, info)
+	}
+
+	return services, nil
+}
+
+// loadServiceInfo reads a service entry and overlays the latest heartbeat timestamp
+func (r *RedisServiceDiscovery) loadServiceInfo(ctx context.Context, key string) (*interfaces.ServiceInfo, error) {
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var info interfaces.ServiceInfo
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal service info: %w", err)
+	}
+
+	heartbeat, err := r.client.Get(ctx, r.heartbeatKey(info.ServiceID)).Int64()
+	if err == nil {
+		info.LastHeartbeat = time.Unix(heartbeat, 0)
+	}
+
+	return &info, nil
+}
+
+// Watch streams registration changes for serviceName. Registrations and deregistrations
+// arrive on the namespace events channel; expirations are detected through Redis keyspace
+// notifications, which Watch enables on first use only when allowed to CONFIG SET.
+func (r *RedisServiceDiscovery) Watch(ctx context.Context, serviceName string) (<-chan *interfaces.ServiceEvent, error) {
+	r.enableExpiryNotifications(ctx)
+
+	pubsub := r.client.Subscribe(ctx, r.eventsChannel(), r.expiredChannel())
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		r.logger.WithError(err).Error("Failed to subscribe to service events")
+		return nil, fmt.Errorf("failed to subscribe to service events: %w", err)
+	}
+
+	// Seed known instances so expirations, which only carry the key, can be attributed
+	known := map[string]bool{}
+	current, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+	for _, info := range current {
+		known[info.ServiceID] = true
+	}
+
+	events := make(chan *interfaces.ServiceEvent, 64)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				event := r.parseEvent(msg)
+				if event == nil {
+					continue
+				}
+
+				switch event.Type {
+				case interfaces.ServiceRegistered, interfaces.ServiceHealthChange:
+					if event.ServiceName != serviceName {
+						continue
+					}
+					known[event.ServiceID] = true
+				case interfaces.ServiceDeregistered, interfaces.ServiceExpired:
+					if !known[event.ServiceID] && event.ServiceName != serviceName {
+						continue
+					}
+					delete(known, event.ServiceID)
+					event.ServiceName = serviceName
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
 		}
+	}()
+
+	return events, nil
+}
 
-		services = append(services, &info)
+func (r *RedisServiceDiscovery) parseEvent(msg *redis.Message) *interfaces.ServiceEvent {
+	if msg.Channel == r.eventsChannel() {
+		var event interfaces.ServiceEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			r.logger.WithError(err).Warn("Failed to unmarshal service event")
+			return nil
+		}
+		return &event
 	}
 
-	return services, nil
+	// Keyspace expiry: payload is the expired key name
+	prefix := fmt.Sprintf("%s:service:", r.namespace)
+	if !strings.HasPrefix(msg.Payload, prefix) {
+		return nil
+	}
+	return &interfaces.ServiceEvent{
+		Type:      interfaces.ServiceExpired,
+		ServiceID: strings.TrimPrefix(msg.Payload, prefix),
+		Timestamp: time.Now(),
+	}
+}
+
+func (r *RedisServiceDiscovery) publishEvent(ctx context.Context, event *interfaces.ServiceEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		r.logger.WithError(err).Warn("Failed to marshal service event")
+		return
+	}
+
+	// Watchers are best-effort; a failed publish must not fail the registration itself
+	if err := r.client.Publish(ctx, r.eventsChannel(), data).Err(); err != nil {
+		r.logger.WithError(err).WithField("service_id", event.ServiceID).Warn("Failed to publish service event")
+	}
+}
+
+// enableExpiryNotifications checks that expired-key events are on and, if allowed, turns
+// them on. Managed Redis often refuses CONFIG; there expirations must be enabled
+// server-side (notify-keyspace-events "Ex") or watchers never see them.
+func (r *RedisServiceDiscovery) enableExpiryNotifications(ctx context.Context) {
+	r.notificationsOnce.Do(func() {
+		current, err := r.client.ConfigGet(ctx, "notify-keyspace-events").Result()
+		if err != nil {
+			r.logger.WithError(err).Warn("Could not check keyspace notifications, expirations may not be watched")
+			return
+		}
+
+		flags := current["notify-keyspace-events"]
+		if strings.Contains(flags, "E") && (strings.Contains(flags, "x") || strings.Contains(flags, "A")) {
+			return
+		}
+		if !r.configureKeyspaces {
+			r.logger.WithField("notify_keyspace_events", flags).Warn("Keyspace expiry notifications are off, expirations will not be watched")
+			return
+		}
+
+		if err := r.client.ConfigSet(ctx, "notify-keyspace-events", flags+"Ex").Err(); err != nil {
+			r.logger.WithError(err).Warn("Could not enable keyspace notifications, expirations will not be watched")
+		}
+	})
 }
 
 func (r *RedisServiceDiscovery) HealthCheck(ctx context.Context) error {
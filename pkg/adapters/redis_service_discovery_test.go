@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextServiceEvent waits for the next watch event, failing the test if none arrives
+func nextServiceEvent(t *testing.T, events <-chan *interfaces.ServiceEvent) *interfaces.ServiceEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "Watch channel closed unexpectedly")
+		return event
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "Timed out waiting for a service event")
+		return nil
+	}
+}
+
+func TestRedisServiceDiscovery_WatchRegisterAndDeregister(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	discovery := NewRedisServiceDiscovery(client, "market_data", time.Minute, false, newQuietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := discovery.Watch(ctx, "market-data-adapter")
+	require.NoError(t, err)
+
+	require.NoError(t, discovery.Register(ctx, &interfaces.ServiceInfo{ServiceName: "other-service", ServiceID: "other-1"}))
+	require.NoError(t, discovery.Register(ctx, &interfaces.ServiceInfo{ServiceName: "market-data-adapter", ServiceID: "mda-1"}))
+
+	event := nextServiceEvent(t, events)
+	assert.Equal(t, interfaces.ServiceRegistered, event.Type, "Other services' registrations should be filtered out")
+	assert.Equal(t, "mda-1", event.ServiceID)
+	require.NotNil(t, event.Service)
+	assert.Equal(t, "market-data-adapter", event.Service.ServiceName)
+
+	require.NoError(t, discovery.Deregister(ctx, "other-1"))
+	require.NoError(t, discovery.Deregister(ctx, "mda-1"))
+
+	event = nextServiceEvent(t, events)
+	assert.Equal(t, interfaces.ServiceDeregistered, event.Type)
+	assert.Equal(t, "mda-1", event.ServiceID)
+	assert.Equal(t, "market-data-adapter", event.ServiceName)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestRedisServiceDiscovery_WatchExpiry(t *testing.T) {
+	server, client := newMiniredisClient(t)
+	discovery := NewRedisServiceDiscovery(client, "market_data", time.Minute, false, newQuietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Registered before Watch, so the expiry is attributed through the seeded instances
+	require.NoError(t, discovery.Register(ctx, &interfaces.ServiceInfo{ServiceName: "market-data-adapter", ServiceID: "mda-1"}))
+
+	events, err := discovery.Watch(ctx, "market-data-adapter")
+	require.NoError(t, err)
+
+	// miniredis has no keyspace notifications, so publish what Redis would on expiry
+	server.Publish("__keyevent@0__:expired", "market_data:heartbeat:mda-1")
+	server.Publish("__keyevent@0__:expired", "market_data:service:unknown-1")
+	server.Publish("__keyevent@0__:expired", "market_data:service:mda-1")
+
+	event := nextServiceEvent(t, events)
+	assert.Equal(t, interfaces.ServiceExpired, event.Type, "Heartbeat keys and unknown instances should be ignored")
+	assert.Equal(t, "mda-1", event.ServiceID)
+	assert.Equal(t, "market-data-adapter", event.ServiceName)
+}
+
+func TestRedisServiceDiscovery_WatchWithoutConfigAccess(t *testing.T) {
+	_, client := newMiniredisClient(t)
+	logger, hook := test.NewNullLogger()
+	discovery := NewRedisServiceDiscovery(client, "market_data", time.Minute, true, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// miniredis rejects CONFIG like managed Redis does; Watch must still stream events
+	events, err := discovery.Watch(ctx, "market-data-adapter")
+	require.NoError(t, err)
+	_, err = discovery.Watch(ctx, "market-data-adapter")
+	require.NoError(t, err)
+
+	require.NoError(t, discovery.Register(ctx, &interfaces.ServiceInfo{ServiceName: "market-data-adapter", ServiceID: "mda-1"}))
+	event := nextServiceEvent(t, events)
+	assert.Equal(t, interfaces.ServiceRegistered, event.Type)
+
+	warnings := 0
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings++
+			assert.Contains(t, entry.Message, "keyspace notifications")
+		}
+	}
+	assert.Equal(t, 1, warnings, "The CONFIG failure should be reported once, not per watcher")
+}
@@ -0,0 +1,567 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/database"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceRepositories are one backend's implementations of the storage interfaces
+type conformanceRepositories struct {
+	priceFeeds interfaces.PriceFeedRepository
+	candles    interfaces.CandleRepository
+	snapshots  interfaces.MarketSnapshotRepository
+	symbols    interfaces.SymbolRepository
+}
+
+func TestSQLiteRepositories_Conformance(t *testing.T) {
+	ctx := context.Background()
+	logger := newQuietLogger()
+
+	db, err := database.NewSQLiteDB(&config.Config{DatabaseURL: "sqlite::memory:"}, logger)
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	t.Cleanup(func() { db.Disconnect(ctx) })
+	require.NoError(t, db.Migrate(ctx))
+	require.NoError(t, db.Migrate(ctx), "Migrations are applied once")
+
+	runRepositoryConformance(t, conformanceRepositories{
+		priceFeeds: NewSQLitePriceFeedRepository(db.DB, logger),
+		candles:    NewSQLiteCandleRepository(db.DB, logger),
+		snapshots:  NewSQLiteMarketSnapshotRepository(db.DB, logger),
+		symbols:    NewSQLiteSymbolRepository(db.DB, logger),
+	})
+}
+
+func TestPostgresRepositories_Conformance(t *testing.T) {
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL not set")
+	}
+	ctx := context.Background()
+	logger := newQuietLogger()
+
+	schema := "conformance_" + uuid.New().String()[:8]
+	db, err := database.NewPostgresDB(&config.Config{PostgresURL: url, SchemaName: schema}, logger)
+	require.NoError(t, err)
+	require.NoError(t, db.Connect(ctx))
+	t.Cleanup(func() {
+		db.DB.ExecContext(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema))
+		db.Disconnect(ctx)
+	})
+	require.NoError(t, db.Migrate(ctx))
+
+	runRepositoryConformance(t, conformanceRepositories{
+		priceFeeds: NewPostgresPriceFeedRepository(db.DB, schema, false, logger),
+		candles:    NewPostgresCandleRepository(db.DB, schema, false, logger),
+		snapshots:  NewPostgresMarketSnapshotRepository(db.DB, schema, false, logger),
+		symbols:    NewPostgresSymbolRepository(db.DB, schema, false, logger),
+	})
+}
+
+// runRepositoryConformance checks the behaviour every storage backend must share. Each
+// case uses its own symbols and sources so cases do not see each other's rows.
+func runRepositoryConformance(t *testing.T, repos conformanceRepositories) {
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 123456000, time.UTC)
+
+	t.Run("PriceFeedDecimalsAreLossless", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		bid := decimal.RequireFromString("65432.12345677")
+		volume := decimal.RequireFromString("0.00000001")
+		feed := &models.PriceFeed{
+			Symbol:    symbol,
+			Price:     decimal.RequireFromString("65432.12345678"),
+			Bid:       &bid,
+			Volume24h: &volume,
+			Source:    "conformance",
+			Timestamp: base,
+			Metadata:  []byte(`{"venue": "test", "depth": 3}`),
+		}
+		result, err := repos.priceFeeds.Create(ctx, feed)
+		require.NoError(t, err)
+		assert.False(t, result.Duplicate)
+
+		stored, err := repos.priceFeeds.GetByID(ctx, result.FeedID)
+		require.NoError(t, err)
+		assert.Equal(t, "65432.12345678", stored.Price.String())
+		assert.Equal(t, "65432.12345677", stored.Bid.String())
+		assert.Nil(t, stored.Ask)
+		assert.Equal(t, "0.00000001", stored.Volume24h.String())
+		assert.WithinDuration(t, base, stored.Timestamp, 0)
+		assert.JSONEq(t, `{"venue": "test", "depth": 3}`, string(stored.Metadata))
+		assert.False(t, stored.PersistedAt.IsZero())
+
+		latest, err := repos.priceFeeds.GetLatestBySymbol(ctx, symbol)
+		require.NoError(t, err)
+		assert.Equal(t, result.FeedID, latest.FeedID)
+	})
+
+	t.Run("PriceFeedsSortNumerically", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		for i, price := range []string{"100", "9.5", "10.25"} {
+			_, err := repos.priceFeeds.Create(ctx, &models.PriceFeed{
+				Symbol:    symbol,
+				Price:     decimal.RequireFromString(price),
+				Source:    "conformance",
+				Timestamp: base.Add(time.Duration(i) * time.Second),
+			})
+			require.NoError(t, err)
+		}
+
+		feeds, err := repos.priceFeeds.Query(ctx, &models.PriceFeedQuery{Symbol: &symbol, SortBy: "price", SortOrder: "asc"})
+		require.NoError(t, err)
+		require.Len(t, feeds, 3)
+		assert.Equal(t, []string{"9.5", "10.25", "100"}, feedPrices(feeds))
+
+		feeds, err = repos.priceFeeds.GetBySymbol(ctx, symbol, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"10.25", "9.5"}, feedPrices(feeds), "History is newest first")
+	})
+
+	t.Run("PriceFeedReplaysAreIgnored", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		key := symbol + ":seq:1"
+		feed := func(price string) *models.PriceFeed {
+			return &models.PriceFeed{
+				Symbol:         symbol,
+				Price:          decimal.RequireFromString(price),
+				Source:         "conformance",
+				Timestamp:      base,
+				IdempotencyKey: &key,
+			}
+		}
+
+		first, err := repos.priceFeeds.Create(ctx, feed("1.5"))
+		require.NoError(t, err)
+		replay, err := repos.priceFeeds.Create(ctx, feed("1.6"))
+		require.NoError(t, err)
+		assert.True(t, replay.Duplicate)
+		assert.Equal(t, first.FeedID, replay.FeedID)
+
+		otherKey := symbol + ":seq:2"
+		second := feed("1.7")
+		second.IdempotencyKey = &otherKey
+		results, err := repos.priceFeeds.CreateBatch(ctx, []*models.PriceFeed{feed("1.8"), second, feed("1.9")})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.True(t, results[0].Duplicate)
+		assert.False(t, results[1].Duplicate)
+		assert.True(t, results[2].Duplicate)
+		assert.Equal(t, first.FeedID, results[2].FeedID)
+
+		feeds, err := repos.priceFeeds.GetBySymbol(ctx, symbol, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1.5", "1.7"}, feedPrices(feeds))
+	})
+
+	t.Run("PriceFeedReplayAfterRetentionIsStored", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		key := symbol + ":seq:1"
+		old := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+		feed := &models.PriceFeed{Symbol: symbol, Price: decimal.NewFromInt(2), Source: "conformance", Timestamp: old, IdempotencyKey: &key}
+
+		_, err := repos.priceFeeds.Create(ctx, feed)
+		require.NoError(t, err)
+		deleted, err := repos.priceFeeds.DeleteOlderThan(ctx, old.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		feed.FeedID = ""
+		result, err := repos.priceFeeds.Create(ctx, feed)
+		require.NoError(t, err)
+		assert.False(t, result.Duplicate)
+	})
+
+	t.Run("PriceFeedLatestTimestampsAndLatency", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		source := "conformance-" + symbol
+		received := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+		for i, latency := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 200 * time.Millisecond} {
+			at := received.Add(time.Duration(i) * time.Minute)
+			_, err := repos.priceFeeds.Create(ctx, &models.PriceFeed{
+				Symbol:     symbol,
+				Price:      decimal.NewFromInt(3),
+				Source:     source,
+				Timestamp:  at.Add(-latency),
+				ReceivedAt: at,
+			})
+			require.NoError(t, err)
+		}
+
+		latest, err := repos.priceFeeds.GetLatestTimestamps(ctx, received.Add(-time.Hour))
+		require.NoError(t, err)
+		found := false
+		for _, entry := range latest {
+			if entry.Symbol == symbol {
+				found = true
+				assert.Equal(t, source, entry.Source)
+				assert.WithinDuration(t, received.Add(2*time.Minute-200*time.Millisecond), entry.Timestamp, 0)
+			}
+		}
+		assert.True(t, found)
+
+		stats, err := repos.priceFeeds.LatencyStats(ctx, received, received.Add(time.Hour))
+		require.NoError(t, err)
+		var stat *models.LatencyStats
+		for _, s := range stats {
+			if s.Source == source {
+				stat = s
+			}
+		}
+		require.NotNil(t, stat)
+		assert.Equal(t, int64(3), stat.Samples)
+		assert.InDelta(t, float64(200*time.Millisecond), float64(stat.FeedLatencyP50), float64(time.Microsecond))
+		assert.InDelta(t, float64(298*time.Millisecond), float64(stat.FeedLatencyP99), float64(time.Microsecond))
+		assert.Zero(t, stat.ClockSkew)
+	})
+
+	t.Run("MissingRowsAreNotFound", func(t *testing.T) {
+		missingID := uuid.New().String()
+
+		_, err := repos.priceFeeds.GetByID(ctx, missingID)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.priceFeeds.GetLatestBySymbol(ctx, conformanceSymbol())
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.candles.GetByID(ctx, missingID)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.candles.GetLatest(ctx, conformanceSymbol(), models.Interval1m)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.snapshots.GetByID(ctx, missingID)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.snapshots.GetLatestBySymbol(ctx, conformanceSymbol())
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.symbols.GetByID(ctx, missingID)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repos.symbols.GetBySymbol(ctx, conformanceSymbol())
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		assert.ErrorIs(t, repos.symbols.UpdateActiveStatus(ctx, missingID, false), interfaces.ErrNotFound)
+		assert.ErrorIs(t, repos.symbols.Delete(ctx, missingID), interfaces.ErrNotFound)
+	})
+
+	t.Run("CandleUpsertKeepsID", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		trades := 42
+		candle := &models.Candle{
+			Symbol:    symbol,
+			Interval:  models.Interval1m,
+			Open:      decimal.RequireFromString("100.12345678"),
+			High:      decimal.RequireFromString("101"),
+			Low:       decimal.RequireFromString("99.5"),
+			Close:     decimal.RequireFromString("100.5"),
+			Volume:    decimal.RequireFromString("12.00000001"),
+			StartTime: base,
+			EndTime:   base.Add(time.Minute),
+			NumTrades: &trades,
+		}
+		require.NoError(t, repos.candles.Upsert(ctx, candle))
+		firstID := candle.CandleID
+
+		revised := *candle
+		revised.CandleID = ""
+		revised.Close = decimal.RequireFromString("100.75")
+		require.NoError(t, repos.candles.Upsert(ctx, &revised))
+		assert.Equal(t, firstID, revised.CandleID, "An upsert of the same bucket keeps the stored ID")
+
+		stored, err := repos.candles.GetLatest(ctx, symbol, models.Interval1m)
+		require.NoError(t, err)
+		assert.Equal(t, firstID, stored.CandleID)
+		assert.Equal(t, "100.12345678", stored.Open.String())
+		assert.Equal(t, "100.75", stored.Close.String())
+		assert.Equal(t, "12.00000001", stored.Volume.String())
+		assert.WithinDuration(t, base, stored.StartTime, 0)
+		assert.WithinDuration(t, base.Add(time.Minute), stored.EndTime, 0)
+		require.NotNil(t, stored.NumTrades)
+		assert.Equal(t, 42, *stored.NumTrades)
+	})
+
+	t.Run("CandlesQueryAndExpirePerInterval", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		old := time.Date(2002, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i, close := range []string{"100", "9.5", "10.25"} {
+			start := old.Add(time.Duration(i) * time.Minute)
+			require.NoError(t, repos.candles.Upsert(ctx, &models.Candle{
+				Symbol: symbol, Interval: models.Interval1m,
+				Open: decimal.RequireFromString(close), High: decimal.RequireFromString(close),
+				Low: decimal.RequireFromString(close), Close: decimal.RequireFromString(close),
+				Volume: decimal.Zero, StartTime: start, EndTime: start.Add(time.Minute),
+			}))
+		}
+		require.NoError(t, repos.candles.Upsert(ctx, &models.Candle{
+			Symbol: symbol, Interval: models.Interval1h,
+			Open: decimal.NewFromInt(1), High: decimal.NewFromInt(1), Low: decimal.NewFromInt(1), Close: decimal.NewFromInt(1),
+			Volume: decimal.Zero, StartTime: old, EndTime: old.Add(time.Hour),
+		}))
+
+		minute := models.Interval1m
+		from, to := old.Add(time.Minute), old.Add(time.Hour)
+		candles, err := repos.candles.Query(ctx, &models.CandleQuery{Symbol: &symbol, Interval: &minute, StartTimeFrom: &from, StartTimeTo: &to})
+		require.NoError(t, err)
+		require.Len(t, candles, 2)
+		assert.Equal(t, "10.25", candles[0].Close.String(), "Candles are newest first by default")
+
+		candles, err = repos.candles.Query(ctx, &models.CandleQuery{Symbol: &symbol, Interval: &minute, SortBy: "close", SortOrder: "asc"})
+		require.NoError(t, err)
+		require.Len(t, candles, 3)
+		assert.Equal(t, "9.5", candles[0].Close.String())
+		assert.Equal(t, "100", candles[2].Close.String())
+
+		deleted, err := repos.candles.DeleteIntervalOlderThan(ctx, models.Interval1m, old.Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		hourly, err := repos.candles.GetBySymbolAndInterval(ctx, symbol, models.Interval1h, 10)
+		require.NoError(t, err)
+		assert.Len(t, hourly, 1, "Other intervals are kept")
+	})
+
+	t.Run("OldestTimestampsBeforeCutoff", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		old := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			at := old.Add(time.Duration(i) * time.Hour)
+			_, err := repos.priceFeeds.Create(ctx, &models.PriceFeed{Symbol: symbol, Price: decimal.NewFromInt(5), Source: "conformance", Timestamp: at.Add(time.Minute)})
+			require.NoError(t, err)
+			require.NoError(t, repos.candles.Upsert(ctx, &models.Candle{
+				Symbol: symbol, Interval: models.Interval1h,
+				Open: decimal.NewFromInt(5), High: decimal.NewFromInt(5), Low: decimal.NewFromInt(5), Close: decimal.NewFromInt(5),
+				Volume: decimal.Zero, StartTime: at, EndTime: at.Add(time.Hour),
+			}))
+		}
+
+		feeds, err := repos.priceFeeds.GetOldestTimestamps(ctx, old.Add(2*time.Hour))
+		require.NoError(t, err)
+		candles, err := repos.candles.GetOldestIntervalTimestamps(ctx, models.Interval1h, old.Add(2*time.Hour))
+		require.NoError(t, err)
+		for _, oldest := range [][]*models.LatestTimestamp{feeds, candles} {
+			var entry *models.LatestTimestamp
+			for _, e := range oldest {
+				if e.Symbol == symbol {
+					entry = e
+				}
+			}
+			require.NotNil(t, entry)
+			assert.WithinDuration(t, old, entry.Timestamp, time.Minute)
+		}
+
+		candles, err = repos.candles.GetOldestIntervalTimestamps(ctx, models.Interval1m, old.Add(2*time.Hour))
+		require.NoError(t, err)
+		for _, entry := range candles {
+			assert.NotEqual(t, symbol, entry.Symbol, "Other intervals are not reported")
+		}
+	})
+
+	t.Run("SnapshotsRoundTrip", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		spread := decimal.RequireFromString("0.00000002")
+		change := decimal.RequireFromString("-1.23456789")
+		for i, price := range []string{"100", "9.5", "10.25"} {
+			require.NoError(t, repos.snapshots.Create(ctx, &models.MarketSnapshot{
+				Symbol:                symbol,
+				LastPrice:             decimal.RequireFromString(price),
+				Spread:                &spread,
+				PriceChangePercent24h: &change,
+				Timestamp:             base.Add(time.Duration(i) * time.Second),
+				Metadata:              []byte(`{"window": "24h"}`),
+			}))
+		}
+
+		latest, err := repos.snapshots.GetLatestBySymbol(ctx, symbol)
+		require.NoError(t, err)
+		assert.Equal(t, "10.25", latest.LastPrice.String())
+		assert.Equal(t, "0.00000002", latest.Spread.String())
+		assert.Equal(t, "-1.23456789", latest.PriceChangePercent24h.String())
+		assert.Nil(t, latest.Bid)
+		assert.WithinDuration(t, base.Add(2*time.Second), latest.Timestamp, 0)
+		assert.JSONEq(t, `{"window": "24h"}`, string(latest.Metadata))
+
+		byID, err := repos.snapshots.GetByID(ctx, latest.SnapshotID)
+		require.NoError(t, err)
+		assert.Equal(t, latest.SnapshotID, byID.SnapshotID)
+
+		snapshots, err := repos.snapshots.Query(ctx, &models.MarketSnapshotQuery{Symbol: &symbol, SortBy: "last_price", SortOrder: "asc"})
+		require.NoError(t, err)
+		require.Len(t, snapshots, 3)
+		assert.Equal(t, "9.5", snapshots[0].LastPrice.String())
+		assert.Equal(t, "100", snapshots[2].LastPrice.String())
+
+		history, err := repos.snapshots.GetBySymbol(ctx, symbol, 2)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "10.25", history[0].LastPrice.String())
+
+		timestamps, err := repos.snapshots.GetLatestTimestamps(ctx, base)
+		require.NoError(t, err)
+		found := false
+		for _, entry := range timestamps {
+			if entry.Symbol == symbol {
+				found = true
+				assert.Empty(t, entry.Source)
+				assert.WithinDuration(t, base.Add(2*time.Second), entry.Timestamp, 0)
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("SnapshotsExpire", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		old := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, repos.snapshots.Create(ctx, &models.MarketSnapshot{Symbol: symbol, LastPrice: decimal.NewFromInt(5), Timestamp: old}))
+
+		deleted, err := repos.snapshots.DeleteOlderThan(ctx, old.Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		_, err = repos.snapshots.GetLatestBySymbol(ctx, symbol)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+	})
+
+	t.Run("SnapshotQueries", func(t *testing.T) {
+		symbol := conformanceSymbol()
+		for i := 0; i < 4; i++ {
+			require.NoError(t, repos.snapshots.Create(ctx, &models.MarketSnapshot{
+				Symbol:    symbol,
+				LastPrice: decimal.NewFromInt(int64(10 + i)),
+				Timestamp: base.Add(time.Duration(i) * time.Minute),
+			}))
+		}
+
+		from, to := base.Add(time.Minute), base.Add(3*time.Minute)
+		window, err := repos.snapshots.Query(ctx, &models.MarketSnapshotQuery{Symbol: &symbol, TimestampFrom: &from, TimestampTo: &to})
+		require.NoError(t, err)
+		require.Len(t, window, 2, "The time range includes its start and excludes its end")
+		assert.Equal(t, "12", window[0].LastPrice.String(), "Snapshots are newest first by default")
+		assert.Equal(t, "11", window[1].LastPrice.String())
+
+		page, err := repos.snapshots.Query(ctx, &models.MarketSnapshotQuery{Symbol: &symbol, SortBy: "timestamp", SortOrder: "asc", Limit: 2, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, "11", page[0].LastPrice.String())
+		assert.Equal(t, "12", page[1].LastPrice.String())
+
+		other := conformanceSymbol()
+		none, err := repos.snapshots.GetBySymbol(ctx, other, 10)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("SymbolLifecycle", func(t *testing.T) {
+		name := conformanceSymbol()
+		displayName := "Conformance " + name
+		tick := decimal.RequireFromString("0.00000001")
+		symbol := &models.Symbol{
+			Symbol:           name,
+			BaseCurrency:     "CNF",
+			QuoteCurrency:    "USD",
+			DisplayName:      &displayName,
+			IsActive:         true,
+			MinPriceMovement: &tick,
+			Metadata:         []byte(`{"tier": 1}`),
+		}
+		require.NoError(t, repos.symbols.Create(ctx, symbol))
+		require.NoError(t, repos.symbols.Create(ctx, &models.Symbol{Symbol: name + "-B", BaseCurrency: "CNF", QuoteCurrency: "EUR"}))
+
+		stored, err := repos.symbols.GetBySymbol(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, symbol.SymbolID, stored.SymbolID)
+		assert.True(t, stored.IsActive)
+		assert.Equal(t, displayName, *stored.DisplayName)
+		assert.Equal(t, "0.00000001", stored.MinPriceMovement.String())
+		assert.Nil(t, stored.MaxOrderSize)
+		assert.JSONEq(t, `{"tier": 1}`, string(stored.Metadata))
+		assert.WithinDuration(t, stored.UpdatedAt, symbol.UpdatedAt, time.Millisecond, "Create should report the stored write time")
+		assert.WithinDuration(t, stored.CreatedAt, symbol.CreatedAt, time.Millisecond)
+
+		base := "CNF"
+		symbols, err := repos.symbols.Query(ctx, &models.SymbolQuery{BaseCurrency: &base})
+		require.NoError(t, err)
+		require.Len(t, symbols, 2)
+		assert.Equal(t, name, symbols[0].Symbol, "Symbols are alphabetical by default")
+
+		stored.QuoteCurrency = "USDT"
+		maxSize := decimal.RequireFromString("1000.5")
+		stored.MaxOrderSize = &maxSize
+		require.NoError(t, repos.symbols.Update(ctx, stored))
+		assert.Equal(t, "USDT", stored.QuoteCurrency)
+		assert.False(t, stored.UpdatedAt.Before(stored.CreatedAt))
+
+		require.NoError(t, repos.symbols.UpdateActiveStatus(ctx, stored.SymbolID, false))
+		updated, err := repos.symbols.GetByID(ctx, stored.SymbolID)
+		require.NoError(t, err)
+		assert.False(t, updated.IsActive)
+		assert.Equal(t, "1000.5", updated.MaxOrderSize.String())
+
+		active, err := repos.symbols.GetActive(ctx)
+		require.NoError(t, err)
+		for _, s := range active {
+			assert.NotEqual(t, stored.SymbolID, s.SymbolID)
+		}
+
+		require.NoError(t, repos.symbols.Delete(ctx, stored.SymbolID))
+		_, err = repos.symbols.GetByID(ctx, stored.SymbolID)
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+	})
+
+	t.Run("SymbolQueries", func(t *testing.T) {
+		names := []string{conformanceSymbol(), conformanceSymbol(), conformanceSymbol()}
+		sort.Strings(names)
+		for i, name := range names {
+			require.NoError(t, repos.symbols.Create(ctx, &models.Symbol{
+				Symbol:        name,
+				BaseCurrency:  "CNQ",
+				QuoteCurrency: "USD",
+				IsActive:      i != 1,
+			}))
+		}
+
+		baseCurrency, inactive := "CNQ", false
+		found, err := repos.symbols.Query(ctx, &models.SymbolQuery{BaseCurrency: &baseCurrency, IsActive: &inactive})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, names[1], found[0].Symbol)
+
+		page, err := repos.symbols.Query(ctx, &models.SymbolQuery{BaseCurrency: &baseCurrency, SortBy: "symbol", SortOrder: "desc", Limit: 2, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, names[1], page[0].Symbol)
+		assert.Equal(t, names[0], page[1].Symbol)
+
+		active, err := repos.symbols.GetActive(ctx)
+		require.NoError(t, err)
+		activeNames := make(map[string]bool)
+		for _, symbol := range active {
+			activeNames[symbol.Symbol] = true
+		}
+		assert.True(t, activeNames[names[0]])
+		assert.False(t, activeNames[names[1]])
+		assert.True(t, activeNames[names[2]])
+
+		_, err = repos.symbols.GetBySymbol(ctx, conformanceSymbol())
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+	})
+}
+
+// conformanceSymbol returns a symbol no other case uses
+func conformanceSymbol() string {
+	return "CNF-" + strings.ToUpper(uuid.New().String()[:8])
+}
+
+func feedPrices(feeds []*models.PriceFeed) []string {
+	prices := make([]string, len(feeds))
+	for i, feed := range feeds {
+		prices[i] = feed.Price.String()
+	}
+	return prices
+}
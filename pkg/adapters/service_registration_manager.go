@@ -0,0 +1,141 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// ServiceRegistrationManager keeps a service registered for the lifetime of the process:
+// it registers on Start, heartbeats every interval, re-registers if the entry expired
+// and deregisters on Stop.
+type ServiceRegistrationManager struct {
+	discovery interfaces.ServiceDiscoveryRepository
+	info      *interfaces.ServiceInfo
+	interval  time.Duration
+	logger    *logrus.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewServiceRegistrationManager rejects a heartbeat interval that is not shorter than the
+// registration TTL, since the entry would expire between heartbeats
+func NewServiceRegistrationManager(discovery interfaces.ServiceDiscoveryRepository, info *interfaces.ServiceInfo, interval, ttl time.Duration, logger *logrus.Logger) (*ServiceRegistrationManager, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must be positive, got %s", interval)
+	}
+	if interval >= ttl {
+		return nil, fmt.Errorf("heartbeat interval %s must be shorter than the service TTL %s", interval, ttl)
+	}
+
+	return &ServiceRegistrationManager{
+		discovery: discovery,
+		info:      info,
+		interval:  interval,
+		logger:    logger,
+	}, nil
+}
+
+func (m *ServiceRegistrationManager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return fmt.Errorf("service registration already started")
+	}
+
+	if err := m.discovery.Register(ctx, m.info); err != nil {
+		return fmt.Errorf("failed to register service: %w", err)
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.heartbeatLoop(loopCtx, m.done)
+
+	m.logger.WithFields(logrus.Fields{
+		"service_id":         m.info.ServiceID,
+		"heartbeat_interval": m.interval,
+	}).Info("Service registration started")
+	return nil
+}
+
+func (m *ServiceRegistrationManager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	<-done
+
+	if err := m.discovery.Deregister(ctx, m.info.ServiceID); err != nil {
+		return fmt.Errorf("failed to deregister service: %w", err)
+	}
+
+	m.logger.WithField("service_id", m.info.ServiceID).Info("Service registration stopped")
+	return nil
+}
+
+// SetHealth reports this service's health without deregistering it; the status is kept
+// across re-registrations until changed again
+func (m *ServiceRegistrationManager) SetHealth(ctx context.Context, status interfaces.HealthStatus, note string) error {
+	m.mu.Lock()
+	m.info.Health = status
+	m.info.HealthNote = note
+	started := m.cancel != nil
+	m.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+	return m.discovery.UpdateHealth(ctx, m.info.ServiceID, status, note)
+}
+
+func (m *ServiceRegistrationManager) heartbeatLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.heartbeat(ctx)
+		}
+	}
+}
+
+func (m *ServiceRegistrationManager) heartbeat(ctx context.Context) {
+	err := m.discovery.Heartbeat(ctx, m.info.ServiceID)
+	if err == nil {
+		return
+	}
+
+	if !errors.Is(err, interfaces.ErrServiceNotRegistered) {
+		m.logger.WithError(err).WithField("service_id", m.info.ServiceID).Warn("Heartbeat failed")
+		return
+	}
+
+	m.logger.WithField("service_id", m.info.ServiceID).Warn("Service registration expired, re-registering")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.discovery.Register(ctx, m.info); err != nil {
+		m.logger.WithError(err).WithField("service_id", m.info.ServiceID).Error("Failed to re-register service")
+	}
+}
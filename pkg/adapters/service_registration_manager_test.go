@@ -0,0 +1,222 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServiceDiscovery records calls and lets tests simulate expired registrations
+type fakeServiceDiscovery struct {
+	mu            sync.Mutex
+	services      map[string]*interfaces.ServiceInfo
+	registrations int
+	heartbeats    int
+}
+
+func newFakeServiceDiscovery() *fakeServiceDiscovery {
+	return &fakeServiceDiscovery{services: make(map[string]*interfaces.ServiceInfo)}
+}
+
+func (f *fakeServiceDiscovery) Register(ctx context.Context, info *interfaces.ServiceInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *info
+	f.services[info.ServiceID] = &copied
+	f.registrations++
+	return nil
+}
+
+func (f *fakeServiceDiscovery) Deregister(ctx context.Context, serviceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.services, serviceID)
+	return nil
+}
+
+func (f *fakeServiceDiscovery) Heartbeat(ctx context.Context, serviceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.heartbeats++
+	if _, ok := f.services[serviceID]; !ok {
+		return fmt.Errorf("%w: %s", interfaces.ErrServiceNotRegistered, serviceID)
+	}
+	return nil
+}
+
+func (f *fakeServiceDiscovery) Discover(ctx context.Context, serviceName string) ([]*interfaces.ServiceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	services := []*interfaces.ServiceInfo{}
+	for _, info := range f.services {
+		if info.ServiceName == serviceName {
+			copied := *info
+			services = append(services, &copied)
+		}
+	}
+	return services, nil
+}
+
+func (f *fakeServiceDiscovery) Query(ctx context.Context, query *interfaces.ServiceQuery) ([]*interfaces.ServiceInfo, error) {
+	return f.Discover(ctx, query.ServiceName)
+}
+
+func (f *fakeServiceDiscovery) UpdateHealth(ctx context.Context, serviceID string, status interfaces.HealthStatus, note string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, ok := f.services[serviceID]
+	if !ok {
+		return fmt.Errorf("%w: %s", interfaces.ErrServiceNotRegistered, serviceID)
+	}
+	info.Health = status
+	info.HealthNote = note
+	return nil
+}
+
+func (f *fakeServiceDiscovery) GetServiceInfo(ctx context.Context, serviceID string) (*interfaces.ServiceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, ok := f.services[serviceID]
+	if !ok {
+		return nil, fmt.Errorf("service not found: %s", serviceID)
+	}
+	copied := *info
+	return &copied, nil
+}
+
+func (f *fakeServiceDiscovery) ListServices(ctx context.Context) ([]*interfaces.ServiceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	services := []*interfaces.ServiceInfo{}
+	for _, info := range f.services {
+		copied := *info
+		services = append(services, &copied)
+	}
+	return services, nil
+}
+
+func (f *fakeServiceDiscovery) Watch(ctx context.Context, serviceName string) (<-chan *interfaces.ServiceEvent, error) {
+	events := make(chan *interfaces.ServiceEvent)
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events, nil
+}
+
+func (f *fakeServiceDiscovery) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakeServiceDiscovery) expire(serviceID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.services, serviceID)
+}
+
+func (f *fakeServiceDiscovery) counts() (registrations, heartbeats int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.registrations, f.heartbeats
+}
+
+func newTestRegistrationManager(t *testing.T, discovery interfaces.ServiceDiscoveryRepository) *ServiceRegistrationManager {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	info := &interfaces.ServiceInfo{
+		ServiceName: "market-data-simulator",
+		ServiceID:   "market-data-simulator-abc12345",
+	}
+	manager, err := NewServiceRegistrationManager(discovery, info, 10*time.Millisecond, time.Second, logger)
+	require.NoError(t, err)
+	return manager
+}
+
+func TestServiceRegistrationManager_RegistersAndHeartbeats(t *testing.T) {
+	discovery := newFakeServiceDiscovery()
+	manager := newTestRegistrationManager(t, discovery)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	_, err := discovery.GetServiceInfo(ctx, "market-data-simulator-abc12345")
+	require.NoError(t, err, "Service should be registered on start")
+
+	assert.Eventually(t, func() bool {
+		_, heartbeats := discovery.counts()
+		return heartbeats >= 2
+	}, time.Second, 5*time.Millisecond, "Heartbeats should be sent on the configured interval")
+}
+
+func TestServiceRegistrationManager_ReRegistersAfterExpiry(t *testing.T) {
+	discovery := newFakeServiceDiscovery()
+	manager := newTestRegistrationManager(t, discovery)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	discovery.expire("market-data-simulator-abc12345")
+
+	assert.Eventually(t, func() bool {
+		registrations, _ := discovery.counts()
+		_, err := discovery.GetServiceInfo(ctx, "market-data-simulator-abc12345")
+		return registrations == 2 && err == nil
+	}, time.Second, 5*time.Millisecond, "Expired registration should be re-created")
+}
+
+func TestServiceRegistrationManager_DeregistersOnStop(t *testing.T) {
+	discovery := newFakeServiceDiscovery()
+	manager := newTestRegistrationManager(t, discovery)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Start(ctx))
+	require.NoError(t, manager.Stop(ctx))
+
+	_, err := discovery.GetServiceInfo(ctx, "market-data-simulator-abc12345")
+	assert.Error(t, err, "Service should be deregistered on stop")
+
+	assert.NoError(t, manager.Stop(ctx), "Stopping twice should be a no-op")
+}
+
+func TestServiceRegistrationManager_HealthSurvivesReRegistration(t *testing.T) {
+	discovery := newFakeServiceDiscovery()
+	manager := newTestRegistrationManager(t, discovery)
+	ctx := context.Background()
+
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop(ctx)
+
+	require.NoError(t, manager.SetHealth(ctx, interfaces.HealthWarning, "vendor feed stale"))
+
+	discovery.expire("market-data-simulator-abc12345")
+
+	assert.Eventually(t, func() bool {
+		info, err := discovery.GetServiceInfo(ctx, "market-data-simulator-abc12345")
+		return err == nil && info.Health == interfaces.HealthWarning && info.HealthNote == "vendor feed stale"
+	}, time.Second, 5*time.Millisecond, "Re-registration should keep the reported health")
+}
+
+func TestNewServiceRegistrationManager_RejectsHeartbeatNotShorterThanTTL(t *testing.T) {
+	info := &interfaces.ServiceInfo{ServiceName: "market-data-simulator", ServiceID: "market-data-simulator-abc12345"}
+
+	_, err := NewServiceRegistrationManager(newFakeServiceDiscovery(), info, 90*time.Second, 90*time.Second, newQuietLogger())
+	assert.Error(t, err, "A registration would expire just as the heartbeat refreshes it")
+
+	_, err = NewServiceRegistrationManager(newFakeServiceDiscovery(), info, 0, 90*time.Second, newQuietLogger())
+	assert.Error(t, err)
+
+	_, err = NewServiceRegistrationManager(newFakeServiceDiscovery(), info, 30*time.Second, 90*time.Second, newQuietLogger())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,82 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/snapshot"
+	"github.com/sirupsen/logrus"
+)
+
+// SnapshottingPriceFeedRepository builds a market snapshot from every newly stored price
+// feed. A batch yields one snapshot per symbol, from its latest feed. Build failures are
+// logged rather than returned: the write already succeeded. In a transaction the build
+// waits for the commit, since the builder reads and writes outside it.
+type SnapshottingPriceFeedRepository struct {
+	interfaces.PriceFeedRepository
+	builder     *snapshot.Builder
+	afterCommit *afterCommit
+	logger      *logrus.Logger
+}
+
+func NewSnapshottingPriceFeedRepository(repo interfaces.PriceFeedRepository, builder *snapshot.Builder, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return newSnapshottingPriceFeedRepository(repo, builder, nil, logger)
+}
+
+func newSnapshottingPriceFeedRepository(repo interfaces.PriceFeedRepository, builder *snapshot.Builder, hooks *afterCommit, logger *logrus.Logger) *SnapshottingPriceFeedRepository {
+	return &SnapshottingPriceFeedRepository{
+		PriceFeedRepository: repo,
+		builder:             builder,
+		afterCommit:         hooks,
+		logger:              logger,
+	}
+}
+
+func (r *SnapshottingPriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	result, err := r.PriceFeedRepository.Create(ctx, feed)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.Duplicate {
+		r.build(ctx, feed)
+	}
+	return result, nil
+}
+
+func (r *SnapshottingPriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results, err := r.PriceFeedRepository.CreateBatch(ctx, feeds)
+	if err != nil {
+		return results, err
+	}
+
+	latest := make(map[string]*models.PriceFeed)
+	var symbols []string
+	for i, result := range results {
+		if result.Duplicate || result.Rejected {
+			continue
+		}
+		feed := feeds[i]
+		current, ok := latest[feed.Symbol]
+		if !ok {
+			symbols = append(symbols, feed.Symbol)
+		}
+		if !ok || !feed.Timestamp.Before(current.Timestamp) {
+			latest[feed.Symbol] = feed
+		}
+	}
+
+	for _, symbol := range symbols {
+		r.build(ctx, latest[symbol])
+	}
+	return results, nil
+}
+
+func (r *SnapshottingPriceFeedRepository) build(ctx context.Context, feed *models.PriceFeed) {
+	r.afterCommit.do(ctx, func(ctx context.Context) {
+		if _, err := r.builder.BuildFromFeed(ctx, feed); err != nil {
+			r.logger.WithError(err).WithField("symbol", feed.Symbol).Warn("Failed to build market snapshot")
+		}
+	})
+}
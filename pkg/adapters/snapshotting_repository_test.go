@@ -0,0 +1,60 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/snapshot"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type emptyCandleRepository struct {
+	interfaces.CandleRepository
+}
+
+func (r *emptyCandleRepository) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	return nil, nil
+}
+
+type recordingMarketSnapshotRepository struct {
+	interfaces.MarketSnapshotRepository
+	created []*models.MarketSnapshot
+}
+
+func (r *recordingMarketSnapshotRepository) Create(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	r.created = append(r.created, snapshot)
+	return nil
+}
+
+func TestSnapshottingPriceFeedRepository_BuildsLatestPerSymbol(t *testing.T) {
+	inner := &stubPriceFeedRepository{}
+	snapshots := &recordingMarketSnapshotRepository{}
+	builder := snapshot.NewBuilder(inner, &emptyCandleRepository{}, snapshots, snapshot.Options{}, newQuietLogger())
+	repo := NewSnapshottingPriceFeedRepository(inner, builder, newQuietLogger())
+
+	now := time.Now()
+	feed := func(id, symbol, price string, at time.Time) *models.PriceFeed {
+		return &models.PriceFeed{FeedID: id, Symbol: symbol, Price: decimal.RequireFromString(price), Timestamp: at}
+	}
+
+	_, err := repo.CreateBatch(context.Background(), []*models.PriceFeed{
+		feed("1", "BTC-USD", "100", now),
+		feed("2", "ETH-USD", "10", now),
+		feed("3", "BTC-USD", "101", now.Add(time.Second)),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, snapshots.created, 2)
+	assert.Equal(t, "BTC-USD", snapshots.created[0].Symbol)
+	assert.True(t, decimal.NewFromInt(101).Equal(snapshots.created[0].LastPrice))
+	assert.Equal(t, "ETH-USD", snapshots.created[1].Symbol)
+
+	_, err = repo.Create(context.Background(), feed("4", "BTC-USD", "102", now.Add(2*time.Second)))
+	require.NoError(t, err)
+	assert.Len(t, snapshots.created, 3)
+}
@@ -0,0 +1,209 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SQLiteCandleRepository stores candles in SQLite with the semantics of
+// PostgresCandleRepository, minus the outbox
+type SQLiteCandleRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewSQLiteCandleRepository(db *sql.DB, logger *logrus.Logger) interfaces.CandleRepository {
+	return &SQLiteCandleRepository{
+		db:     asDBTX(db),
+		table:  sqliteTable("candles"),
+		logger: logger,
+	}
+}
+
+func (r *SQLiteCandleRepository) Upsert(ctx context.Context, candle *models.Candle) error {
+	if r.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+	if candle.CandleID == "" {
+		candle.CandleID = uuid.New().String()
+	}
+
+	query := `INSERT INTO ` + r.table + ` (candle_id, symbol, interval, open, high, low, close, volume, start_time, end_time, num_trades, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (symbol, interval, start_time) DO UPDATE SET
+			open = excluded.open,
+			high = excluded.high,
+			low = excluded.low,
+			close = excluded.close,
+			volume = excluded.volume,
+			end_time = excluded.end_time,
+			num_trades = excluded.num_trades,
+			metadata = excluded.metadata
+		RETURNING candle_id`
+
+	if err := r.db.QueryRowContext(ctx, query,
+		candle.CandleID, candle.Symbol, candle.Interval, candle.Open, candle.High, candle.Low,
+		candle.Close, candle.Volume, sqliteTimestamp(candle.StartTime), sqliteTimestamp(candle.EndTime), candle.NumTrades,
+		nullableJSON(candle.Metadata),
+	).Scan(&candle.CandleID); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"symbol":   candle.Symbol,
+			"interval": candle.Interval,
+		}).Error("Failed to upsert candle")
+		return fmt.Errorf("failed to upsert candle: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteCandleRepository) GetByID(ctx context.Context, candleID string) (*models.Candle, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	candle, err := scanSQLiteCandle(r.db.QueryRowContext(ctx,
+		`SELECT `+candleColumns+` FROM `+r.table+` WHERE candle_id = $1`, candleID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candle %s: %w", candleID, err)
+	}
+	return candle, nil
+}
+
+func (r *SQLiteCandleRepository) GetBySymbolAndInterval(ctx context.Context, symbol string, interval models.CandleInterval, limit int) ([]*models.Candle, error) {
+	return r.Query(ctx, &models.CandleQuery{Symbol: &symbol, Interval: &interval, Limit: limit})
+}
+
+func (r *SQLiteCandleRepository) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.Interval != nil {
+		where.add("interval =", *query.Interval)
+	}
+	if query.StartTimeFrom != nil {
+		where.add("start_time >=", sqliteTimestamp(*query.StartTimeFrom))
+	}
+	if query.StartTimeTo != nil {
+		where.add("start_time <", sqliteTimestamp(*query.StartTimeTo))
+	}
+
+	statement := `SELECT ` + candleColumns + ` FROM ` + r.table + where.String() +
+		sqliteOrderClause(query.SortBy, query.SortOrder, candleSortColumns, "start_time", "close", "volume") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query candles")
+		return nil, fmt.Errorf("failed to query candles: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []*models.Candle
+	for rows.Next() {
+		candle, err := scanSQLiteCandle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read candles: %w", err)
+	}
+	return candles, nil
+}
+
+func (r *SQLiteCandleRepository) GetLatest(ctx context.Context, symbol string, interval models.CandleInterval) (*models.Candle, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	candle, err := scanSQLiteCandle(r.db.QueryRowContext(ctx,
+		`SELECT `+candleColumns+` FROM `+r.table+` WHERE symbol = $1 AND interval = $2 ORDER BY start_time DESC LIMIT 1`,
+		symbol, interval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest %s candle for %s: %w", interval, symbol, err)
+	}
+	return candle, nil
+}
+
+func (r *SQLiteCandleRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("SQLite not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE start_time < $1`, sqliteTimestamp(timestamp))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old candles")
+		return 0, fmt.Errorf("failed to delete old candles: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteCandleRepository) GetOldestIntervalTimestamps(ctx context.Context, interval models.CandleInterval, before time.Time) ([]*models.LatestTimestamp, error) {
+	oldest, err := sqliteLatestTimestamps(ctx, r.db,
+		`SELECT symbol, '', MIN(start_time) FROM `+r.table+` WHERE interval = $1 AND start_time < $2 GROUP BY symbol`,
+		interval, sqliteTimestamp(before))
+	if err != nil {
+		r.logger.WithError(err).WithField("interval", interval).Error("Failed to get oldest candle timestamps")
+		return nil, fmt.Errorf("failed to get oldest %s candle timestamps: %w", interval, err)
+	}
+	return oldest, nil
+}
+
+func (r *SQLiteCandleRepository) DeleteIntervalOlderThan(ctx context.Context, interval models.CandleInterval, timestamp time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("SQLite not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE interval = $1 AND start_time < $2`,
+		interval, sqliteTimestamp(timestamp))
+	if err != nil {
+		r.logger.WithError(err).WithField("interval", interval).Error("Failed to delete old candles")
+		return 0, fmt.Errorf("failed to delete old %s candles: %w", interval, err)
+	}
+	return result.RowsAffected()
+}
+
+// scanSQLiteCandle is scanCandle for times stored as text
+func scanSQLiteCandle(row rowScanner) (*models.Candle, error) {
+	var (
+		candle    models.Candle
+		startTime sqliteTime
+		endTime   sqliteTime
+		numTrades sql.NullInt64
+		metadata  []byte
+	)
+
+	err := row.Scan(
+		&candle.CandleID, &candle.Symbol, &candle.Interval, &candle.Open, &candle.High, &candle.Low,
+		&candle.Close, &candle.Volume, &startTime, &endTime, &numTrades, &metadata,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candle %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	candle.StartTime = startTime.Time
+	candle.EndTime = endTime.Time
+	if numTrades.Valid {
+		trades := int(numTrades.Int64)
+		candle.NumTrades = &trades
+	}
+	candle.Metadata = metadata
+
+	return &candle, nil
+}
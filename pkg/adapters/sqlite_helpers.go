@@ -0,0 +1,92 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+// sqliteTimeLayout is fixed-width, so stored times compare and sort as text in time order.
+// Times keep PostgreSQL's microsecond precision.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// sqliteTimestamp renders t for storage in SQLite
+func sqliteTimestamp(t time.Time) string {
+	return t.UTC().Round(time.Microsecond).Format(sqliteTimeLayout)
+}
+
+// sqliteTime scans a time stored by sqliteTimestamp; Valid is false for NULL
+type sqliteTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *sqliteTime) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		t.Time, t.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v.UTC(), true
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a time", value)
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return fmt.Errorf("invalid stored time %q: %w", text, err)
+	}
+	t.Time, t.Valid = parsed, true
+	return nil
+}
+
+// sqliteTable quotes a table name; SQLite has no schemas, so each instance uses its own file
+func sqliteTable(name string) string {
+	return pq.QuoteIdentifier(name)
+}
+
+// sqliteOrderClause is orderClause for SQLite, where decimal columns stored as text are
+// ordered by their value rather than lexically
+func sqliteOrderClause(sortBy, sortOrder string, sortable []string, fallback string, decimals ...string) string {
+	clause := orderClause(sortBy, sortOrder, sortable, fallback)
+	for _, column := range decimals {
+		quoted := pq.QuoteIdentifier(column)
+		clause = strings.Replace(clause, " ORDER BY "+quoted+" ", " ORDER BY CAST("+quoted+" AS REAL) ", 1)
+	}
+	return clause
+}
+
+// sqliteLatestTimestamps is queryLatestTimestamps for times stored as text
+func sqliteLatestTimestamps(ctx context.Context, db dbtx, query string, args ...interface{}) ([]*models.LatestTimestamp, error) {
+	if db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var latest []*models.LatestTimestamp
+	for rows.Next() {
+		var entry models.LatestTimestamp
+		var timestamp sqliteTime
+		if err := rows.Scan(&entry.Symbol, &entry.Source, &timestamp); err != nil {
+			return nil, err
+		}
+		entry.Timestamp = timestamp.Time
+		latest = append(latest, &entry)
+	}
+	return latest, rows.Err()
+}
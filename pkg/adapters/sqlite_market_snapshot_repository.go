@@ -0,0 +1,187 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// SQLiteMarketSnapshotRepository stores snapshots in SQLite with the semantics of
+// PostgresMarketSnapshotRepository, minus the outbox
+type SQLiteMarketSnapshotRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewSQLiteMarketSnapshotRepository(db *sql.DB, logger *logrus.Logger) interfaces.MarketSnapshotRepository {
+	return &SQLiteMarketSnapshotRepository{
+		db:     asDBTX(db),
+		table:  sqliteTable("market_snapshots"),
+		logger: logger,
+	}
+}
+
+func (r *SQLiteMarketSnapshotRepository) Create(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	if r.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+	if snapshot.SnapshotID == "" {
+		snapshot.SnapshotID = uuid.New().String()
+	}
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+
+	query := `INSERT INTO ` + r.table + ` (` + snapshotColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		snapshot.SnapshotID, snapshot.Symbol, snapshot.LastPrice, snapshot.Bid, snapshot.Ask,
+		snapshot.Spread, snapshot.Volume24h, snapshot.PriceChange24h, snapshot.PriceChangePercent24h,
+		sqliteTimestamp(snapshot.Timestamp), nullableJSON(snapshot.Metadata),
+	); err != nil {
+		r.logger.WithError(err).WithField("symbol", snapshot.Symbol).Error("Failed to create snapshot")
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteMarketSnapshotRepository) GetByID(ctx context.Context, snapshotID string) (*models.MarketSnapshot, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	snapshot, err := scanSQLiteMarketSnapshot(r.db.QueryRowContext(ctx,
+		`SELECT `+snapshotColumns+` FROM `+r.table+` WHERE snapshot_id = $1`, snapshotID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot %s: %w", snapshotID, err)
+	}
+	return snapshot, nil
+}
+
+func (r *SQLiteMarketSnapshotRepository) GetLatestBySymbol(ctx context.Context, symbol string) (*models.MarketSnapshot, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	snapshot, err := scanSQLiteMarketSnapshot(r.db.QueryRowContext(ctx,
+		`SELECT `+snapshotColumns+` FROM `+r.table+` WHERE symbol = $1 ORDER BY timestamp DESC LIMIT 1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshot for %s: %w", symbol, err)
+	}
+	return snapshot, nil
+}
+
+func (r *SQLiteMarketSnapshotRepository) GetBySymbol(ctx context.Context, symbol string, limit int) ([]*models.MarketSnapshot, error) {
+	return r.Query(ctx, &models.MarketSnapshotQuery{Symbol: &symbol, Limit: limit})
+}
+
+func (r *SQLiteMarketSnapshotRepository) Query(ctx context.Context, query *models.MarketSnapshotQuery) ([]*models.MarketSnapshot, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.TimestampFrom != nil {
+		where.add("timestamp >=", sqliteTimestamp(*query.TimestampFrom))
+	}
+	if query.TimestampTo != nil {
+		where.add("timestamp <", sqliteTimestamp(*query.TimestampTo))
+	}
+
+	statement := `SELECT ` + snapshotColumns + ` FROM ` + r.table + where.String() +
+		sqliteOrderClause(query.SortBy, query.SortOrder, snapshotSortColumns, "timestamp",
+			"last_price", "volume_24h", "price_change_percent_24h") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query snapshots")
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*models.MarketSnapshot
+	for rows.Next() {
+		snapshot, err := scanSQLiteMarketSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+func (r *SQLiteMarketSnapshotRepository) GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error) {
+	latest, err := sqliteLatestTimestamps(ctx, r.db,
+		`SELECT symbol, '', MAX(timestamp) FROM `+r.table+` WHERE timestamp >= $1 GROUP BY symbol`, sqliteTimestamp(since))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get latest snapshot timestamps")
+		return nil, fmt.Errorf("failed to get latest snapshot timestamps: %w", err)
+	}
+	return latest, nil
+}
+
+func (r *SQLiteMarketSnapshotRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("SQLite not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE timestamp < $1`, sqliteTimestamp(timestamp))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old snapshots")
+		return 0, fmt.Errorf("failed to delete old snapshots: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanSQLiteMarketSnapshot is scanMarketSnapshot for times stored as text
+func scanSQLiteMarketSnapshot(row rowScanner) (*models.MarketSnapshot, error) {
+	var (
+		snapshot              models.MarketSnapshot
+		bid                   decimal.NullDecimal
+		ask                   decimal.NullDecimal
+		spread                decimal.NullDecimal
+		volume24h             decimal.NullDecimal
+		priceChange24h        decimal.NullDecimal
+		priceChangePercent24h decimal.NullDecimal
+		timestamp             sqliteTime
+		metadata              []byte
+	)
+
+	err := row.Scan(
+		&snapshot.SnapshotID, &snapshot.Symbol, &snapshot.LastPrice, &bid, &ask, &spread, &volume24h,
+		&priceChange24h, &priceChangePercent24h, &timestamp, &metadata,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("snapshot %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Bid = decimalPtr(bid)
+	snapshot.Ask = decimalPtr(ask)
+	snapshot.Spread = decimalPtr(spread)
+	snapshot.Volume24h = decimalPtr(volume24h)
+	snapshot.PriceChange24h = decimalPtr(priceChange24h)
+	snapshot.PriceChangePercent24h = decimalPtr(priceChangePercent24h)
+	snapshot.Timestamp = timestamp.Time
+	snapshot.Metadata = metadata
+
+	return &snapshot, nil
+}
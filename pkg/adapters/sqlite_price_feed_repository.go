@@ -0,0 +1,337 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// SQLitePriceFeedRepository stores price feeds in SQLite with the semantics of
+// PostgresPriceFeedRepository, minus the outbox
+type SQLitePriceFeedRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewSQLitePriceFeedRepository(db *sql.DB, logger *logrus.Logger) interfaces.PriceFeedRepository {
+	return &SQLitePriceFeedRepository{
+		db:     asDBTX(db),
+		table:  sqliteTable("price_feeds"),
+		logger: logger,
+	}
+}
+
+func (r *SQLitePriceFeedRepository) Create(ctx context.Context, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	var result interfaces.CreateResult
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		var err error
+		result, err = r.insert(ctx, tx, feed)
+		return err
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", feed.Symbol).Error("Failed to create price feed")
+		return interfaces.CreateResult{}, fmt.Errorf("failed to create price feed: %w", err)
+	}
+	return result, nil
+}
+
+func (r *SQLitePriceFeedRepository) CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]interfaces.CreateResult, error) {
+	results := make([]interfaces.CreateResult, 0, len(feeds))
+	err := withTx(ctx, r.db, func(tx dbtx) error {
+		for _, feed := range feeds {
+			result, err := r.insert(ctx, tx, feed)
+			if err != nil {
+				return fmt.Errorf("symbol %s: %w", feed.Symbol, err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("count", len(feeds)).Error("Failed to create price feed batch")
+		return nil, fmt.Errorf("failed to create price feed batch: %w", err)
+	}
+	return results, nil
+}
+
+// insert stores feed unless another feed with the same idempotency key already exists;
+// SQLite has a single writer, so the unique key alone resolves replays
+func (r *SQLitePriceFeedRepository) insert(ctx context.Context, tx dbtx, feed *models.PriceFeed) (interfaces.CreateResult, error) {
+	if feed.FeedID == "" {
+		feed.FeedID = uuid.New().String()
+	}
+	now := time.Now()
+	if feed.Timestamp.IsZero() {
+		feed.Timestamp = now
+	}
+	if feed.ReceivedAt.IsZero() {
+		feed.ReceivedAt = now
+	}
+	persistedAt := time.Now()
+
+	query := `INSERT INTO ` + r.table + ` (feed_id, symbol, price, bid, ask, volume_24h, source, timestamp, metadata,
+			idempotency_key, sequence, received_at, persisted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING feed_id`
+
+	var feedID string
+	err := tx.QueryRowContext(ctx, query,
+		feed.FeedID, feed.Symbol, feed.Price, feed.Bid, feed.Ask, feed.Volume24h,
+		feed.Source, sqliteTimestamp(feed.Timestamp), nullableJSON(feed.Metadata), feed.IdempotencyKey, feed.Sequence,
+		sqliteTimestamp(feed.ReceivedAt), sqliteTimestamp(persistedAt),
+	).Scan(&feedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := tx.QueryRowContext(ctx,
+			`SELECT feed_id FROM `+r.table+` WHERE idempotency_key = $1`, *feed.IdempotencyKey,
+		).Scan(&feedID); err != nil {
+			return interfaces.CreateResult{}, fmt.Errorf("failed to look up duplicate price feed: %w", err)
+		}
+		return interfaces.CreateResult{FeedID: feedID, Duplicate: true}, nil
+	}
+	if err != nil {
+		return interfaces.CreateResult{}, err
+	}
+
+	feed.PersistedAt = persistedAt
+	return interfaces.CreateResult{FeedID: feedID}, nil
+}
+
+func (r *SQLitePriceFeedRepository) GetByID(ctx context.Context, feedID string) (*models.PriceFeed, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	feed, err := scanSQLitePriceFeed(r.db.QueryRowContext(ctx,
+		`SELECT `+priceFeedColumns+` FROM `+r.table+` WHERE feed_id = $1`, feedID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price feed %s: %w", feedID, err)
+	}
+	return feed, nil
+}
+
+func (r *SQLitePriceFeedRepository) GetLatestBySymbol(ctx context.Context, symbol string) (*models.PriceFeed, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	feed, err := scanSQLitePriceFeed(r.db.QueryRowContext(ctx,
+		`SELECT `+priceFeedColumns+` FROM `+r.table+` WHERE symbol = $1 ORDER BY timestamp DESC LIMIT 1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest price feed for %s: %w", symbol, err)
+	}
+	return feed, nil
+}
+
+func (r *SQLitePriceFeedRepository) GetBySymbol(ctx context.Context, symbol string, limit int) ([]*models.PriceFeed, error) {
+	return r.Query(ctx, &models.PriceFeedQuery{Symbol: &symbol, Limit: limit})
+}
+
+func (r *SQLitePriceFeedRepository) Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.Source != nil {
+		where.add("source =", *query.Source)
+	}
+	if query.TimestampFrom != nil {
+		where.add("timestamp >=", sqliteTimestamp(*query.TimestampFrom))
+	}
+	if query.TimestampTo != nil {
+		where.add("timestamp <", sqliteTimestamp(*query.TimestampTo))
+	}
+
+	statement := `SELECT ` + priceFeedColumns + ` FROM ` + r.table + where.String() +
+		sqliteOrderClause(query.SortBy, query.SortOrder, priceFeedSortColumns, "timestamp", "price") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query price feeds")
+		return nil, fmt.Errorf("failed to query price feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []*models.PriceFeed
+	for rows.Next() {
+		feed, err := scanSQLitePriceFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+func (r *SQLitePriceFeedRepository) GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error) {
+	latest, err := sqliteLatestTimestamps(ctx, r.db,
+		`SELECT symbol, source, MAX(timestamp) FROM `+r.table+` WHERE timestamp >= $1 GROUP BY symbol, source`,
+		sqliteTimestamp(since))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get latest price feed timestamps")
+		return nil, fmt.Errorf("failed to get latest price feed timestamps: %w", err)
+	}
+	return latest, nil
+}
+
+func (r *SQLitePriceFeedRepository) GetOldestTimestamps(ctx context.Context, before time.Time) ([]*models.LatestTimestamp, error) {
+	oldest, err := sqliteLatestTimestamps(ctx, r.db,
+		`SELECT symbol, '', MIN(timestamp) FROM `+r.table+` WHERE timestamp < $1 GROUP BY symbol`,
+		sqliteTimestamp(before))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get oldest price feed timestamps")
+		return nil, fmt.Errorf("failed to get oldest price feed timestamps: %w", err)
+	}
+	return oldest, nil
+}
+
+// LatencyStats computes the same continuous percentiles as PostgreSQL's percentile_cont,
+// in Go since SQLite has no percentile functions
+func (r *SQLitePriceFeedRepository) LatencyStats(ctx context.Context, from, to time.Time) ([]*models.LatencyStats, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT source, timestamp, received_at, persisted_at FROM `+r.table+`
+		WHERE received_at >= $1 AND received_at < $2 AND persisted_at IS NOT NULL
+		ORDER BY source`, sqliteTimestamp(from), sqliteTimestamp(to))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query price feed latency")
+		return nil, fmt.Errorf("failed to query price feed latency: %w", err)
+	}
+	defer rows.Close()
+
+	type samples struct {
+		feed, persist []float64
+		skew          float64
+	}
+	var sources []string
+	bySource := make(map[string]*samples)
+	for rows.Next() {
+		var source string
+		var timestamp, receivedAt, persistedAt sqliteTime
+		if err := rows.Scan(&source, &timestamp, &receivedAt, &persistedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price feed latency: %w", err)
+		}
+
+		s, ok := bySource[source]
+		if !ok {
+			s = &samples{}
+			bySource[source] = s
+			sources = append(sources, source)
+		}
+		s.feed = append(s.feed, receivedAt.Time.Sub(timestamp.Time).Seconds())
+		s.persist = append(s.persist, persistedAt.Time.Sub(receivedAt.Time).Seconds())
+		s.skew = math.Max(s.skew, timestamp.Time.Sub(receivedAt.Time).Seconds())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price feed latency: %w", err)
+	}
+
+	stats := make([]*models.LatencyStats, 0, len(sources))
+	for _, source := range sources {
+		s := bySource[source]
+		sort.Float64s(s.feed)
+		sort.Float64s(s.persist)
+		stats = append(stats, &models.LatencyStats{
+			Source:            source,
+			Samples:           int64(len(s.feed)),
+			FeedLatencyP50:    secondsToDuration(percentileCont(s.feed, 0.5)),
+			FeedLatencyP99:    secondsToDuration(percentileCont(s.feed, 0.99)),
+			PersistLatencyP50: secondsToDuration(percentileCont(s.persist, 0.5)),
+			PersistLatencyP99: secondsToDuration(percentileCont(s.persist, 0.99)),
+			ClockSkew:         secondsToDuration(s.skew),
+		})
+	}
+	return stats, nil
+}
+
+// percentileCont interpolates between the closest ranks of sorted, like percentile_cont
+func percentileCont(sorted []float64, fraction float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := fraction * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (position-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// DeleteOlderThan is retention cleanup; the idempotency keys of deleted feeds go with them,
+// so a replay that old is stored again
+func (r *SQLitePriceFeedRepository) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("SQLite not connected")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM `+r.table+` WHERE timestamp < $1`, sqliteTimestamp(timestamp))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete old price feeds")
+		return 0, fmt.Errorf("failed to delete old price feeds: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// scanSQLitePriceFeed is scanPriceFeed for times stored as text
+func scanSQLitePriceFeed(row rowScanner) (*models.PriceFeed, error) {
+	var (
+		feed           models.PriceFeed
+		bid            decimal.NullDecimal
+		ask            decimal.NullDecimal
+		volume24h      decimal.NullDecimal
+		timestamp      sqliteTime
+		metadata       []byte
+		idempotencyKey sql.NullString
+		sequence       sql.NullInt64
+		receivedAt     sqliteTime
+		persistedAt    sqliteTime
+	)
+
+	err := row.Scan(
+		&feed.FeedID, &feed.Symbol, &feed.Price, &bid, &ask, &volume24h, &feed.Source, &timestamp, &metadata,
+		&idempotencyKey, &sequence, &receivedAt, &persistedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("price feed %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	feed.Bid = decimalPtr(bid)
+	feed.Ask = decimalPtr(ask)
+	feed.Volume24h = decimalPtr(volume24h)
+	feed.Timestamp = timestamp.Time
+	feed.Metadata = metadata
+	if idempotencyKey.Valid {
+		feed.IdempotencyKey = &idempotencyKey.String
+	}
+	if sequence.Valid {
+		feed.Sequence = &sequence.Int64
+	}
+	feed.ReceivedAt = receivedAt.Time
+	feed.PersistedAt = persistedAt.Time
+
+	return &feed, nil
+}
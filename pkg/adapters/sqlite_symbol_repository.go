@@ -0,0 +1,230 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// SQLiteSymbolRepository stores symbols in SQLite with the semantics of
+// PostgresSymbolRepository, minus the outbox
+type SQLiteSymbolRepository struct {
+	db     dbtx
+	table  string
+	logger *logrus.Logger
+}
+
+func NewSQLiteSymbolRepository(db *sql.DB, logger *logrus.Logger) interfaces.SymbolRepository {
+	return &SQLiteSymbolRepository{
+		db:     asDBTX(db),
+		table:  sqliteTable("symbols"),
+		logger: logger,
+	}
+}
+
+func (r *SQLiteSymbolRepository) Create(ctx context.Context, symbol *models.Symbol) error {
+	if r.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+	if symbol.SymbolID == "" {
+		symbol.SymbolID = uuid.New().String()
+	}
+	now := time.Now()
+	if symbol.CreatedAt.IsZero() {
+		symbol.CreatedAt = now
+	}
+	symbol.UpdatedAt = now
+
+	query := `INSERT INTO ` + r.table + ` (` + symbolColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		symbol.SymbolID, symbol.Symbol, symbol.BaseCurrency, symbol.QuoteCurrency, symbol.DisplayName,
+		symbol.IsActive, symbol.MinPriceMovement, symbol.MinOrderSize, symbol.MaxOrderSize,
+		sqliteTimestamp(symbol.CreatedAt), sqliteTimestamp(symbol.UpdatedAt), nullableJSON(symbol.Metadata),
+	); err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol.Symbol).Error("Failed to create symbol")
+		return fmt.Errorf("failed to create symbol: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteSymbolRepository) GetByID(ctx context.Context, symbolID string) (*models.Symbol, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	symbol, err := scanSQLiteSymbol(r.db.QueryRowContext(ctx,
+		`SELECT `+symbolColumns+` FROM `+r.table+` WHERE symbol_id = $1`, symbolID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol %s: %w", symbolID, err)
+	}
+	return symbol, nil
+}
+
+func (r *SQLiteSymbolRepository) GetBySymbol(ctx context.Context, symbol string) (*models.Symbol, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	found, err := scanSQLiteSymbol(r.db.QueryRowContext(ctx,
+		`SELECT `+symbolColumns+` FROM `+r.table+` WHERE symbol = $1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol %s: %w", symbol, err)
+	}
+	return found, nil
+}
+
+func (r *SQLiteSymbolRepository) Query(ctx context.Context, query *models.SymbolQuery) ([]*models.Symbol, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("SQLite not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.BaseCurrency != nil {
+		where.add("base_currency =", *query.BaseCurrency)
+	}
+	if query.QuoteCurrency != nil {
+		where.add("quote_currency =", *query.QuoteCurrency)
+	}
+	if query.IsActive != nil {
+		where.add("is_active =", *query.IsActive)
+	}
+
+	sortOrder := query.SortOrder
+	if query.SortBy == "" && sortOrder == "" {
+		sortOrder = "asc"
+	}
+	statement := `SELECT ` + symbolColumns + ` FROM ` + r.table + where.String() +
+		orderClause(query.SortBy, sortOrder, symbolSortColumns, "symbol") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query symbols")
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []*models.Symbol
+	for rows.Next() {
+		symbol, err := scanSQLiteSymbol(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+func (r *SQLiteSymbolRepository) Update(ctx context.Context, symbol *models.Symbol) error {
+	if r.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+
+	query := `UPDATE ` + r.table + ` SET
+			symbol = $2, base_currency = $3, quote_currency = $4, display_name = $5, is_active = $6,
+			min_price_movement = $7, min_order_size = $8, max_order_size = $9, metadata = $10,
+			updated_at = $11
+		WHERE symbol_id = $1
+		RETURNING ` + symbolColumns
+
+	updated, err := scanSQLiteSymbol(r.db.QueryRowContext(ctx, query,
+		symbol.SymbolID, symbol.Symbol, symbol.BaseCurrency, symbol.QuoteCurrency, symbol.DisplayName,
+		symbol.IsActive, symbol.MinPriceMovement, symbol.MinOrderSize, symbol.MaxOrderSize,
+		nullableJSON(symbol.Metadata), sqliteTimestamp(time.Now()),
+	))
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol_id", symbol.SymbolID).Error("Failed to update symbol")
+		return fmt.Errorf("failed to update symbol: %w", err)
+	}
+
+	*symbol = *updated
+	return nil
+}
+
+func (r *SQLiteSymbolRepository) UpdateActiveStatus(ctx context.Context, symbolID string, isActive bool) error {
+	if r.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+
+	query := `UPDATE ` + r.table + ` SET is_active = $2, updated_at = $3
+		WHERE symbol_id = $1
+		RETURNING ` + symbolColumns
+
+	if _, err := scanSQLiteSymbol(r.db.QueryRowContext(ctx, query, symbolID, isActive, sqliteTimestamp(time.Now()))); err != nil {
+		r.logger.WithError(err).WithField("symbol_id", symbolID).Error("Failed to update symbol active status")
+		return fmt.Errorf("failed to update symbol active status: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteSymbolRepository) GetActive(ctx context.Context) ([]*models.Symbol, error) {
+	active := true
+	return r.Query(ctx, &models.SymbolQuery{IsActive: &active})
+}
+
+func (r *SQLiteSymbolRepository) Delete(ctx context.Context, symbolID string) error {
+	if r.db == nil {
+		return fmt.Errorf("SQLite not connected")
+	}
+
+	query := `DELETE FROM ` + r.table + ` WHERE symbol_id = $1 RETURNING ` + symbolColumns
+
+	if _, err := scanSQLiteSymbol(r.db.QueryRowContext(ctx, query, symbolID)); err != nil {
+		r.logger.WithError(err).WithField("symbol_id", symbolID).Error("Failed to delete symbol")
+		return fmt.Errorf("failed to delete symbol: %w", err)
+	}
+	return nil
+}
+
+// scanSQLiteSymbol is scanSymbol for times stored as text
+func scanSQLiteSymbol(row rowScanner) (*models.Symbol, error) {
+	var (
+		symbol           models.Symbol
+		displayName      sql.NullString
+		minPriceMovement decimal.NullDecimal
+		minOrderSize     decimal.NullDecimal
+		maxOrderSize     decimal.NullDecimal
+		createdAt        sqliteTime
+		updatedAt        sqliteTime
+		metadata         []byte
+	)
+
+	err := row.Scan(
+		&symbol.SymbolID, &symbol.Symbol, &symbol.BaseCurrency, &symbol.QuoteCurrency, &displayName,
+		&symbol.IsActive, &minPriceMovement, &minOrderSize, &maxOrderSize,
+		&createdAt, &updatedAt, &metadata,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("symbol %w", interfaces.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if displayName.Valid {
+		symbol.DisplayName = &displayName.String
+	}
+	symbol.MinPriceMovement = decimalPtr(minPriceMovement)
+	symbol.MinOrderSize = decimalPtr(minOrderSize)
+	symbol.MaxOrderSize = decimalPtr(maxOrderSize)
+	symbol.CreatedAt = createdAt.Time
+	symbol.UpdatedAt = updatedAt.Time
+	symbol.Metadata = metadata
+
+	return &symbol, nil
+}
@@ -0,0 +1,133 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/database"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// TimescaleCandleRepository reads coarser candle intervals from TimescaleDB continuous
+// aggregates of the source interval. Candles stored at an aggregated interval, such as
+// backfilled history, take precedence over the aggregate's bucket for the same start;
+// aggregate rows have no candle ID. Writes and other intervals go to the inner repository.
+type TimescaleCandleRepository struct {
+	interfaces.CandleRepository
+	db      dbtx
+	schema  string
+	table   string
+	sources map[models.CandleInterval]string
+	logger  *logrus.Logger
+}
+
+func NewTimescaleCandleRepository(repo interfaces.CandleRepository, db *sql.DB, schema string, aggregates []models.CandleInterval, logger *logrus.Logger) interfaces.CandleRepository {
+	return newTimescaleCandleRepository(repo, asDBTX(db), schema, aggregates, logger)
+}
+
+func newTimescaleCandleRepository(repo interfaces.CandleRepository, db dbtx, schema string, aggregates []models.CandleInterval, logger *logrus.Logger) *TimescaleCandleRepository {
+	r := &TimescaleCandleRepository{
+		CandleRepository: repo,
+		db:               db,
+		schema:           schema,
+		table:            qualifiedTable(schema, "candles"),
+		sources:          make(map[models.CandleInterval]string),
+		logger:           logger,
+	}
+	for _, interval := range aggregates {
+		r.sources[interval] = r.aggregatedSource(interval)
+	}
+	return r
+}
+
+// aggregatedSource selects candleColumns from stored candles of interval together with
+// the aggregate buckets that have no stored candle
+func (r *TimescaleCandleRepository) aggregatedSource(interval models.CandleInterval) string {
+	literal := pq.QuoteLiteral(string(interval))
+	return fmt.Sprintf(`(SELECT candle_id::text AS candle_id, symbol, interval, open, high, low, close, volume,
+        start_time, end_time, num_trades, metadata
+    FROM %[1]s WHERE interval = %[2]s
+    UNION ALL
+    SELECT '', a.symbol, %[2]s, a.open, a.high, a.low, a.close, a.volume,
+        a.bucket, a.bucket + INTERVAL %[3]s, a.num_trades,
+        jsonb_build_object('source', 'continuous_aggregate', 'candles', a.candles)
+    FROM %[4]s a
+    WHERE NOT EXISTS (
+        SELECT 1 FROM %[1]s c WHERE c.symbol = a.symbol AND c.interval = %[2]s AND c.start_time = a.bucket
+    )) candles`,
+		r.table, literal, pq.QuoteLiteral(fmt.Sprintf("%d seconds", int64(interval.Duration().Seconds()))),
+		qualifiedTable(r.schema, database.AggregateView(interval)))
+}
+
+func (r *TimescaleCandleRepository) GetBySymbolAndInterval(ctx context.Context, symbol string, interval models.CandleInterval, limit int) ([]*models.Candle, error) {
+	return r.Query(ctx, &models.CandleQuery{Symbol: &symbol, Interval: &interval, Limit: limit})
+}
+
+func (r *TimescaleCandleRepository) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	if query.Interval == nil {
+		return r.CandleRepository.Query(ctx, query)
+	}
+	source, ok := r.sources[*query.Interval]
+	if !ok {
+		return r.CandleRepository.Query(ctx, query)
+	}
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	var where whereClause
+	if query.Symbol != nil {
+		where.add("symbol =", *query.Symbol)
+	}
+	if query.StartTimeFrom != nil {
+		where.add("start_time >=", *query.StartTimeFrom)
+	}
+	if query.StartTimeTo != nil {
+		where.add("start_time <", *query.StartTimeTo)
+	}
+
+	statement := `SELECT ` + candleColumns + ` FROM ` + source + where.String() +
+		orderClause(query.SortBy, query.SortOrder, candleSortColumns, "start_time") +
+		limitClause(query.Limit, query.Offset)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		r.logger.WithError(err).WithField("interval", *query.Interval).Error("Failed to query aggregated candles")
+		return nil, fmt.Errorf("failed to query %s candles: %w", *query.Interval, err)
+	}
+	defer rows.Close()
+
+	var candles []*models.Candle
+	for rows.Next() {
+		candle, err := scanCandle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read candles: %w", err)
+	}
+	return candles, nil
+}
+
+func (r *TimescaleCandleRepository) GetLatest(ctx context.Context, symbol string, interval models.CandleInterval) (*models.Candle, error) {
+	source, ok := r.sources[interval]
+	if !ok {
+		return r.CandleRepository.GetLatest(ctx, symbol, interval)
+	}
+	if r.db == nil {
+		return nil, fmt.Errorf("PostgreSQL not connected")
+	}
+
+	candle, err := scanCandle(r.db.QueryRowContext(ctx,
+		`SELECT `+candleColumns+` FROM `+source+` WHERE symbol = $1 ORDER BY start_time DESC LIMIT 1`, symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest %s candle for %s: %w", interval, symbol, err)
+	}
+	return candle, nil
+}
@@ -0,0 +1,33 @@
+package adapters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimescaleCandleRepository_ServesOnlyAggregatedIntervals(t *testing.T) {
+	repo := NewTimescaleCandleRepository(&emptyCandleRepository{}, nil, "market_data", []models.CandleInterval{models.Interval1h}, newQuietLogger())
+
+	// Stored intervals and unfiltered queries go to the inner repository
+	minute := models.Interval1m
+	_, err := repo.Query(context.Background(), &models.CandleQuery{Interval: &minute})
+	require.NoError(t, err)
+	_, err = repo.Query(context.Background(), &models.CandleQuery{})
+	require.NoError(t, err)
+
+	_, err = repo.GetBySymbolAndInterval(context.Background(), "BTC-USD", models.Interval1h, 10)
+	assert.ErrorContains(t, err, "not connected", "Aggregated intervals are read from the view")
+}
+
+func TestTimescaleCandleRepository_PrefersStoredCandles(t *testing.T) {
+	repo := NewTimescaleCandleRepository(&emptyCandleRepository{}, nil, "market_data", nil, newQuietLogger()).(*TimescaleCandleRepository)
+
+	source := repo.aggregatedSource(models.Interval4h)
+	assert.Contains(t, source, `FROM "market_data"."candles_4h" a`)
+	assert.Contains(t, source, `a.bucket + INTERVAL '14400 seconds'`)
+	assert.Contains(t, source, `WHERE NOT EXISTS`, "Buckets with a stored candle come from the table")
+}
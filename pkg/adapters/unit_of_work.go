@@ -0,0 +1,240 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// PostgreSQL SQLSTATEs that mean "run the whole transaction again"
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// repositorySet is the price feed, candle, snapshot and symbol repositories callers write
+// through, either over the pool or bound to one transaction
+type repositorySet struct {
+	priceFeedRepo      interfaces.PriceFeedRepository
+	candleRepo         interfaces.CandleRepository
+	marketSnapshotRepo interfaces.MarketSnapshotRepository
+	symbolRepo         interfaces.SymbolRepository
+}
+
+func (r *repositorySet) PriceFeedRepository() interfaces.PriceFeedRepository {
+	return r.priceFeedRepo
+}
+
+func (r *repositorySet) CandleRepository() interfaces.CandleRepository {
+	return r.candleRepo
+}
+
+func (r *repositorySet) MarketSnapshotRepository() interfaces.MarketSnapshotRepository {
+	return r.marketSnapshotRepo
+}
+
+func (r *repositorySet) SymbolRepository() interfaces.SymbolRepository {
+	return r.symbolRepo
+}
+
+// afterCommit queues work that must not run until a transaction commits: fan-out, tick
+// snapshots, gap detection and anomaly filter updates, whose state a retried transaction
+// would replay. A nil queue runs the work straight away.
+type afterCommit struct {
+	mu  sync.Mutex
+	fns []func(ctx context.Context)
+}
+
+func (h *afterCommit) do(ctx context.Context, fn func(ctx context.Context)) {
+	if h == nil {
+		fn(ctx)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fns = append(h.fns, fn)
+}
+
+func (h *afterCommit) run(ctx context.Context) {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(ctx)
+	}
+}
+
+// afterCommitPublisher holds publications until the transaction commits
+type afterCommitPublisher struct {
+	interfaces.MarketDataPublisher
+	hooks  *afterCommit
+	logger *logrus.Logger
+}
+
+func (p *afterCommitPublisher) PublishPriceFeed(ctx context.Context, feed *models.PriceFeed) error {
+	p.hooks.do(ctx, func(ctx context.Context) {
+		if err := p.MarketDataPublisher.PublishPriceFeed(ctx, feed); err != nil {
+			p.logger.WithError(err).WithField("feed_id", feed.FeedID).Warn("Price feed committed but not published")
+		}
+	})
+	return nil
+}
+
+func (p *afterCommitPublisher) PublishSnapshot(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	p.hooks.do(ctx, func(ctx context.Context) {
+		if err := p.MarketDataPublisher.PublishSnapshot(ctx, snapshot); err != nil {
+			p.logger.WithError(err).WithField("snapshot_id", snapshot.SnapshotID).Warn("Market snapshot committed but not published")
+		}
+	})
+	return nil
+}
+
+// afterCommitEventLog holds event log appends until the transaction commits
+type afterCommitEventLog struct {
+	interfaces.EventLog
+	hooks  *afterCommit
+	logger *logrus.Logger
+}
+
+// Append returns no entry ID, since the entry does not exist until the commit
+func (l *afterCommitEventLog) Append(ctx context.Context, event *interfaces.LogEvent) (string, error) {
+	l.hooks.do(ctx, func(ctx context.Context) {
+		if _, err := l.EventLog.Append(ctx, event); err != nil {
+			l.logger.WithError(err).WithField("stream", event.Stream).Warn("Write committed but not appended to the event log")
+		}
+	})
+	return "", nil
+}
+
+// newTxRepositories binds the repositories to one transaction and applies the same
+// decorators as the pooled ones. Outbox rows are written in the transaction; fan-out and
+// other follow-up work is queued on hooks for after the commit.
+func (a *MarketDataAdapter) newTxRepositories(tx *sql.Tx, hooks *afterCommit) *repositorySet {
+	cfg, logger := a.config, a.logger
+
+	repos := &repositorySet{
+		priceFeedRepo:      newPostgresPriceFeedRepository(tx, cfg.SchemaName, cfg.OutboxEnabled, logger),
+		candleRepo:         newPostgresCandleRepository(tx, cfg.SchemaName, cfg.OutboxEnabled, logger),
+		marketSnapshotRepo: newPostgresMarketSnapshotRepository(tx, cfg.SchemaName, cfg.OutboxEnabled, logger),
+		symbolRepo:         newPostgresSymbolRepository(tx, cfg.SchemaName, cfg.OutboxEnabled, logger),
+	}
+	a.wrapStorage(repos, tx, hooks)
+	a.wrapTxRepositories(repos, newPostgresQuarantineRepository(tx, cfg.SchemaName, logger), hooks)
+	return repos
+}
+
+// wrapTxRepositories applies the backend-independent decorators to a transaction's
+// repositories, with fan-out held on hooks
+func (a *MarketDataAdapter) wrapTxRepositories(repos *repositorySet, quarantine interfaces.QuarantineRepository, hooks *afterCommit) {
+	if !a.config.OutboxEnabled {
+		var (
+			publisher interfaces.MarketDataPublisher
+			eventLog  interfaces.EventLog
+		)
+		if a.publisher != nil {
+			publisher = &afterCommitPublisher{MarketDataPublisher: a.publisher, hooks: hooks, logger: a.logger}
+		}
+		if a.eventLog != nil {
+			eventLog = &afterCommitEventLog{EventLog: a.eventLog, hooks: hooks, logger: a.logger}
+		}
+		a.wrapFanOut(repos, publisher, eventLog)
+	}
+	a.wrapScreening(repos, quarantine, hooks)
+}
+
+// WithTx runs fn in a transaction using the configured isolation level and retry budget
+func (a *MarketDataAdapter) WithTx(ctx context.Context, fn func(tx interfaces.TxRepositories) error) error {
+	return a.WithTxOptions(ctx, a.txOptions, fn)
+}
+
+// WithTxOptions runs fn in a transaction, committing if it returns nil. Serialization
+// failures and deadlocks re-run fn from the start in a fresh transaction, so fn must not
+// have side effects outside the database. Fan-out of the writes happens once, after the
+// commit. Only PostgreSQL supports transactions.
+func (a *MarketDataAdapter) WithTxOptions(ctx context.Context, options interfaces.TxOptions, fn func(tx interfaces.TxRepositories) error) error {
+	if a.sqliteDB != nil {
+		return fmt.Errorf("transactions are not supported on SQLite: %w", errors.ErrUnsupported)
+	}
+	if a.postgresDB == nil || a.postgresDB.DB == nil {
+		return fmt.Errorf("PostgreSQL not connected")
+	}
+
+	db := a.postgresDB.DB
+	return retryTx(ctx, options.MaxRetries, a.logger, func() error {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: options.Isolation, ReadOnly: options.ReadOnly})
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		hooks := &afterCommit{}
+		if err := fn(a.newTxRepositories(tx, hooks)); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		hooks.run(ctx)
+		return nil
+	})
+}
+
+// retryTx calls attempt until it succeeds, fails with a non-retryable error or the
+// retry budget runs out, backing off exponentially between attempts
+func retryTx(ctx context.Context, maxRetries int, logger *logrus.Logger, attempt func() error) error {
+	backoff := 10 * time.Millisecond
+	for retry := 0; ; retry++ {
+		err := attempt()
+		if err == nil || !isRetryableTxError(err) || retry >= maxRetries {
+			return err
+		}
+
+		logger.WithError(err).WithField("retry", retry+1).Debug("Retrying transaction")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == sqlStateSerializationFailure || pqErr.Code == sqlStateDeadlockDetected
+}
+
+// parseIsolationLevel accepts the SQL names in any case, with spaces or underscores
+func parseIsolationLevel(level string) (sql.IsolationLevel, error) {
+	switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(level)), " ", "_") {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read_uncommitted":
+		return sql.LevelReadUncommitted, nil
+	case "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("unknown transaction isolation level %q", level)
+	}
+}
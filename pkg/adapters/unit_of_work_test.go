@@ -0,0 +1,126 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/config"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/anomaly"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	tests := map[string]sql.IsolationLevel{
+		"":                 sql.LevelDefault,
+		"default":          sql.LevelDefault,
+		"read_committed":   sql.LevelReadCommitted,
+		"REPEATABLE READ":  sql.LevelRepeatableRead,
+		"Serializable":     sql.LevelSerializable,
+		"read_uncommitted": sql.LevelReadUncommitted,
+	}
+	for input, expected := range tests {
+		level, err := parseIsolationLevel(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, level, input)
+	}
+
+	_, err := parseIsolationLevel("snapshot")
+	assert.Error(t, err)
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	assert.True(t, isRetryableTxError(fmt.Errorf("failed to commit transaction: %w", &pq.Error{Code: "40001"})))
+	assert.True(t, isRetryableTxError(&pq.Error{Code: "40P01"}))
+	assert.False(t, isRetryableTxError(&pq.Error{Code: "23505"}))
+	assert.False(t, isRetryableTxError(errors.New("connection refused")))
+}
+
+func TestRetryTx(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	t.Run("retries serialization failures until success", func(t *testing.T) {
+		attempts := 0
+		err := retryTx(context.Background(), 3, logger, func() error {
+			attempts++
+			if attempts < 3 {
+				return &pq.Error{Code: "40001"}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		attempts := 0
+		err := retryTx(context.Background(), 2, logger, func() error {
+			attempts++
+			return &pq.Error{Code: "40P01"}
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		attempts := 0
+		err := retryTx(context.Background(), 3, logger, func() error {
+			attempts++
+			return errors.New("boom")
+		})
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestWrapTxRepositories_FansOutAfterCommit(t *testing.T) {
+	publisher := &recordingPublisher{}
+	adapter := &MarketDataAdapter{
+		config:        &config.Config{PublishMarketData: true},
+		logger:        newQuietLogger(),
+		publisher:     publisher,
+		anomalyFilter: anomaly.NewFilter(10, 0, anomaly.JumpDetector{MaxChange: 0.1}),
+		anomalyAction: anomaly.ActionReject,
+	}
+	inner := &stubPriceFeedRepository{}
+	repos := &repositorySet{priceFeedRepo: inner}
+	hooks := &afterCommit{}
+	adapter.wrapTxRepositories(repos, &recordingQuarantineRepository{}, hooks)
+
+	ctx := context.Background()
+	_, err := repos.PriceFeedRepository().Create(ctx, pricedFeed("100"))
+	require.NoError(t, err)
+	_, err = repos.PriceFeedRepository().Create(ctx, pricedFeed("150"))
+	assert.ErrorIs(t, err, interfaces.ErrAnomalousPrice, "Feeds are screened inside the transaction")
+	assert.Len(t, inner.created, 1)
+	assert.Empty(t, publisher.feeds, "Nothing is published before the commit")
+
+	hooks.run(ctx)
+	require.Len(t, publisher.feeds, 1)
+	assert.Equal(t, "100", publisher.feeds[0].Price.String())
+
+	_, err = repos.PriceFeedRepository().Create(ctx, pricedFeed("105"))
+	require.NoError(t, err, "The committed price became the reference")
+}
+
+func TestAfterCommit_RunsImmediatelyOutsideTransaction(t *testing.T) {
+	var hooks *afterCommit
+	ran := false
+	hooks.do(context.Background(), func(context.Context) { ran = true })
+	assert.True(t, ran)
+}
+
+func TestWithTx_UnsupportedOnSQLite(t *testing.T) {
+	adapter, err := NewMarketDataAdapter(&config.Config{DatabaseURL: "sqlite::memory:"}, newQuietLogger())
+	require.NoError(t, err)
+
+	err = adapter.WithTx(context.Background(), func(tx interfaces.TxRepositories) error { return nil })
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+}
@@ -0,0 +1,94 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+// ZScoreDetector flags prices more than Threshold standard deviations from the mean of
+// the window. It stays quiet until MinSamples prices have been accepted, and when the
+// window has no variance.
+type ZScoreDetector struct {
+	Threshold  float64
+	MinSamples int
+}
+
+func (d ZScoreDetector) Name() string {
+	return "zscore"
+}
+
+func (d ZScoreDetector) Inspect(feed *models.PriceFeed, history []float64) (string, bool) {
+	minSamples := d.MinSamples
+	if minSamples < 2 {
+		minSamples = 10
+	}
+	if d.Threshold <= 0 || len(history) < minSamples {
+		return "", false
+	}
+
+	var sum float64
+	for _, price := range history {
+		sum += price
+	}
+	mean := sum / float64(len(history))
+
+	var squares float64
+	for _, price := range history {
+		squares += (price - mean) * (price - mean)
+	}
+	stddev := math.Sqrt(squares / float64(len(history)-1))
+	if stddev == 0 {
+		return "", false
+	}
+
+	price, _ := feed.Price.Float64()
+	z := (price - mean) / stddev
+	if math.Abs(z) <= d.Threshold {
+		return "", false
+	}
+	return fmt.Sprintf("price %s is %.1f standard deviations from the mean %.8g", feed.Price, z, mean), true
+}
+
+// JumpDetector flags prices that moved more than MaxChange, as a fraction, from the
+// last accepted price
+type JumpDetector struct {
+	MaxChange float64
+}
+
+func (d JumpDetector) Name() string {
+	return "jump"
+}
+
+func (d JumpDetector) Inspect(feed *models.PriceFeed, history []float64) (string, bool) {
+	if d.MaxChange <= 0 || len(history) == 0 {
+		return "", false
+	}
+
+	last := history[len(history)-1]
+	if last == 0 {
+		return "", false
+	}
+
+	price, _ := feed.Price.Float64()
+	change := price/last - 1
+	if math.Abs(change) <= d.MaxChange {
+		return "", false
+	}
+	return fmt.Sprintf("price %s moved %.2f%% from the last price %.8g", feed.Price, change*100, last), true
+}
+
+// CrossedQuoteDetector flags feeds whose bid is above their ask
+type CrossedQuoteDetector struct{}
+
+func (d CrossedQuoteDetector) Name() string {
+	return "crossed_quote"
+}
+
+func (d CrossedQuoteDetector) Inspect(feed *models.PriceFeed, history []float64) (string, bool) {
+	if feed.Bid == nil || feed.Ask == nil || !feed.Bid.GreaterThan(*feed.Ask) {
+		return "", false
+	}
+	return fmt.Sprintf("bid %s is above ask %s", feed.Bid, feed.Ask), true
+}
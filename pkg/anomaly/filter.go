@@ -0,0 +1,263 @@
+package anomaly
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+// Action is what happens to a price feed that fails a check
+type Action string
+
+const (
+	ActionFlag       Action = "flag"       // Store it, annotated with the findings
+	ActionQuarantine Action = "quarantine" // Hold it back for review
+	ActionReject     Action = "reject"     // Drop it with an error
+)
+
+// ParseAction accepts the Action names case-insensitively
+func ParseAction(value string) (Action, error) {
+	switch action := Action(strings.ToLower(strings.TrimSpace(value))); action {
+	case ActionFlag, ActionQuarantine, ActionReject:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown anomaly action %q", value)
+	}
+}
+
+// Detector is one anomaly check. History holds the recently accepted prices for the
+// feed's symbol and source, oldest first.
+type Detector interface {
+	Name() string
+	Inspect(feed *models.PriceFeed, history []float64) (reason string, anomalous bool)
+}
+
+// DefaultWindow is the number of accepted prices kept per symbol and source
+const DefaultWindow = 50
+
+type historyKey struct {
+	symbol string
+	source string
+}
+
+// Filter runs detectors against incoming feeds using a rolling window of accepted
+// prices per symbol and source. Only feeds passed to Accept enter the window, so bad
+// prints do not skew later checks. So that a genuine level shift is not flagged forever,
+// a run of rebaseline consecutive anomalous prices that pass the detectors against each
+// other replaces the window.
+type Filter struct {
+	detectors  []Detector
+	window     int
+	rebaseline int
+
+	mu      sync.Mutex
+	history map[historyKey][]float64
+	runs    map[historyKey][]float64
+}
+
+// NewFilter creates a filter; a rebaseline of 0 keeps the window until clean prices arrive
+func NewFilter(window, rebaseline int, detectors ...Detector) *Filter {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Filter{
+		detectors:  detectors,
+		window:     window,
+		rebaseline: rebaseline,
+		history:    make(map[historyKey][]float64),
+		runs:       make(map[historyKey][]float64),
+	}
+}
+
+// Inspect returns the findings of every detector that considers the feed anomalous. An
+// anomalous price that completes a consistent run becomes the new baseline and is
+// inspected against the run instead.
+func (f *Filter) Inspect(feed *models.PriceFeed) []models.AnomalyFinding {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.inspectIn(f, feed)
+}
+
+// Accept adds a stored feed's price to its window
+func (f *Filter) Accept(feed *models.PriceFeed) {
+	price, _ := feed.Price.Float64()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.acceptPrice(historyKey{symbol: feed.Symbol, source: feed.Source}, price)
+}
+
+// Stage starts a set of inspections and accepts that leave the filter untouched until
+// Commit, e.g. for the feeds written in one transaction
+func (f *Filter) Stage() *Stage {
+	return &Stage{
+		filter:  f,
+		history: make(map[historyKey][]float64),
+		runs:    make(map[historyKey][]float64),
+	}
+}
+
+// state is the window and rebaseline run storage Inspect and Accept work against: the
+// filter itself, or a Stage layered over it. Callers hold the filter's lock.
+type state interface {
+	windowOf(key historyKey) []float64
+	runOf(key historyKey) []float64
+	replaceWindow(key historyKey, window []float64)
+	replaceRun(key historyKey, run []float64) // nil clears the run
+	acceptPrice(key historyKey, price float64)
+}
+
+func (f *Filter) inspectIn(st state, feed *models.PriceFeed) []models.AnomalyFinding {
+	key := historyKey{symbol: feed.Symbol, source: feed.Source}
+
+	findings := f.inspect(feed, st.windowOf(key))
+	if len(findings) == 0 {
+		st.replaceRun(key, nil)
+		return nil
+	}
+	if f.rebaseline <= 0 {
+		return findings
+	}
+
+	run := st.runOf(key)
+	if len(run) > 0 && len(f.inspect(feed, run)) > 0 {
+		run = nil
+	}
+	price, _ := feed.Price.Float64()
+	run = append(append([]float64(nil), run...), price)
+	if len(run) < f.rebaseline {
+		st.replaceRun(key, run)
+		return findings
+	}
+
+	// The feed itself enters the window through Accept once it is stored
+	st.replaceRun(key, nil)
+	st.replaceWindow(key, run[:len(run)-1])
+	return f.inspect(feed, st.windowOf(key))
+}
+
+func (f *Filter) inspect(feed *models.PriceFeed, history []float64) []models.AnomalyFinding {
+	var findings []models.AnomalyFinding
+	for _, detector := range f.detectors {
+		if reason, anomalous := detector.Inspect(feed, history); anomalous {
+			findings = append(findings, models.AnomalyFinding{Check: detector.Name(), Reason: reason})
+		}
+	}
+	return findings
+}
+
+func (f *Filter) windowOf(key historyKey) []float64 {
+	return f.history[key]
+}
+
+func (f *Filter) runOf(key historyKey) []float64 {
+	return f.runs[key]
+}
+
+func (f *Filter) replaceWindow(key historyKey, window []float64) {
+	f.history[key] = append([]float64(nil), window...)
+}
+
+func (f *Filter) replaceRun(key historyKey, run []float64) {
+	if run == nil {
+		delete(f.runs, key)
+		return
+	}
+	f.runs[key] = append([]float64(nil), run...)
+}
+
+func (f *Filter) acceptPrice(key historyKey, price float64) {
+	f.history[key] = f.extend(f.history[key], price)
+}
+
+// extend appends price to a window, dropping the oldest prices beyond the window size
+func (f *Filter) extend(window []float64, price float64) []float64 {
+	window = append(window, price)
+	if len(window) > f.window {
+		window = window[len(window)-f.window:]
+	}
+	return window
+}
+
+// Stage is a pending set of changes to a Filter. Its inspections and accepts see each
+// other, so later feeds in a transaction are screened against earlier ones, but the
+// filter only sees them once Commit replays them; a discarded stage leaves no trace.
+type Stage struct {
+	filter *Filter
+
+	mu      sync.Mutex
+	history map[historyKey][]float64 // Windows changed in this stage
+	runs    map[historyKey][]float64 // Runs changed in this stage, nil once cleared
+	changes []func(f *Filter)
+}
+
+// Inspect is Filter.Inspect against the filter with this stage's changes applied
+func (s *Stage) Inspect(feed *models.PriceFeed) []models.AnomalyFinding {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filter.mu.Lock()
+	defer s.filter.mu.Unlock()
+
+	return s.filter.inspectIn(s, feed)
+}
+
+// Accept is Filter.Accept, held back until Commit
+func (s *Stage) Accept(feed *models.PriceFeed) {
+	price, _ := feed.Price.Float64()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filter.mu.Lock()
+	defer s.filter.mu.Unlock()
+
+	s.acceptPrice(historyKey{symbol: feed.Symbol, source: feed.Source}, price)
+}
+
+// Commit applies the staged changes to the filter in order and empties the stage
+func (s *Stage) Commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filter.mu.Lock()
+	defer s.filter.mu.Unlock()
+
+	for _, change := range s.changes {
+		change(s.filter)
+	}
+	s.changes = nil
+	s.history = make(map[historyKey][]float64)
+	s.runs = make(map[historyKey][]float64)
+}
+
+func (s *Stage) windowOf(key historyKey) []float64 {
+	if window, ok := s.history[key]; ok {
+		return window
+	}
+	return s.filter.history[key]
+}
+
+func (s *Stage) runOf(key historyKey) []float64 {
+	if run, ok := s.runs[key]; ok {
+		return run
+	}
+	return s.filter.runs[key]
+}
+
+func (s *Stage) replaceWindow(key historyKey, window []float64) {
+	s.history[key] = append([]float64(nil), window...)
+	s.changes = append(s.changes, func(f *Filter) { f.replaceWindow(key, window) })
+}
+
+func (s *Stage) replaceRun(key historyKey, run []float64) {
+	s.runs[key] = run
+	s.changes = append(s.changes, func(f *Filter) { f.replaceRun(key, run) })
+}
+
+func (s *Stage) acceptPrice(key historyKey, price float64) {
+	s.history[key] = s.filter.extend(append([]float64(nil), s.windowOf(key)...), price)
+	// Replayed as an append, so prices the filter accepted meanwhile are kept
+	s.changes = append(s.changes, func(f *Filter) { f.acceptPrice(key, price) })
+}
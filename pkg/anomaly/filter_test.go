@@ -0,0 +1,155 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func priceFeed(price string) *models.PriceFeed {
+	return &models.PriceFeed{Symbol: "BTC-USD", Source: "coinbase", Price: decimal.RequireFromString(price)}
+}
+
+func TestZScoreDetector(t *testing.T) {
+	filter := NewFilter(20, 0, ZScoreDetector{Threshold: 4, MinSamples: 5})
+	for _, price := range []string{"100", "101", "99", "100.5", "99.5", "100"} {
+		require.Empty(t, filter.Inspect(priceFeed(price)))
+		filter.Accept(priceFeed(price))
+	}
+
+	assert.Empty(t, filter.Inspect(priceFeed("101.5")))
+
+	findings := filter.Inspect(priceFeed("110"))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "zscore", findings[0].Check)
+}
+
+func TestZScoreDetector_WaitsForMinSamples(t *testing.T) {
+	filter := NewFilter(20, 0, ZScoreDetector{Threshold: 1, MinSamples: 5})
+	filter.Accept(priceFeed("100"))
+	filter.Accept(priceFeed("101"))
+
+	assert.Empty(t, filter.Inspect(priceFeed("1000")))
+}
+
+func TestJumpDetector(t *testing.T) {
+	filter := NewFilter(0, 0, JumpDetector{MaxChange: 0.05})
+	assert.Empty(t, filter.Inspect(priceFeed("100")), "No last price to compare against")
+	filter.Accept(priceFeed("100"))
+
+	assert.Empty(t, filter.Inspect(priceFeed("104")))
+	assert.Empty(t, filter.Inspect(priceFeed("96")))
+
+	findings := filter.Inspect(priceFeed("90"))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "jump", findings[0].Check)
+
+	other := priceFeed("10")
+	other.Source = "kraken"
+	assert.Empty(t, filter.Inspect(other), "History is per source")
+}
+
+func TestFilter_RebaselinesAfterLevelShift(t *testing.T) {
+	filter := NewFilter(20, 3, ZScoreDetector{Threshold: 4, MinSamples: 5}, JumpDetector{MaxChange: 0.05})
+	for _, price := range []string{"100", "101", "99", "100.5", "99.5", "100"} {
+		require.Empty(t, filter.Inspect(priceFeed(price)))
+		filter.Accept(priceFeed(price))
+	}
+
+	assert.Len(t, filter.Inspect(priceFeed("150")), 2)
+	assert.Len(t, filter.Inspect(priceFeed("151")), 2)
+	assert.Empty(t, filter.Inspect(priceFeed("150.5")), "Third consistent print re-baselines")
+	filter.Accept(priceFeed("150.5"))
+
+	assert.Empty(t, filter.Inspect(priceFeed("150")))
+	assert.Len(t, filter.Inspect(priceFeed("100")), 1, "The old level is now the jump")
+}
+
+func TestFilter_InconsistentRunDoesNotRebaseline(t *testing.T) {
+	filter := NewFilter(0, 2, JumpDetector{MaxChange: 0.05})
+	filter.Accept(priceFeed("100"))
+
+	assert.NotEmpty(t, filter.Inspect(priceFeed("150")))
+	assert.NotEmpty(t, filter.Inspect(priceFeed("50")), "Spikes in opposite directions are not a level")
+	assert.Empty(t, filter.Inspect(priceFeed("101")))
+	assert.NotEmpty(t, filter.Inspect(priceFeed("150")), "A clean print resets the run")
+
+	noRebaseline := NewFilter(0, 0, JumpDetector{MaxChange: 0.05})
+	noRebaseline.Accept(priceFeed("100"))
+	for i := 0; i < 10; i++ {
+		assert.NotEmpty(t, noRebaseline.Inspect(priceFeed("150")))
+	}
+}
+
+func TestCrossedQuoteDetector(t *testing.T) {
+	filter := NewFilter(0, 0, CrossedQuoteDetector{})
+
+	feed := priceFeed("100")
+	bid, ask := decimal.NewFromInt(99), decimal.NewFromInt(101)
+	feed.Bid, feed.Ask = &bid, &ask
+	assert.Empty(t, filter.Inspect(feed))
+
+	feed.Bid, feed.Ask = &ask, &bid
+	findings := filter.Inspect(feed)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "crossed_quote", findings[0].Check)
+}
+
+func TestFilter_WindowIsBounded(t *testing.T) {
+	filter := NewFilter(3, 0, JumpDetector{MaxChange: 0.5})
+	for _, price := range []string{"1", "2", "3", "100"} {
+		filter.Accept(priceFeed(price))
+	}
+
+	assert.Equal(t, []float64{2, 3, 100}, filter.history[historyKey{symbol: "BTC-USD", source: "coinbase"}])
+}
+
+func TestStage_SeesItsOwnChangesUntilCommit(t *testing.T) {
+	filter := NewFilter(0, 2, JumpDetector{MaxChange: 0.05})
+	filter.Accept(priceFeed("100"))
+	key := historyKey{symbol: "BTC-USD", source: "coinbase"}
+
+	stage := filter.Stage()
+	assert.Empty(t, stage.Inspect(priceFeed("104")))
+	stage.Accept(priceFeed("104"))
+	assert.Empty(t, stage.Inspect(priceFeed("108")), "Later feeds are screened against staged accepts")
+	assert.NotEmpty(t, stage.Inspect(priceFeed("150")))
+
+	assert.Equal(t, []float64{100}, filter.history[key], "The filter is untouched before Commit")
+	assert.Empty(t, filter.runs)
+
+	// A price accepted outside the stage meanwhile survives the commit
+	filter.Accept(priceFeed("101"))
+	stage.Commit()
+
+	assert.Equal(t, []float64{100, 101, 104}, filter.history[key])
+	assert.Equal(t, []float64{150}, filter.runs[key], "Staged runs apply on Commit")
+	assert.Empty(t, filter.Inspect(priceFeed("151")), "The committed run completes the rebaseline")
+}
+
+func TestStage_DiscardedStageLeavesFilterUnchanged(t *testing.T) {
+	filter := NewFilter(0, 2, JumpDetector{MaxChange: 0.05})
+	filter.Accept(priceFeed("100"))
+
+	stage := filter.Stage()
+	assert.NotEmpty(t, stage.Inspect(priceFeed("150")))
+	assert.Empty(t, stage.Inspect(priceFeed("151")), "The staged run rebaselines within the stage")
+	stage.Accept(priceFeed("151"))
+
+	// Never committed, like a rolled-back transaction
+	assert.Equal(t, []float64{100}, filter.history[historyKey{symbol: "BTC-USD", source: "coinbase"}])
+	assert.Empty(t, filter.runs)
+	assert.NotEmpty(t, filter.Inspect(priceFeed("150")), "A rolled-back run does not count toward a rebaseline")
+}
+
+func TestParseAction(t *testing.T) {
+	action, err := ParseAction("Quarantine")
+	require.NoError(t, err)
+	assert.Equal(t, ActionQuarantine, action)
+
+	_, err = ParseAction("ignore")
+	assert.Error(t, err)
+}
@@ -0,0 +1,228 @@
+package arbitration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/pricing"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoHealthySource is returned when none of a symbol's configured sources is usable
+var ErrNoHealthySource = errors.New("no healthy feed source")
+
+type Options struct {
+	MaxAge        time.Duration // A source is stale when its latest feed is older; defaults to 10s
+	MaxDeviation  float64       // A source further than this fraction from the median of fresh sources is unhealthy; 0 disables. Needs 3 fresh sources.
+	FailbackAfter time.Duration // A preferred source must stay healthy this long before it takes over again; defaults to 30s
+}
+
+// Selection is the feed the arbiter serves for a symbol
+type Selection struct {
+	Symbol    string            `json:"symbol"`
+	Source    string            `json:"source"`
+	Feed      *models.PriceFeed `json:"feed"`
+	Preferred bool              `json:"preferred"` // Source is the symbol's highest-priority source
+}
+
+type symbolState struct {
+	active       string
+	healthySince map[string]time.Time
+}
+
+// Arbiter serves each symbol from its highest-priority healthy source. When the active
+// source goes stale or deviates it fails over immediately; a preferred source that
+// recovers only takes over again after staying healthy for FailbackAfter, so a flapping
+// vendor does not bounce the symbol back and forth. Health is evaluated on each Price
+// call and every switch is logged and recorded.
+type Arbiter struct {
+	feeds       interfaces.PriceFeedRepository
+	arbitration interfaces.ArbitrationRepository
+	options     Options
+	logger      *logrus.Logger
+	now         func() time.Time
+
+	mu      sync.Mutex
+	symbols map[string]*symbolState
+}
+
+func NewArbiter(feeds interfaces.PriceFeedRepository, arbitration interfaces.ArbitrationRepository, options Options, logger *logrus.Logger) *Arbiter {
+	if options.MaxAge <= 0 {
+		options.MaxAge = 10 * time.Second
+	}
+	if options.FailbackAfter <= 0 {
+		options.FailbackAfter = 30 * time.Second
+	}
+
+	return &Arbiter{
+		feeds:       feeds,
+		arbitration: arbitration,
+		options:     options,
+		logger:      logger,
+		now:         time.Now,
+		symbols:     make(map[string]*symbolState),
+	}
+}
+
+// Price returns the latest feed from the source currently serving symbol
+func (a *Arbiter) Price(ctx context.Context, symbol string) (*Selection, error) {
+	priority, err := a.arbitration.GetSourcePriority(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(priority) == 0 {
+		return nil, fmt.Errorf("no feed sources configured for %s", symbol)
+	}
+
+	now := a.now()
+	from := now.Add(-a.options.MaxAge)
+	feeds, err := a.feeds.Query(ctx, &models.PriceFeedQuery{
+		Symbol:        &symbol,
+		TimestampFrom: &from,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load price feeds for arbitration: %w", err)
+	}
+
+	latest := make(map[string]*models.PriceFeed)
+	for _, feed := range feeds {
+		if current, ok := latest[feed.Source]; !ok || feed.Timestamp.After(current.Timestamp) {
+			latest[feed.Source] = feed
+		}
+	}
+	health := a.evaluate(symbol, priority, latest, now)
+
+	a.mu.Lock()
+	state, ok := a.symbols[symbol]
+	if !ok {
+		state = &symbolState{healthySince: make(map[string]time.Time)}
+		a.symbols[symbol] = state
+	}
+	for _, source := range priority {
+		if _, unhealthy := health[source]; unhealthy {
+			delete(state.healthySince, source)
+		} else if _, tracked := state.healthySince[source]; !tracked {
+			state.healthySince[source] = now
+		}
+	}
+
+	previous := state.active
+	next, reason := a.choose(state, priority, health, now)
+	if next != "" {
+		state.active = next
+	}
+	a.mu.Unlock()
+
+	if next == "" {
+		return nil, fmt.Errorf("%s: %w", symbol, ErrNoHealthySource)
+	}
+	if next != previous {
+		a.recordSwitch(ctx, symbol, previous, next, reason, now)
+	}
+
+	return &Selection{
+		Symbol:    symbol,
+		Source:    next,
+		Feed:      latest[next],
+		Preferred: next == priority[0],
+	}, nil
+}
+
+// evaluate returns why each unhealthy source in priority is unusable; healthy sources are absent
+func (a *Arbiter) evaluate(symbol string, priority []string, latest map[string]*models.PriceFeed, now time.Time) map[string]models.SourceSwitchReason {
+	health := make(map[string]models.SourceSwitchReason)
+
+	var fresh []*models.PriceFeed
+	for _, source := range priority {
+		feed, ok := latest[source]
+		if !ok || now.Sub(feed.Timestamp) > a.options.MaxAge {
+			health[source] = models.SwitchSourceStale
+			continue
+		}
+		fresh = append(fresh, feed)
+	}
+
+	// With fewer than three sources the median cannot tell which one is wrong
+	if a.options.MaxDeviation > 0 && len(fresh) >= 3 {
+		consensus, err := pricing.Compute(symbol, fresh, now, pricing.Options{Method: pricing.MethodMedian})
+		if err == nil {
+			limit := consensus.Price.Mul(decimal.NewFromFloat(a.options.MaxDeviation))
+			for _, feed := range fresh {
+				if feed.Price.Sub(consensus.Price).Abs().GreaterThan(limit) {
+					health[feed.Source] = models.SwitchSourceDeviation
+				}
+			}
+		}
+	}
+
+	return health
+}
+
+// choose keeps a healthy active source unless a preferred one has been healthy for
+// FailbackAfter, and otherwise fails over to the highest-priority healthy source
+func (a *Arbiter) choose(state *symbolState, priority []string, health map[string]models.SourceSwitchReason, now time.Time) (string, models.SourceSwitchReason) {
+	activeReason, activeUnhealthy := health[state.active]
+	if state.active != "" && contains(priority, state.active) && !activeUnhealthy {
+		for _, source := range priority {
+			if source == state.active {
+				return state.active, ""
+			}
+			if since, ok := state.healthySince[source]; ok && now.Sub(since) >= a.options.FailbackAfter {
+				return source, models.SwitchFailback
+			}
+		}
+	}
+
+	if !activeUnhealthy {
+		// The active source was removed from the priority list
+		activeReason = models.SwitchReprioritized
+	}
+	for _, source := range priority {
+		if _, unhealthy := health[source]; !unhealthy {
+			return source, activeReason
+		}
+	}
+	return "", ""
+}
+
+func (a *Arbiter) recordSwitch(ctx context.Context, symbol, from, to string, reason models.SourceSwitchReason, now time.Time) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"symbol": symbol,
+		"source": to,
+	})
+	if from == "" {
+		// The first selection after start-up is not a switch
+		logger.Info("Feed source selected")
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"from_source": from,
+		"reason":      reason,
+	}).Warn("Feed source switched")
+
+	if err := a.arbitration.RecordSwitch(ctx, &models.SourceSwitchEvent{
+		Symbol:     symbol,
+		FromSource: from,
+		ToSource:   to,
+		Reason:     reason,
+		SwitchedAt: now,
+	}); err != nil {
+		logger.WithError(err).Warn("Failed to record feed source switch")
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
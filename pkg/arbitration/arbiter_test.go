@@ -0,0 +1,178 @@
+package arbitration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFeeds serves the latest price per source, timestamped when it was last set
+type fakeFeeds struct {
+	interfaces.PriceFeedRepository
+	feeds map[string]*models.PriceFeed
+}
+
+func (f *fakeFeeds) set(source, price string, at time.Time) {
+	f.feeds[source] = &models.PriceFeed{
+		FeedID:    source,
+		Symbol:    "BTC-USD",
+		Source:    source,
+		Price:     decimal.RequireFromString(price),
+		Timestamp: at,
+	}
+}
+
+func (f *fakeFeeds) Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error) {
+	var result []*models.PriceFeed
+	for _, feed := range f.feeds {
+		if !feed.Timestamp.Before(*query.TimestampFrom) {
+			result = append(result, feed)
+		}
+	}
+	return result, nil
+}
+
+type fakeArbitration struct {
+	interfaces.ArbitrationRepository
+	priority []string
+	switches []*models.SourceSwitchEvent
+}
+
+func (f *fakeArbitration) GetSourcePriority(ctx context.Context, symbol string) ([]string, error) {
+	return f.priority, nil
+}
+
+func (f *fakeArbitration) RecordSwitch(ctx context.Context, event *models.SourceSwitchEvent) error {
+	f.switches = append(f.switches, event)
+	return nil
+}
+
+type harness struct {
+	arbiter     *Arbiter
+	feeds       *fakeFeeds
+	arbitration *fakeArbitration
+	now         time.Time
+}
+
+func newHarness(options Options, priority ...string) *harness {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	h := &harness{
+		feeds:       &fakeFeeds{feeds: make(map[string]*models.PriceFeed)},
+		arbitration: &fakeArbitration{priority: priority},
+		now:         time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	h.arbiter = NewArbiter(h.feeds, h.arbitration, options, logger)
+	h.arbiter.now = func() time.Time { return h.now }
+	return h
+}
+
+func (h *harness) price(t *testing.T) *Selection {
+	t.Helper()
+	selection, err := h.arbiter.Price(context.Background(), "BTC-USD")
+	require.NoError(t, err)
+	return selection
+}
+
+func (h *harness) advance(d time.Duration) {
+	h.now = h.now.Add(d)
+}
+
+func TestArbiter_ServesPrimaryWhenHealthy(t *testing.T) {
+	h := newHarness(Options{MaxAge: 5 * time.Second}, "primary", "backup")
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "101", h.now)
+
+	selection := h.price(t)
+
+	assert.Equal(t, "primary", selection.Source)
+	assert.True(t, selection.Preferred)
+	assert.True(t, decimal.NewFromInt(100).Equal(selection.Feed.Price))
+	assert.Empty(t, h.arbitration.switches, "The initial selection is not a switch")
+}
+
+func TestArbiter_FailsOverWhenPrimaryGoesStale(t *testing.T) {
+	h := newHarness(Options{MaxAge: 5 * time.Second}, "primary", "backup")
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "101", h.now)
+	h.price(t)
+
+	h.advance(10 * time.Second)
+	h.feeds.set("backup", "102", h.now)
+	selection := h.price(t)
+
+	assert.Equal(t, "backup", selection.Source)
+	assert.False(t, selection.Preferred)
+	require.Len(t, h.arbitration.switches, 1)
+	assert.Equal(t, "primary", h.arbitration.switches[0].FromSource)
+	assert.Equal(t, "backup", h.arbitration.switches[0].ToSource)
+	assert.Equal(t, models.SwitchSourceStale, h.arbitration.switches[0].Reason)
+}
+
+func TestArbiter_FailsOverWhenPrimaryDeviates(t *testing.T) {
+	h := newHarness(Options{MaxAge: 5 * time.Second, MaxDeviation: 0.01}, "primary", "backup", "tertiary")
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "100.5", h.now)
+	h.feeds.set("tertiary", "100.2", h.now)
+	assert.Equal(t, "primary", h.price(t).Source)
+
+	h.feeds.set("primary", "120", h.now)
+	selection := h.price(t)
+
+	assert.Equal(t, "backup", selection.Source)
+	require.Len(t, h.arbitration.switches, 1)
+	assert.Equal(t, models.SwitchSourceDeviation, h.arbitration.switches[0].Reason)
+}
+
+func TestArbiter_FailsBackOnlyAfterHysteresis(t *testing.T) {
+	h := newHarness(Options{MaxAge: 5 * time.Second, FailbackAfter: 30 * time.Second}, "primary", "backup")
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "101", h.now)
+	h.price(t)
+
+	// Primary drops out and the symbol moves to the backup
+	h.advance(10 * time.Second)
+	h.feeds.set("backup", "101", h.now)
+	assert.Equal(t, "backup", h.price(t).Source)
+
+	// Primary recovers but has not been healthy long enough
+	h.advance(time.Second)
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "101", h.now)
+	assert.Equal(t, "backup", h.price(t).Source)
+
+	h.advance(20 * time.Second)
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "101", h.now)
+	assert.Equal(t, "backup", h.price(t).Source)
+
+	h.advance(15 * time.Second)
+	h.feeds.set("primary", "100", h.now)
+	h.feeds.set("backup", "101", h.now)
+	selection := h.price(t)
+
+	assert.Equal(t, "primary", selection.Source)
+	require.Len(t, h.arbitration.switches, 2)
+	assert.Equal(t, models.SwitchFailback, h.arbitration.switches[1].Reason)
+}
+
+func TestArbiter_ErrorsWhenNoSourceIsHealthy(t *testing.T) {
+	h := newHarness(Options{MaxAge: 5 * time.Second}, "primary", "backup")
+	h.feeds.set("primary", "100", h.now.Add(-time.Minute))
+
+	_, err := h.arbiter.Price(context.Background(), "BTC-USD")
+	assert.True(t, errors.Is(err, ErrNoHealthySource))
+
+	h.arbitration.priority = nil
+	_, err = h.arbiter.Price(context.Background(), "BTC-USD")
+	assert.Error(t, err)
+}
@@ -0,0 +1,313 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/internal/semver"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/hashring"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoInstances is returned when no discovered instance passes the configured filters
+var ErrNoInstances = errors.New("no service instances available")
+
+type Strategy string
+
+const (
+	// RoundRobin cycles through instances in a stable order, honoring weights
+	RoundRobin Strategy = "round_robin"
+
+	// Random picks a weighted random instance
+	Random Strategy = "random"
+
+	// FreshestHeartbeat picks the instance whose last heartbeat is the most recent, i.e.
+	// the one least likely to have died. Heartbeats have one-second resolution, so
+	// instances tied on the freshest second are picked at random.
+	FreshestHeartbeat Strategy = "freshest_heartbeat"
+
+	// LeastRecentlyHeartbeated picks the instance whose last heartbeat is the oldest, so
+	// callers can probe or drain the instance closest to expiring. Ties on the oldest
+	// second are picked at random.
+	LeastRecentlyHeartbeated Strategy = "least_recently_heartbeated"
+)
+
+// Filter decides whether a discovered instance is eligible for selection
+type Filter func(info *interfaces.ServiceInfo) bool
+
+// WithMetadata keeps instances whose metadata has key set to value
+func WithMetadata(key, value string) Filter {
+	return func(info *interfaces.ServiceInfo) bool {
+		return info.Metadata[key] == value
+	}
+}
+
+// WithVersion keeps instances running exactly the given version
+func WithVersion(version string) Filter {
+	return func(info *interfaces.ServiceInfo) bool {
+		return info.Version == version
+	}
+}
+
+// WithVersionConstraint keeps instances whose version satisfies a semver range
+func WithVersionConstraint(constraint string) (Filter, error) {
+	parsed, err := semver.ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+	return func(info *interfaces.ServiceInfo) bool {
+		return parsed.CheckString(info.Version)
+	}, nil
+}
+
+// WithTags keeps instances carrying every one of tags
+func WithTags(tags ...string) Filter {
+	return func(info *interfaces.ServiceInfo) bool {
+		return info.HasTags(tags...)
+	}
+}
+
+// HealthyOnly keeps instances whose health is passing
+func HealthyOnly() Filter {
+	return func(info *interfaces.ServiceInfo) bool {
+		return info.EffectiveHealth() == interfaces.HealthPassing
+	}
+}
+
+type Options struct {
+	Strategy        Strategy
+	Filters         []Filter
+	RefreshInterval time.Duration // Periodic re-discovery, in addition to watch events
+	HashReplicas    int           // Virtual nodes per instance for PickForKey
+}
+
+// Balancer keeps a cached, filtered endpoint list for one service name and picks
+// instances from it. Start keeps the list fresh from Watch events and periodic polling.
+type Balancer struct {
+	discovery   interfaces.ServiceDiscoveryRepository
+	serviceName string
+	options     Options
+	logger      *logrus.Logger
+
+	mu             sync.RWMutex
+	instances      []*interfaces.ServiceInfo
+	byID           map[string]*interfaces.ServiceInfo
+	ring           *hashring.Ring
+	currentWeights map[string]int
+}
+
+func New(discovery interfaces.ServiceDiscoveryRepository, serviceName string, options Options, logger *logrus.Logger) *Balancer {
+	if options.Strategy == "" {
+		options.Strategy = RoundRobin
+	}
+	if options.RefreshInterval <= 0 {
+		options.RefreshInterval = 30 * time.Second
+	}
+
+	return &Balancer{
+		discovery:      discovery,
+		serviceName:    serviceName,
+		options:        options,
+		logger:         logger,
+		byID:           map[string]*interfaces.ServiceInfo{},
+		ring:           hashring.New(options.HashReplicas),
+		currentWeights: map[string]int{},
+	}
+}
+
+// Start performs an initial discovery and keeps the endpoint list refreshed until ctx is done
+func (b *Balancer) Start(ctx context.Context) error {
+	if err := b.Refresh(ctx); err != nil {
+		return err
+	}
+
+	events, err := b.discovery.Watch(ctx, b.serviceName)
+	if err != nil {
+		// Polling alone still keeps the list eventually consistent
+		b.logger.WithError(err).WithField("service_name", b.serviceName).Warn("Failed to watch service, falling back to polling")
+	}
+
+	go b.refreshLoop(ctx, events)
+	return nil
+}
+
+func (b *Balancer) refreshLoop(ctx context.Context, events <-chan *interfaces.ServiceEvent) {
+	ticker := time.NewTicker(b.options.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+		}
+
+		if err := b.Refresh(ctx); err != nil {
+			b.logger.WithError(err).WithField("service_name", b.serviceName).Warn("Failed to refresh service instances")
+		}
+	}
+}
+
+// Refresh re-discovers instances and rebuilds the cached endpoint list
+func (b *Balancer) Refresh(ctx context.Context) error {
+	discovered, err := b.discovery.Discover(ctx, b.serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to discover %s: %w", b.serviceName, err)
+	}
+
+	instances := make([]*interfaces.ServiceInfo, 0, len(discovered))
+	for _, info := range discovered {
+		if b.accepts(info) {
+			instances = append(instances, info)
+		}
+	}
+	b.setInstances(instances)
+
+	return nil
+}
+
+func (b *Balancer) accepts(info *interfaces.ServiceInfo) bool {
+	for _, filter := range b.options.Filters {
+		if !filter(info) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Balancer) setInstances(instances []*interfaces.ServiceInfo) {
+	// Stable order keeps round robin fair across refreshes
+	sort.Slice(instances, func(i, j int) bool { return instances[i].ServiceID < instances[j].ServiceID })
+
+	byID := make(map[string]*interfaces.ServiceInfo, len(instances))
+	weights := make(map[string]int, len(instances))
+	for _, info := range instances {
+		byID[info.ServiceID] = info
+		weights[info.ServiceID] = info.EffectiveWeight()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.instances = instances
+	b.byID = byID
+	b.ring = hashring.NewWeighted(b.options.HashReplicas, weights)
+
+	for id := range b.currentWeights {
+		if _, ok := byID[id]; !ok {
+			delete(b.currentWeights, id)
+		}
+	}
+}
+
+// Instances returns the cached endpoint list
+func (b *Balancer) Instances() []*interfaces.ServiceInfo {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	instances := make([]*interfaces.ServiceInfo, len(b.instances))
+	copy(instances, b.instances)
+	return instances
+}
+
+// Pick selects an instance using the configured strategy
+func (b *Balancer) Pick() (*interfaces.ServiceInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.instances) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoInstances, b.serviceName)
+	}
+
+	switch b.options.Strategy {
+	case Random:
+		return b.pickWeightedRandom(), nil
+	case FreshestHeartbeat:
+		return b.pickByHeartbeat(true), nil
+	case LeastRecentlyHeartbeated:
+		return b.pickByHeartbeat(false), nil
+	default:
+		return b.pickSmoothWeighted(), nil
+	}
+}
+
+// pickWeightedRandom draws an instance with probability proportional to its weight; b.mu must be held
+func (b *Balancer) pickWeightedRandom() *interfaces.ServiceInfo {
+	total := 0
+	for _, info := range b.instances {
+		total += info.EffectiveWeight()
+	}
+
+	n := rand.IntN(total)
+	for _, info := range b.instances {
+		n -= info.EffectiveWeight()
+		if n < 0 {
+			return info
+		}
+	}
+	return b.instances[len(b.instances)-1]
+}
+
+// pickByHeartbeat draws uniformly among the instances tied on the newest (or oldest)
+// heartbeat second; b.mu must be held
+func (b *Balancer) pickByHeartbeat(newest bool) *interfaces.ServiceInfo {
+	var picked *interfaces.ServiceInfo
+	var best time.Time
+	ties := 0
+	for _, info := range b.instances {
+		heartbeat := info.LastHeartbeat.Truncate(time.Second)
+		switch {
+		case picked == nil || (newest && heartbeat.After(best)) || (!newest && heartbeat.Before(best)):
+			picked, best, ties = info, heartbeat, 1
+		case heartbeat.Equal(best):
+			// Reservoir sampling keeps each tied instance equally likely
+			ties++
+			if rand.IntN(ties) == 0 {
+				picked = info
+			}
+		}
+	}
+	return picked
+}
+
+// pickSmoothWeighted is nginx-style smooth weighted round robin: equal weights cycle in
+// order, heavier instances are picked proportionally more often without bursts; b.mu must be held
+func (b *Balancer) pickSmoothWeighted() *interfaces.ServiceInfo {
+	total := 0
+	var best *interfaces.ServiceInfo
+	for _, info := range b.instances {
+		weight := info.EffectiveWeight()
+		b.currentWeights[info.ServiceID] += weight
+		total += weight
+
+		if best == nil || b.currentWeights[info.ServiceID] > b.currentWeights[best.ServiceID] {
+			best = info
+		}
+	}
+
+	b.currentWeights[best.ServiceID] -= total
+	return best
+}
+
+// PickForKey selects the instance owning key on a consistent hash ring, so the same
+// key keeps landing on the same instance while membership is stable
+func (b *Balancer) PickForKey(key string) (*interfaces.ServiceInfo, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	id, ok := b.ring.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoInstances, b.serviceName)
+	}
+	return b.byID[id], nil
+}
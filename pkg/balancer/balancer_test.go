@@ -0,0 +1,191 @@
+package balancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDiscovery serves a fixed instance list; unused methods fall through to the nil interface
+type staticDiscovery struct {
+	interfaces.ServiceDiscoveryRepository
+	instances []*interfaces.ServiceInfo
+}
+
+func (s *staticDiscovery) Discover(ctx context.Context, serviceName string) ([]*interfaces.ServiceInfo, error) {
+	var result []*interfaces.ServiceInfo
+	for _, info := range s.instances {
+		if info.ServiceName == serviceName {
+			result = append(result, info)
+		}
+	}
+	return result, nil
+}
+
+func newTestBalancer(t *testing.T, options Options, instances ...*interfaces.ServiceInfo) *Balancer {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	b := New(&staticDiscovery{instances: instances}, "market-data-simulator", options, logger)
+	require.NoError(t, b.Refresh(context.Background()))
+	return b
+}
+
+func instance(id, version string, heartbeat time.Time) *interfaces.ServiceInfo {
+	return &interfaces.ServiceInfo{
+		ServiceName:   "market-data-simulator",
+		ServiceID:     id,
+		Version:       version,
+		Metadata:      map[string]string{"region": "eu"},
+		LastHeartbeat: heartbeat,
+	}
+}
+
+func TestBalancer_RoundRobinCyclesInstances(t *testing.T) {
+	now := time.Now()
+	b := newTestBalancer(t, Options{Strategy: RoundRobin},
+		instance("b", "1.0.0", now), instance("a", "1.0.0", now), instance("c", "1.0.0", now))
+
+	var picked []string
+	for i := 0; i < 6; i++ {
+		info, err := b.Pick()
+		require.NoError(t, err)
+		picked = append(picked, info.ServiceID)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+}
+
+func TestBalancer_FreshestHeartbeatPicksFreshest(t *testing.T) {
+	now := time.Now()
+	b := newTestBalancer(t, Options{Strategy: FreshestHeartbeat},
+		instance("a", "1.0.0", now.Add(-time.Minute)),
+		instance("b", "1.0.0", now),
+		instance("c", "1.0.0", now.Add(-30*time.Second)))
+
+	info, err := b.Pick()
+
+	require.NoError(t, err)
+	assert.Equal(t, "b", info.ServiceID)
+}
+
+func TestBalancer_FreshestHeartbeatSpreadsTies(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	b := newTestBalancer(t, Options{Strategy: FreshestHeartbeat},
+		instance("a", "1.0.0", now),
+		instance("b", "1.0.0", now.Add(300*time.Millisecond)),
+		instance("c", "1.0.0", now.Add(-time.Minute)))
+
+	picked := map[string]int{}
+	for i := 0; i < 200; i++ {
+		info, err := b.Pick()
+		require.NoError(t, err)
+		picked[info.ServiceID]++
+	}
+
+	assert.Positive(t, picked["a"], "Instances heartbeating in the same second should share traffic")
+	assert.Positive(t, picked["b"])
+	assert.Zero(t, picked["c"])
+}
+
+func TestBalancer_LeastRecentlyHeartbeatedPicksOldest(t *testing.T) {
+	now := time.Now()
+	b := newTestBalancer(t, Options{Strategy: LeastRecentlyHeartbeated},
+		instance("a", "1.0.0", now.Add(-30*time.Second)),
+		instance("b", "1.0.0", now.Add(-time.Minute)),
+		instance("c", "1.0.0", now))
+
+	info, err := b.Pick()
+
+	require.NoError(t, err)
+	assert.Equal(t, "b", info.ServiceID)
+}
+
+func TestBalancer_FiltersByVersionAndMetadata(t *testing.T) {
+	now := time.Now()
+	other := instance("c", "2.0.0", now)
+	other.Metadata = map[string]string{"region": "us"}
+
+	b := newTestBalancer(t, Options{
+		Strategy: Random,
+		Filters:  []Filter{WithVersion("1.0.0"), WithMetadata("region", "eu")},
+	}, instance("a", "1.0.0", now), instance("b", "2.0.0", now), other)
+
+	instances := b.Instances()
+
+	require.Len(t, instances, 1)
+	assert.Equal(t, "a", instances[0].ServiceID)
+}
+
+func TestBalancer_PickForKeyIsSticky(t *testing.T) {
+	now := time.Now()
+	b := newTestBalancer(t, Options{},
+		instance("a", "1.0.0", now), instance("b", "1.0.0", now), instance("c", "1.0.0", now))
+
+	first, err := b.PickForKey("BTC-USD")
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		again, err := b.PickForKey("BTC-USD")
+		require.NoError(t, err)
+		assert.Equal(t, first.ServiceID, again.ServiceID)
+	}
+}
+
+func TestBalancer_NoInstances(t *testing.T) {
+	b := newTestBalancer(t, Options{})
+
+	_, err := b.Pick()
+	assert.ErrorIs(t, err, ErrNoInstances)
+
+	_, err = b.PickForKey("BTC-USD")
+	assert.ErrorIs(t, err, ErrNoInstances)
+}
+
+func TestBalancer_RoundRobinHonorsWeights(t *testing.T) {
+	now := time.Now()
+	heavy := instance("a", "1.0.0", now)
+	heavy.Weight = 3
+	b := newTestBalancer(t, Options{Strategy: RoundRobin}, heavy, instance("b", "1.0.0", now))
+
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		info, err := b.Pick()
+		require.NoError(t, err)
+		counts[info.ServiceID]++
+	}
+
+	assert.Equal(t, map[string]int{"a": 6, "b": 2}, counts)
+}
+
+func TestBalancer_HealthTagAndVersionConstraintFilters(t *testing.T) {
+	now := time.Now()
+	degraded := instance("a", "1.4.0", now)
+	degraded.Health = interfaces.HealthWarning
+	degraded.Tags = []string{"primary"}
+
+	healthy := instance("b", "1.5.0", now)
+	healthy.Tags = []string{"primary"}
+
+	tooNew := instance("c", "2.0.0", now)
+	tooNew.Tags = []string{"primary"}
+
+	untagged := instance("d", "1.5.0", now)
+
+	versionFilter, err := WithVersionConstraint("^1.2")
+	require.NoError(t, err)
+
+	b := newTestBalancer(t, Options{
+		Filters: []Filter{HealthyOnly(), WithTags("primary"), versionFilter},
+	}, degraded, healthy, tooNew, untagged)
+
+	instances := b.Instances()
+
+	require.Len(t, instances, 1)
+	assert.Equal(t, "b", instances[0].ServiceID)
+}
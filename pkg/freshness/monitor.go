@@ -0,0 +1,336 @@
+package freshness
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+type Kind string
+
+const (
+	KindPriceFeed Kind = "price_feed"
+	KindSnapshot  Kind = "snapshot"
+)
+
+type EventType string
+
+const (
+	EventStale     EventType = "stale"
+	EventRecovered EventType = "recovered"
+	EventExpired   EventType = "expired" // A stale entry stopped being tracked
+)
+
+type Options struct {
+	DefaultSLA    time.Duration            // Maximum age of the latest update; defaults to 30s
+	SymbolSLAs    map[string]time.Duration // Per-symbol overrides
+	SourceSLAs    map[string]time.Duration // Per-source overrides for price feeds
+	CheckInterval time.Duration            // Defaults to 10s
+	Lookback      time.Duration            // How far back the first check looks for symbols; defaults to 1h
+	Expiry        time.Duration            // Stale entries not updated for this long are dropped; defaults to 1h
+	Symbols       []string                 // Symbols whose snapshots are expected even if none were seen within Lookback
+	OnEvent       func(Event)              // Called for every stale, recovered and expired transition
+}
+
+// Entry is the freshness of one symbol's price feeds from one source, or of its snapshots
+type Entry struct {
+	Kind       Kind          `json:"kind"`
+	Symbol     string        `json:"symbol"`
+	Source     string        `json:"source,omitempty"`
+	LastUpdate time.Time     `json:"last_update"` // Zero if an expected symbol was never seen
+	Age        time.Duration `json:"age"`
+	SLA        time.Duration `json:"sla"`
+	Stale      bool          `json:"stale"`
+	StaleSince time.Time     `json:"stale_since,omitempty"`
+}
+
+type Event struct {
+	Type  EventType `json:"type"`
+	Entry Entry     `json:"entry"`
+}
+
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Entries     []Entry   `json:"entries"`
+	Stale       int       `json:"stale"`
+}
+
+type entryKey struct {
+	kind   Kind
+	symbol string
+	source string
+}
+
+// Monitor periodically compares the latest price feed and snapshot times against their
+// SLAs and reports transitions between fresh and stale. Once seen, an entry is tracked
+// until it has gone Expiry without an update, so a symbol that stops updating entirely
+// still goes stale, while a retired source or delisted symbol eventually stops counting.
+// Expected Symbols are never dropped.
+type Monitor struct {
+	feeds     interfaces.PriceFeedRepository
+	snapshots interfaces.MarketSnapshotRepository
+	options   Options
+	logger    *logrus.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	entries   map[entryKey]*Entry
+	report    *Report
+	lastCheck time.Time
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+func NewMonitor(feeds interfaces.PriceFeedRepository, snapshots interfaces.MarketSnapshotRepository, options Options, logger *logrus.Logger) *Monitor {
+	if options.DefaultSLA <= 0 {
+		options.DefaultSLA = 30 * time.Second
+	}
+	if options.CheckInterval <= 0 {
+		options.CheckInterval = 10 * time.Second
+	}
+	if options.Lookback <= 0 {
+		options.Lookback = time.Hour
+	}
+	if options.Expiry <= 0 {
+		options.Expiry = time.Hour
+	}
+
+	return &Monitor{
+		feeds:     feeds,
+		snapshots: snapshots,
+		options:   options,
+		logger:    logger,
+		now:       time.Now,
+		entries:   make(map[entryKey]*Entry),
+	}
+}
+
+func (m *Monitor) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return fmt.Errorf("freshness monitor already started")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.checkLoop(loopCtx, m.done)
+
+	m.logger.WithFields(logrus.Fields{
+		"default_sla":    m.options.DefaultSLA,
+		"check_interval": m.options.CheckInterval,
+	}).Info("Freshness monitor started")
+	return nil
+}
+
+func (m *Monitor) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	m.logger.Info("Freshness monitor stopped")
+	return nil
+}
+
+func (m *Monitor) checkLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.options.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			m.logger.WithError(err).Warn("Freshness check failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report returns the result of the last check, or nil before the first one
+func (m *Monitor) Report() *Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.report
+}
+
+// Check reads the latest update times, refreshes every entry and returns the new report
+func (m *Monitor) Check(ctx context.Context) (*Report, error) {
+	now := m.now()
+
+	m.mu.Lock()
+	since := now.Add(-m.options.Lookback)
+	if !m.lastCheck.IsZero() {
+		since = m.lastCheck.Add(-m.maxSLA())
+	}
+	m.mu.Unlock()
+
+	feeds, err := m.feeds.GetLatestTimestamps(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := m.snapshots.GetLatestTimestamps(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	expected := make(map[string]bool, len(m.options.Symbols))
+	for _, symbol := range m.options.Symbols {
+		expected[symbol] = true
+	}
+	for _, latest := range feeds {
+		m.observe(entryKey{kind: KindPriceFeed, symbol: latest.Symbol, source: latest.Source}, latest.Timestamp)
+	}
+	for _, latest := range snapshots {
+		m.observe(entryKey{kind: KindSnapshot, symbol: latest.Symbol}, latest.Timestamp)
+	}
+	for _, symbol := range m.options.Symbols {
+		key := entryKey{kind: KindSnapshot, symbol: symbol}
+		if _, ok := m.entries[key]; !ok {
+			m.entries[key] = &Entry{Kind: KindSnapshot, Symbol: symbol}
+		}
+	}
+
+	var events []Event
+	report := &Report{GeneratedAt: now}
+	for key, entry := range m.entries {
+		entry.SLA = m.sla(key)
+		stale := entry.LastUpdate.IsZero()
+		if !stale {
+			entry.Age = now.Sub(entry.LastUpdate)
+			stale = entry.Age > entry.SLA
+		}
+
+		if stale && entry.Age > m.options.Expiry && !(key.kind == KindSnapshot && expected[key.symbol]) {
+			// Only entries that were reported stale need an event to clear them
+			if entry.Stale {
+				events = append(events, Event{Type: EventExpired, Entry: *entry})
+			}
+			delete(m.entries, key)
+			continue
+		}
+
+		switch {
+		case stale && !entry.Stale:
+			entry.Stale, entry.StaleSince = true, now
+			events = append(events, Event{Type: EventStale, Entry: *entry})
+		case !stale && entry.Stale:
+			entry.Stale, entry.StaleSince = false, time.Time{}
+			events = append(events, Event{Type: EventRecovered, Entry: *entry})
+		}
+
+		if entry.Stale {
+			report.Stale++
+		}
+		report.Entries = append(report.Entries, *entry)
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Source < b.Source
+	})
+
+	m.report = report
+	m.lastCheck = now
+	m.mu.Unlock()
+
+	for _, event := range events {
+		m.emit(event)
+	}
+	return report, nil
+}
+
+// observe records an update time; it never moves backwards, since later checks query a
+// shorter window that may not include the latest update
+func (m *Monitor) observe(key entryKey, timestamp time.Time) {
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &Entry{Kind: key.kind, Symbol: key.symbol, Source: key.source}
+		m.entries[key] = entry
+	}
+	if timestamp.After(entry.LastUpdate) {
+		entry.LastUpdate = timestamp
+	}
+}
+
+// sla picks the strictest SLA configured for the entry's symbol and source
+func (m *Monitor) sla(key entryKey) time.Duration {
+	var sla time.Duration
+	if symbolSLA, ok := m.options.SymbolSLAs[key.symbol]; ok {
+		sla = symbolSLA
+	}
+	if sourceSLA, ok := m.options.SourceSLAs[key.source]; ok && key.source != "" && (sla == 0 || sourceSLA < sla) {
+		sla = sourceSLA
+	}
+	if sla <= 0 {
+		return m.options.DefaultSLA
+	}
+	return sla
+}
+
+func (m *Monitor) maxSLA() time.Duration {
+	longest := m.options.DefaultSLA
+	for _, sla := range m.options.SymbolSLAs {
+		if sla > longest {
+			longest = sla
+		}
+	}
+	for _, sla := range m.options.SourceSLAs {
+		if sla > longest {
+			longest = sla
+		}
+	}
+	return longest
+}
+
+func (m *Monitor) emit(event Event) {
+	logger := m.logger.WithFields(logrus.Fields{
+		"kind":        event.Entry.Kind,
+		"symbol":      event.Entry.Symbol,
+		"source":      event.Entry.Source,
+		"last_update": event.Entry.LastUpdate,
+		"sla":         event.Entry.SLA,
+	})
+	switch event.Type {
+	case EventStale:
+		logger.Warn("Feed is stale")
+	case EventExpired:
+		logger.Info("Stopped tracking stale feed")
+	default:
+		logger.Info("Feed recovered")
+	}
+
+	if m.options.OnEvent != nil {
+		m.options.OnEvent(event)
+	}
+}
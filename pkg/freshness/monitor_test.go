@@ -0,0 +1,168 @@
+package freshness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeeds struct {
+	interfaces.PriceFeedRepository
+	latest []*models.LatestTimestamp
+}
+
+func (f *fakeFeeds) GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error) {
+	var result []*models.LatestTimestamp
+	for _, latest := range f.latest {
+		if !latest.Timestamp.Before(since) {
+			result = append(result, latest)
+		}
+	}
+	return result, nil
+}
+
+type fakeSnapshots struct {
+	interfaces.MarketSnapshotRepository
+	latest []*models.LatestTimestamp
+}
+
+func (f *fakeSnapshots) GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error) {
+	return f.latest, nil
+}
+
+func newTestMonitor(options Options) (*Monitor, *fakeFeeds, *fakeSnapshots, *[]Event, *time.Time) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	var events []Event
+	options.OnEvent = func(event Event) { events = append(events, event) }
+
+	feeds, snapshots := &fakeFeeds{}, &fakeSnapshots{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	monitor := NewMonitor(feeds, snapshots, options, logger)
+	monitor.now = func() time.Time { return now }
+	return monitor, feeds, snapshots, &events, &now
+}
+
+func TestMonitor_RaisesStaleAndRecoveredEvents(t *testing.T) {
+	monitor, feeds, _, events, now := newTestMonitor(Options{DefaultSLA: 10 * time.Second})
+	feeds.latest = []*models.LatestTimestamp{{Symbol: "BTC-USD", Source: "coinbase", Timestamp: *now}}
+
+	report, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Stale)
+	assert.Empty(t, *events)
+
+	// The source stops updating; later checks no longer see its rows at all
+	*now = now.Add(30 * time.Second)
+	feeds.latest = nil
+	report, err = monitor.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Stale)
+	require.Len(t, *events, 1)
+	assert.Equal(t, EventStale, (*events)[0].Type)
+	assert.Equal(t, "coinbase", (*events)[0].Entry.Source)
+
+	// Still stale: no repeated event
+	*now = now.Add(10 * time.Second)
+	_, err = monitor.Check(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, *events, 1)
+
+	feeds.latest = []*models.LatestTimestamp{{Symbol: "BTC-USD", Source: "coinbase", Timestamp: *now}}
+	report, err = monitor.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Stale)
+	require.Len(t, *events, 2)
+	assert.Equal(t, EventRecovered, (*events)[1].Type)
+	assert.Same(t, report, monitor.Report())
+}
+
+func TestMonitor_UsesStrictestSLA(t *testing.T) {
+	monitor, feeds, _, _, now := newTestMonitor(Options{
+		DefaultSLA: time.Minute,
+		SymbolSLAs: map[string]time.Duration{"BTC-USD": 20 * time.Second},
+		SourceSLAs: map[string]time.Duration{"kraken": 5 * time.Second},
+	})
+	feeds.latest = []*models.LatestTimestamp{
+		{Symbol: "BTC-USD", Source: "coinbase", Timestamp: now.Add(-10 * time.Second)},
+		{Symbol: "BTC-USD", Source: "kraken", Timestamp: now.Add(-10 * time.Second)},
+		{Symbol: "ETH-USD", Source: "coinbase", Timestamp: now.Add(-30 * time.Second)},
+	}
+
+	report, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+
+	stale := map[string]bool{}
+	for _, entry := range report.Entries {
+		stale[entry.Symbol+"/"+entry.Source] = entry.Stale
+	}
+	assert.Equal(t, map[string]bool{
+		"BTC-USD/coinbase": false,
+		"BTC-USD/kraken":   true,
+		"ETH-USD/coinbase": false,
+	}, stale)
+}
+
+func TestMonitor_ExpectedSymbolsNeverSeenAreStale(t *testing.T) {
+	monitor, _, snapshots, events, now := newTestMonitor(Options{Symbols: []string{"BTC-USD", "ETH-USD"}})
+	snapshots.latest = []*models.LatestTimestamp{{Symbol: "BTC-USD", Timestamp: *now}}
+
+	report, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Stale)
+	require.Len(t, *events, 1)
+	assert.Equal(t, "ETH-USD", (*events)[0].Entry.Symbol)
+	assert.Equal(t, KindSnapshot, (*events)[0].Entry.Kind)
+}
+
+func TestMonitor_RetiredSourceStopsCountingAfterExpiry(t *testing.T) {
+	monitor, feeds, _, events, now := newTestMonitor(Options{DefaultSLA: 10 * time.Second, Expiry: time.Minute})
+	feeds.latest = []*models.LatestTimestamp{
+		{Symbol: "BTC-USD", Source: "coinbase", Timestamp: *now},
+		{Symbol: "BTC-USD", Source: "retired", Timestamp: *now},
+	}
+	_, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+
+	// The retired source never updates again
+	*now = now.Add(30 * time.Second)
+	feeds.latest = []*models.LatestTimestamp{{Symbol: "BTC-USD", Source: "coinbase", Timestamp: *now}}
+	report, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Stale)
+
+	*now = now.Add(time.Minute)
+	feeds.latest = []*models.LatestTimestamp{{Symbol: "BTC-USD", Source: "coinbase", Timestamp: *now}}
+	report, err = monitor.Check(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, report.Stale, "An expired source must not keep the service degraded")
+	require.Len(t, report.Entries, 1)
+	assert.Equal(t, "coinbase", report.Entries[0].Source)
+	require.Len(t, *events, 2)
+	assert.Equal(t, EventExpired, (*events)[1].Type)
+	assert.Equal(t, "retired", (*events)[1].Entry.Source)
+}
+
+func TestMonitor_FirstCheckIgnoresSourcesAlreadyExpired(t *testing.T) {
+	monitor, feeds, _, events, now := newTestMonitor(Options{DefaultSLA: 10 * time.Second, Expiry: 10 * time.Minute, Symbols: []string{"ETH-USD"}})
+	// Within the one-hour lookback, but retired longer ago than the expiry, as after a restart
+	feeds.latest = []*models.LatestTimestamp{{Symbol: "BTC-USD", Source: "retired", Timestamp: now.Add(-30 * time.Minute)}}
+
+	report, err := monitor.Check(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Entries, 1, "Expected symbols are tracked regardless of expiry")
+	assert.Equal(t, "ETH-USD", report.Entries[0].Symbol)
+	require.Len(t, *events, 1)
+	assert.Equal(t, EventStale, (*events)[0].Type)
+	assert.Equal(t, "ETH-USD", (*events)[0].Entry.Symbol)
+}
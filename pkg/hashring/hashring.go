@@ -0,0 +1,105 @@
+package hashring
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// DefaultReplicas is the number of virtual nodes placed on the ring per member
+const DefaultReplicas = 128
+
+// Ring is an immutable consistent hash ring; build a new ring when membership changes
+type Ring struct {
+	hashes  []uint64
+	owners  map[uint64]string
+	members []string
+}
+
+// New places each member on the ring `replicas` times
+func New(replicas int, members ...string) *Ring {
+	weights := make(map[string]int, len(members))
+	for _, member := range members {
+		weights[member] = 1
+	}
+	return NewWeighted(replicas, weights)
+}
+
+// NewWeighted places each member on the ring `replicas * weight` times, so a member
+// with weight 2 owns roughly twice the keys of a member with weight 1
+func NewWeighted(replicas int, weights map[string]int) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+
+	ring := &Ring{
+		owners: make(map[uint64]string, len(weights)*replicas),
+	}
+
+	for member, weight := range weights {
+		if weight <= 0 {
+			weight = 1
+		}
+		ring.members = append(ring.members, member)
+
+		for i := 0; i < replicas*weight; i++ {
+			h := hashKey(member + "#" + strconv.Itoa(i))
+			// On the rare collision keep the lexically smaller owner so all nodes agree
+			if owner, exists := ring.owners[h]; exists && owner < member {
+				continue
+			}
+			ring.owners[h] = member
+		}
+	}
+
+	ring.hashes = make([]uint64, 0, len(ring.owners))
+	for h := range ring.owners {
+		ring.hashes = append(ring.hashes, h)
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+	sort.Strings(ring.members)
+
+	return ring
+}
+
+// Get returns the member owning key, or false if the ring is empty
+func (r *Ring) Get(key string) (string, bool) {
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+
+	h := hashKey(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+
+	return r.owners[r.hashes[idx]], true
+}
+
+// Members returns the distinct members in sorted order
+func (r *Ring) Members() []string {
+	members := make([]string, len(r.members))
+	copy(members, r.members)
+	return members
+}
+
+// Len returns the number of distinct members
+func (r *Ring) Len() int {
+	return len(r.members)
+}
+
+// hashKey is FNV-1a followed by the murmur3 finalizer; FNV alone clusters badly on
+// short keys that differ only in their suffix, like "node#1", "node#2"
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,69 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRing_EmptyRingHasNoOwner(t *testing.T) {
+	ring := New(DefaultReplicas)
+
+	_, ok := ring.Get("BTC-USD")
+
+	assert.False(t, ok, "Empty ring should not resolve any key")
+}
+
+func TestRing_AssignmentIsDeterministic(t *testing.T) {
+	first := New(DefaultReplicas, "a", "b", "c")
+	second := New(DefaultReplicas, "c", "a", "b")
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("SYM%d-USD", i)
+		owner1, _ := first.Get(key)
+		owner2, _ := second.Get(key)
+		assert.Equal(t, owner1, owner2, "Member order must not affect assignment")
+	}
+}
+
+func TestRing_RemovingMemberOnlyMovesItsKeys(t *testing.T) {
+	before := New(DefaultReplicas, "a", "b", "c")
+	after := New(DefaultReplicas, "a", "b")
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("SYM%d-USD", i)
+		ownerBefore, _ := before.Get(key)
+		ownerAfter, _ := after.Get(key)
+		if ownerBefore != "c" {
+			assert.Equal(t, ownerBefore, ownerAfter, "Keys not owned by the removed member must stay put")
+		}
+	}
+}
+
+func TestRing_SpreadsKeysAcrossMembers(t *testing.T) {
+	ring := New(DefaultReplicas, "a", "b", "c", "d")
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		owner, _ := ring.Get(fmt.Sprintf("SYM%d-USD", i))
+		counts[owner]++
+	}
+
+	assert.Len(t, counts, 4)
+	for member, count := range counts {
+		assert.InDelta(t, 1000, count, 400, "Member %s should own roughly a quarter of keys", member)
+	}
+}
+
+func TestRing_WeightsSkewOwnership(t *testing.T) {
+	ring := NewWeighted(DefaultReplicas, map[string]int{"a": 3, "b": 1})
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		owner, _ := ring.Get(fmt.Sprintf("SYM%d-USD", i))
+		counts[owner]++
+	}
+
+	assert.Greater(t, counts["a"], 2*counts["b"], "Heavier member should own most keys")
+}
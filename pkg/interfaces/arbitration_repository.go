@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type ArbitrationRepository interface {
+	// Get a symbol's feed sources, highest priority first
+	GetSourcePriority(ctx context.Context, symbol string) ([]string, error)
+
+	// Replace a symbol's feed sources, highest priority first
+	SetSourcePriority(ctx context.Context, symbol string, sources []string) error
+
+	// Record a source switch
+	RecordSwitch(ctx context.Context, event *models.SourceSwitchEvent) error
+
+	// Get recent source switches for a symbol, newest first
+	GetSwitches(ctx context.Context, symbol string, limit int) ([]*models.SourceSwitchEvent, error)
+}
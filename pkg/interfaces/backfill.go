@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type BackfillCheckpointRepository interface {
+	// Get the checkpoint for a symbol and interval, or ErrNotFound
+	Get(ctx context.Context, symbol string, interval models.CandleInterval) (*models.BackfillCheckpoint, error)
+
+	// Create or replace the checkpoint for a symbol and interval
+	Save(ctx context.Context, checkpoint *models.BackfillCheckpoint) error
+
+	// Delete the checkpoint once a backfill completes
+	Delete(ctx context.Context, symbol string, interval models.CandleInterval) error
+}
+
+// HistoricalDataProvider fetches candles from an external vendor to fill gaps that stored
+// data cannot
+type HistoricalDataProvider interface {
+	Name() string
+
+	// Fetch candles starting within [from, to); candles the provider lacks are omitted
+	FetchCandles(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) ([]*models.Candle, error)
+}
@@ -23,6 +23,12 @@ type CandleRepository interface {
 	// Get latest candle for symbol and interval
 	GetLatest(ctx context.Context, symbol string, interval models.CandleInterval) (*models.Candle, error)
 
+	// Get the oldest candle start time per symbol among candles of one interval before the given time
+	GetOldestIntervalTimestamps(ctx context.Context, interval models.CandleInterval, before time.Time) ([]*models.LatestTimestamp, error)
+
 	// Delete old candles (cleanup)
 	DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error)
+
+	// Delete old candles of one interval (tiered retention)
+	DeleteIntervalOlderThan(ctx context.Context, interval models.CandleInterval, timestamp time.Time) (int64, error)
 }
@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+type ChangeOperation string
+
+const (
+	ChangeInsert ChangeOperation = "INSERT"
+	ChangeUpdate ChangeOperation = "UPDATE"
+	ChangeDelete ChangeOperation = "DELETE"
+)
+
+// ChangeEvent describes one changed row in a market-data table
+type ChangeEvent struct {
+	Table     string          `json:"table"`
+	Operation ChangeOperation `json:"operation"`
+	ID        string          `json:"id"`
+	Symbol    string          `json:"symbol,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+
+	// Resynced events are reconstructed from row timestamps after a reconnect rather than
+	// received as notifications; deletes made while disconnected cannot be recovered
+	Resynced bool `json:"resynced,omitempty"`
+}
+
+type ChangeFeed interface {
+	// Subscribe to change events; the channel closes when ctx is done or the feed stops
+	Subscribe(ctx context.Context) (<-chan *ChangeEvent, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type DataQualityRepository interface {
+	// Record a data-quality event
+	Create(ctx context.Context, event *models.DataQualityEvent) error
+
+	// Query data-quality events with filters, newest first
+	Query(ctx context.Context, query *models.DataQualityQuery) ([]*models.DataQualityEvent, error)
+
+	// Delete old data-quality events (cleanup)
+	DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "errors"
+
+// ErrNotFound is wrapped by repositories when the requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrDegraded is wrapped by health checks when the service works but serves degraded data
+var ErrDegraded = errors.New("degraded")
+
+// ErrAnomalousPrice is wrapped when an anomaly filter rejects a price feed
+var ErrAnomalousPrice = errors.New("anomalous price")
+
+// ErrOutsidePartitions is wrapped when a row's time has no partition to be stored in
+var ErrOutsidePartitions = errors.New("outside partitions")
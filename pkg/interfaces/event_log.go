@@ -0,0 +1,80 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type EventStream string
+
+const (
+	StreamPriceFeeds EventStream = "price_feeds"
+	StreamCandles    EventStream = "candles"
+	StreamSnapshots  EventStream = "snapshots"
+)
+
+type LogEvent struct {
+	ID        string // Assigned by the log on append
+	EventID   string // Outbox event ID when relayed from the outbox; stable across redeliveries
+	Stream    EventStream
+	Symbol    string
+	Payload   json.RawMessage
+	Timestamp time.Time
+}
+
+// Decode unmarshals the payload into v, e.g. a *models.PriceFeed for StreamPriceFeeds
+func (e *LogEvent) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", e.Stream, e.ID, err)
+	}
+	return nil
+}
+
+func NewPriceFeedEvent(feed *models.PriceFeed) (*LogEvent, error) {
+	return newLogEvent(StreamPriceFeeds, feed.Symbol, feed)
+}
+
+func NewCandleEvent(candle *models.Candle) (*LogEvent, error) {
+	return newLogEvent(StreamCandles, candle.Symbol, candle)
+}
+
+func NewSnapshotEvent(snapshot *models.MarketSnapshot) (*LogEvent, error) {
+	return newLogEvent(StreamSnapshots, snapshot.Symbol, snapshot)
+}
+
+func newLogEvent(stream EventStream, symbol string, v interface{}) (*LogEvent, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", stream, err)
+	}
+	return &LogEvent{
+		Stream:    stream,
+		Symbol:    symbol,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+type EventLog interface {
+	// Append an event to its stream, trimming the stream to the configured max length; returns the entry ID
+	Append(ctx context.Context, event *LogEvent) (string, error)
+
+	// Create a consumer group reading from the start of the stream (no-op if it exists)
+	EnsureGroup(ctx context.Context, stream EventStream, group string) error
+
+	// Read new events for a consumer, waiting up to block for at least one (no wait if block <= 0)
+	Read(ctx context.Context, stream EventStream, group, consumer string, count int, block time.Duration) ([]*LogEvent, error)
+
+	// Acknowledge processed events so they leave the group's pending list
+	Ack(ctx context.Context, stream EventStream, group string, ids ...string) error
+
+	// Take over events pending longer than minIdle, e.g. from a crashed consumer
+	Claim(ctx context.Context, stream EventStream, group, consumer string, minIdle time.Duration, count int) ([]*LogEvent, error)
+
+	// Trim a stream to at most maxLen entries; returns the number removed
+	Trim(ctx context.Context, stream EventStream, maxLen int64) (int64, error)
+}
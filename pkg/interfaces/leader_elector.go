@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+)
+
+type LeaderCallbacks struct {
+	// Called when this instance becomes leader; ctx is cancelled when leadership is lost
+	OnStartedLeading func(ctx context.Context)
+
+	// Called when this instance stops being leader
+	OnStoppedLeading func()
+
+	// Called whenever a different leader is observed (including this instance)
+	OnNewLeader func(identity string)
+}
+
+type LeaderElector interface {
+	// Campaign for leadership until ctx is cancelled, resigning on exit
+	Run(ctx context.Context) error
+
+	// Check whether this instance currently holds leadership
+	IsLeader() bool
+
+	// Get the identity of the current leader
+	Leader(ctx context.Context) (string, error)
+
+	// Get this instance's identity
+	Identity() string
+
+	// Give up leadership if held
+	Resign(ctx context.Context) error
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrLockNotAcquired is returned when the lock is already held by another owner
+	ErrLockNotAcquired = errors.New("lock not acquired")
+
+	// ErrLockNotHeld is returned when releasing or renewing a lock that expired or changed owner
+	ErrLockNotHeld = errors.New("lock not held")
+)
+
+type Lock struct {
+	Key          string
+	Token        string
+	FencingToken int64
+	AcquiredAt   time.Time
+	ExpiresAt    time.Time
+}
+
+type Locker interface {
+	// Acquire a lock for the given TTL, returns ErrLockNotAcquired if held elsewhere
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error)
+
+	// Release a held lock
+	Release(ctx context.Context, lock *Lock) error
+
+	// Extend a held lock's TTL
+	Renew(ctx context.Context, lock *Lock, ttl time.Duration) error
+}
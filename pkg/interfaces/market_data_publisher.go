@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type MarketDataUpdateType string
+
+const (
+	UpdatePriceFeed MarketDataUpdateType = "price_feed"
+	UpdateSnapshot  MarketDataUpdateType = "snapshot"
+)
+
+// MarketDataUpdate carries exactly one of PriceFeed or Snapshot, according to Type
+type MarketDataUpdate struct {
+	Type      MarketDataUpdateType   `json:"type"`
+	EventID   string                 `json:"event_id,omitempty"` // Set when relayed from the outbox
+	Symbol    string                 `json:"symbol"`
+	PriceFeed *models.PriceFeed      `json:"price_feed,omitempty"`
+	Snapshot  *models.MarketSnapshot `json:"snapshot,omitempty"`
+}
+
+type MarketDataPublisher interface {
+	// Publish a new price feed to its symbol's subscribers
+	PublishPriceFeed(ctx context.Context, feed *models.PriceFeed) error
+
+	// Publish a new market snapshot to its symbol's subscribers
+	PublishSnapshot(ctx context.Context, snapshot *models.MarketSnapshot) error
+
+	// Publish a prepared update, e.g. one carrying an outbox event ID
+	Publish(ctx context.Context, update *MarketDataUpdate) error
+
+	// Subscribe to updates for the given symbols (all symbols if none); the channel closes when ctx is done
+	Subscribe(ctx context.Context, symbols ...string) (<-chan *MarketDataUpdate, error)
+}
@@ -23,6 +23,9 @@ type MarketSnapshotRepository interface {
 	// Query snapshots with filters
 	Query(ctx context.Context, query *models.MarketSnapshotQuery) ([]*models.MarketSnapshot, error)
 
+	// Get the latest snapshot time per symbol among snapshots since the given time
+	GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error)
+
 	// Delete old snapshots (cleanup)
 	DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error)
 }
@@ -0,0 +1,49 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type OutboxAggregate string
+
+const (
+	AggregatePriceFeed OutboxAggregate = "price_feed"
+	AggregateCandle    OutboxAggregate = "candle"
+	AggregateSnapshot  OutboxAggregate = "snapshot"
+	AggregateSymbol    OutboxAggregate = "symbol"
+)
+
+type OutboxOperation string
+
+const (
+	OperationCreated OutboxOperation = "created"
+	OperationUpdated OutboxOperation = "updated"
+	OperationDeleted OutboxOperation = "deleted"
+)
+
+// OutboxEvent is a repository write recorded in the same transaction as the write itself.
+// Delivery is at-least-once; EventID stays the same across redeliveries so consumers can dedupe.
+type OutboxEvent struct {
+	EventID     string          `json:"event_id"`
+	Aggregate   OutboxAggregate `json:"aggregate"`
+	AggregateID string          `json:"aggregate_id"`
+	Operation   OutboxOperation `json:"operation"`
+	Symbol      string          `json:"symbol,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// EventSink receives outbox events from the relay; an error leaves the event pending for retry
+// until the relay's attempt limit dead-letters it
+type EventSink interface {
+	Deliver(ctx context.Context, event *OutboxEvent) error
+}
+
+// EventSinkFunc adapts a callback to EventSink
+type EventSinkFunc func(ctx context.Context, event *OutboxEvent) error
+
+func (f EventSinkFunc) Deliver(ctx context.Context, event *OutboxEvent) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// PartitionDropper removes whole time partitions of a table
+type PartitionDropper interface {
+	// Drop partitions ending at or before cutoff, returning the rows they held and the end
+	// of the newest one dropped, or zero if none were
+	DropBefore(ctx context.Context, table string, cutoff time.Time) (int64, time.Time, error)
+}
+
+// PartitionRanger reports which times a table's partitions can store
+type PartitionRanger interface {
+	// Span covered by table's partitions, from inclusive and to exclusive; from is zero when
+	// unbounded, and ok is false until the partitions are known
+	PartitionRange(table string) (from, to time.Time, ok bool)
+}
@@ -7,9 +7,21 @@ import (
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
 )
 
+// CreateResult reports whether a price feed was stored, ignored as a replay of an earlier
+// feed with the same idempotency key, or held back by an anomaly filter or partitioning
+type CreateResult struct {
+	FeedID      string // For duplicates, the ID of the feed stored first
+	Duplicate   bool
+	Quarantined bool
+	Rejected    bool // Failed a check, or its timestamp has no partition
+}
+
 type PriceFeedRepository interface {
-	// Create a new price feed entry
-	Create(ctx context.Context, feed *models.PriceFeed) error
+	// Create a new price feed entry; replays of an idempotency key are ignored
+	Create(ctx context.Context, feed *models.PriceFeed) (CreateResult, error)
+
+	// Create price feeds in one transaction, with one result per feed in input order
+	CreateBatch(ctx context.Context, feeds []*models.PriceFeed) ([]CreateResult, error)
 
 	// Get price feed by ID
 	GetByID(ctx context.Context, feedID string) (*models.PriceFeed, error)
@@ -23,6 +35,15 @@ type PriceFeedRepository interface {
 	// Query price feeds with filters
 	Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error)
 
+	// Get the latest feed time per symbol and source among feeds since the given time
+	GetLatestTimestamps(ctx context.Context, since time.Time) ([]*models.LatestTimestamp, error)
+
+	// Get the oldest feed time per symbol among feeds before the given time
+	GetOldestTimestamps(ctx context.Context, before time.Time) ([]*models.LatestTimestamp, error)
+
+	// Get per-source latency statistics for feeds received in [from, to)
+	LatencyStats(ctx context.Context, from, to time.Time) ([]*models.LatencyStats, error)
+
 	// Delete old price feeds (cleanup)
 	DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error)
 }
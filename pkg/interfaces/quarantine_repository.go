@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type QuarantineRepository interface {
+	// Quarantine a price feed
+	Create(ctx context.Context, quarantined *models.QuarantinedFeed) error
+
+	// Get quarantined feed by ID
+	GetByID(ctx context.Context, quarantineID string) (*models.QuarantinedFeed, error)
+
+	// Query quarantined feeds with filters, newest first
+	Query(ctx context.Context, query *models.QuarantineQuery) ([]*models.QuarantinedFeed, error)
+
+	// Delete a quarantined feed once reviewed
+	Delete(ctx context.Context, quarantineID string) error
+}
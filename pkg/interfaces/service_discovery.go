@@ -2,9 +2,29 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrServiceNotRegistered is returned when heartbeating a service whose registration has expired
+var ErrServiceNotRegistered = errors.New("service not registered")
+
+type HealthStatus string
+
+const (
+	HealthPassing  HealthStatus = "passing"
+	HealthWarning  HealthStatus = "warning"
+	HealthCritical HealthStatus = "critical"
+)
+
+type ServiceEndpoint struct {
+	Name     string // e.g. "grpc", "http", "metrics"
+	Protocol string // e.g. "grpc", "http", "tcp"
+	Address  string
+	Port     int
+	Path     string
+}
+
 type ServiceInfo struct {
 	ServiceName   string
 	ServiceID     string
@@ -12,10 +32,72 @@ type ServiceInfo struct {
 	Port          int
 	Version       string
 	Metadata      map[string]string
+	Tags          []string
+	Endpoints     []ServiceEndpoint
+	Weight        int          // Relative share of traffic, 0 is treated as 1
+	Health        HealthStatus // Empty is treated as passing
+	HealthNote    string       // Human-readable reason for the current health status
 	RegisteredAt  time.Time
 	LastHeartbeat time.Time
 }
 
+// EffectiveHealth returns the health status, defaulting to passing for entries that never set one
+func (s *ServiceInfo) EffectiveHealth() HealthStatus {
+	if s.Health == "" {
+		return HealthPassing
+	}
+	return s.Health
+}
+
+// EffectiveWeight returns the weight, defaulting to 1 for entries that never set one
+func (s *ServiceInfo) EffectiveWeight() int {
+	if s.Weight <= 0 {
+		return 1
+	}
+	return s.Weight
+}
+
+// HasTags reports whether the service carries every one of tags
+func (s *ServiceInfo) HasTags(tags ...string) bool {
+	for _, want := range tags {
+		found := false
+		for _, tag := range s.Tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type ServiceQuery struct {
+	ServiceName       string
+	Tags              []string // Instances must carry all of these
+	VersionConstraint string   // Semver range, e.g. ">=1.2.0 <2.0.0", "^1.4", "1.x"
+	HealthyOnly       bool     // Only instances whose health is passing
+}
+
+type ServiceEventType string
+
+const (
+	ServiceRegistered   ServiceEventType = "registered"
+	ServiceDeregistered ServiceEventType = "deregistered"
+	ServiceExpired      ServiceEventType = "expired"
+	ServiceHealthChange ServiceEventType = "health_changed"
+)
+
+type ServiceEvent struct {
+	Type        ServiceEventType
+	ServiceName string
+	ServiceID   string
+	Service     *ServiceInfo // Set for registrations and health changes
+	Timestamp   time.Time
+}
+
 type ServiceDiscoveryRepository interface {
 	// Register a service instance
 	Register(ctx context.Context, info *ServiceInfo) error
@@ -23,18 +105,27 @@ type ServiceDiscoveryRepository interface {
 	// Deregister a service instance
 	Deregister(ctx context.Context, serviceID string) error
 
-	// Update heartbeat for a service
+	// Update heartbeat for a service, returns ErrServiceNotRegistered if the registration expired
 	Heartbeat(ctx context.Context, serviceID string) error
 
 	// Discover service instances by name
 	Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error)
 
+	// Discover service instances matching tag, version and health filters
+	Query(ctx context.Context, query *ServiceQuery) ([]*ServiceInfo, error)
+
+	// Report a service's health without deregistering it
+	UpdateHealth(ctx context.Context, serviceID string, status HealthStatus, note string) error
+
 	// Get service info by ID
 	GetServiceInfo(ctx context.Context, serviceID string) (*ServiceInfo, error)
 
 	// List all registered services
 	ListServices(ctx context.Context) ([]*ServiceInfo, error)
 
+	// Watch registration changes for a service name; the channel closes when ctx is done
+	Watch(ctx context.Context, serviceName string) (<-chan *ServiceEvent, error)
+
 	// Health check
 	HealthCheck(ctx context.Context) error
 }
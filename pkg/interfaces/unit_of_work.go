@@ -0,0 +1,17 @@
+package interfaces
+
+import "database/sql"
+
+// TxRepositories hands out repositories bound to one database transaction
+type TxRepositories interface {
+	PriceFeedRepository() PriceFeedRepository
+	CandleRepository() CandleRepository
+	MarketSnapshotRepository() MarketSnapshotRepository
+	SymbolRepository() SymbolRepository
+}
+
+type TxOptions struct {
+	Isolation  sql.IsolationLevel
+	ReadOnly   bool
+	MaxRetries int // Retries after serialization failures and deadlocks; 0 disables
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AnomalyFinding is one check's reason for treating a price feed as a bad print
+type AnomalyFinding struct {
+	Check  string `json:"check"`
+	Reason string `json:"reason"`
+}
+
+// QuarantinedFeed is a price feed held back from the price history for review
+type QuarantinedFeed struct {
+	QuarantineID  string           `json:"quarantine_id" db:"quarantine_id"`
+	Feed          PriceFeed        `json:"feed" db:"feed"`
+	Findings      []AnomalyFinding `json:"findings" db:"findings"`
+	QuarantinedAt time.Time        `json:"quarantined_at" db:"quarantined_at"`
+}
+
+type QuarantineQuery struct {
+	Symbol            *string
+	Source            *string
+	QuarantinedAtFrom *time.Time
+	QuarantinedAtTo   *time.Time
+	Limit             int
+	Offset            int
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type SourceSwitchReason string
+
+const (
+	SwitchSourceStale     SourceSwitchReason = "stale"
+	SwitchSourceDeviation SourceSwitchReason = "deviation"
+	SwitchFailback        SourceSwitchReason = "failback"
+	SwitchReprioritized   SourceSwitchReason = "reprioritized"
+)
+
+// SourceSwitchEvent records the arbiter moving a symbol from one feed source to another
+type SourceSwitchEvent struct {
+	EventID    string             `json:"event_id" db:"event_id"`
+	Symbol     string             `json:"symbol" db:"symbol"`
+	FromSource string             `json:"from_source" db:"from_source"`
+	ToSource   string             `json:"to_source" db:"to_source"`
+	Reason     SourceSwitchReason `json:"reason" db:"reason"`
+	SwitchedAt time.Time          `json:"switched_at" db:"switched_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// CandleGap is a run of consecutive missing candles, from the start of the first missing
+// candle up to the start of the next stored one
+type CandleGap struct {
+	Symbol   string         `json:"symbol"`
+	Interval CandleInterval `json:"interval"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+}
+
+// Candles returns the number of missing candles in the gap
+func (g CandleGap) Candles() int {
+	d := g.Interval.Duration()
+	if d <= 0 {
+		return 0
+	}
+	return int(g.To.Sub(g.From) / d)
+}
+
+// BackfillCheckpoint records how far a backfill of one symbol and interval has got, so an
+// interrupted run can resume from Cursor instead of starting over
+type BackfillCheckpoint struct {
+	Symbol    string         `json:"symbol" db:"symbol"`
+	Interval  CandleInterval `json:"interval" db:"interval"`
+	From      time.Time      `json:"from" db:"from_time"`
+	To        time.Time      `json:"to" db:"to_time"`
+	Cursor    time.Time      `json:"cursor" db:"cursor_time"` // Everything before it has been handled
+	Filled    int            `json:"filled" db:"filled"`
+	Unfilled  int            `json:"unfilled" db:"unfilled"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
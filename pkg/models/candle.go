@@ -18,6 +18,29 @@ const (
 	Interval1d  CandleInterval = "1d"
 )
 
+var candleIntervalDurations = map[CandleInterval]time.Duration{
+	Interval1m:  time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval1d:  24 * time.Hour,
+}
+
+// Duration returns the length of one candle, or zero for an unknown interval
+func (i CandleInterval) Duration() time.Duration {
+	return candleIntervalDurations[i]
+}
+
+// Align returns the start of the candle containing t. Candles are aligned to UTC, so
+// daily candles start at midnight UTC.
+func (i CandleInterval) Align(t time.Time) time.Time {
+	if d := i.Duration(); d > 0 {
+		return t.UTC().Truncate(d)
+	}
+	return t
+}
+
 type Candle struct {
 	CandleID  string          `json:"candle_id" db:"candle_id"`
 	Symbol    string          `json:"symbol" db:"symbol"`
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DataQualityEventType string
+
+const (
+	QualitySequenceGap DataQualityEventType = "sequence_gap"
+	QualityOutOfOrder  DataQualityEventType = "out_of_order"
+	QualityStaleFeed   DataQualityEventType = "stale_feed"
+	QualityRecovered   DataQualityEventType = "feed_recovered"
+)
+
+// DataQualityEvent records a problem detected in a source's feed, such as dropped or
+// reordered messages
+type DataQualityEvent struct {
+	EventID          string               `json:"event_id" db:"event_id"`
+	Type             DataQualityEventType `json:"type" db:"type"`
+	Source           string               `json:"source" db:"source"`
+	Symbol           string               `json:"symbol" db:"symbol"`
+	ExpectedSequence int64                `json:"expected_sequence" db:"expected_sequence"`
+	ReceivedSequence int64                `json:"received_sequence" db:"received_sequence"`
+	Missing          int64                `json:"missing" db:"missing"` // Messages skipped by a gap
+	DetectedAt       time.Time            `json:"detected_at" db:"detected_at"`
+	Metadata         json.RawMessage      `json:"metadata,omitempty" db:"metadata"`
+}
+
+type DataQualityQuery struct {
+	Type           *DataQualityEventType
+	Source         *string
+	Symbol         *string
+	DetectedAtFrom *time.Time
+	DetectedAtTo   *time.Time
+	Limit          int
+	Offset         int
+}
@@ -1,22 +1,70 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 type PriceFeed struct {
-	FeedID    string           `json:"feed_id" db:"feed_id"`
-	Symbol    string           `json:"symbol" db:"symbol"`
-	Price     decimal.Decimal  `json:"price" db:"price"`
-	Bid       *decimal.Decimal `json:"bid,omitempty" db:"bid"`
-	Ask       *decimal.Decimal `json:"ask,omitempty" db:"ask"`
-	Volume24h *decimal.Decimal `json:"volume_24h,omitempty" db:"volume_24h"`
-	Source    string           `json:"source" db:"source"`
-	Timestamp time.Time        `json:"timestamp" db:"timestamp"`
-	Metadata  json.RawMessage  `json:"metadata,omitempty" db:"metadata"`
+	FeedID         string           `json:"feed_id" db:"feed_id"`
+	Symbol         string           `json:"symbol" db:"symbol"`
+	Price          decimal.Decimal  `json:"price" db:"price"`
+	Bid            *decimal.Decimal `json:"bid,omitempty" db:"bid"`
+	Ask            *decimal.Decimal `json:"ask,omitempty" db:"ask"`
+	Volume24h      *decimal.Decimal `json:"volume_24h,omitempty" db:"volume_24h"`
+	Source         string           `json:"source" db:"source"`
+	Timestamp      time.Time        `json:"timestamp" db:"timestamp"`       // Event time reported by the source
+	ReceivedAt     time.Time        `json:"received_at" db:"received_at"`   // When the collector received it; defaults to the write time
+	PersistedAt    time.Time        `json:"persisted_at" db:"persisted_at"` // Set by the database on insert
+	Metadata       json.RawMessage  `json:"metadata,omitempty" db:"metadata"`
+	IdempotencyKey *string          `json:"idempotency_key,omitempty" db:"idempotency_key"`
+	Sequence       *int64           `json:"sequence,omitempty" db:"sequence"` // Monotonic per source, if the source numbers its messages
+}
+
+// SequenceIdempotencyKey builds a dedupe key from a source's own sequence number
+func SequenceIdempotencyKey(source string, sequence int64) string {
+	return fmt.Sprintf("%s:seq:%d", source, sequence)
+}
+
+// ContentIdempotencyKey builds a dedupe key by hashing the fields that identify a tick,
+// for sources that do not number their messages
+func (f *PriceFeed) ContentIdempotencyKey() string {
+	parts := []string{f.Symbol, f.Price.String(), decimalString(f.Bid), decimalString(f.Ask), f.Timestamp.UTC().Format(time.RFC3339Nano)}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return f.Source + ":sha256:" + hex.EncodeToString(sum[:16])
+}
+
+func decimalString(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+// LatestTimestamp is the time of the most recent update for a symbol, per source for
+// price feeds; Source is empty for snapshots. Retention uses it for the oldest row
+// before a cutoff, with Source empty.
+type LatestTimestamp struct {
+	Symbol    string    `json:"symbol"`
+	Source    string    `json:"source,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LatencyStats summarises how late a source's feeds arrive and how long they take to store
+type LatencyStats struct {
+	Source            string        `json:"source"`
+	Samples           int64         `json:"samples"`
+	FeedLatencyP50    time.Duration `json:"feed_latency_p50"` // ReceivedAt - Timestamp, including clock skew
+	FeedLatencyP99    time.Duration `json:"feed_latency_p99"`
+	PersistLatencyP50 time.Duration `json:"persist_latency_p50"` // PersistedAt - ReceivedAt
+	PersistLatencyP99 time.Duration `json:"persist_latency_p99"`
+	ClockSkew         time.Duration `json:"clock_skew"` // Largest amount an event time ran ahead of its receive time; a lower bound on how far the source's clock is fast
 }
 
 type PriceFeedQuery struct {
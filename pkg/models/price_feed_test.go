@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceFeed_ContentIdempotencyKey(t *testing.T) {
+	timestamp := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	feed := &PriceFeed{Symbol: "BTC-USD", Price: decimal.RequireFromString("50000.10"), Source: "coinbase", Timestamp: timestamp}
+	replay := &PriceFeed{Symbol: "BTC-USD", Price: decimal.RequireFromString("50000.10"), Source: "coinbase", Timestamp: timestamp.In(time.FixedZone("EST", -5*3600))}
+
+	assert.Equal(t, feed.ContentIdempotencyKey(), replay.ContentIdempotencyKey(), "The same tick must hash the same in any time zone")
+	assert.Contains(t, feed.ContentIdempotencyKey(), "coinbase:sha256:")
+
+	replay.Price = decimal.RequireFromString("50000.11")
+	assert.NotEqual(t, feed.ContentIdempotencyKey(), replay.ContentIdempotencyKey())
+}
+
+func TestSequenceIdempotencyKey(t *testing.T) {
+	assert.Equal(t, "coinbase:seq:42", SequenceIdempotencyKey("coinbase", 42))
+}
@@ -0,0 +1,170 @@
+package ohlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+type Options struct {
+	ChunkCandles int // Missing candles filled between checkpoints; defaults to 500
+}
+
+// Report summarises one backfill run
+type Report struct {
+	Symbol   string                `json:"symbol"`
+	Interval models.CandleInterval `json:"interval"`
+	Resumed  bool                  `json:"resumed"`
+	Gaps     []models.CandleGap    `json:"gaps"`     // Found when the run started
+	Filled   map[string]int        `json:"filled"`   // Candles stored per source
+	Unfilled []models.CandleGap    `json:"unfilled"` // Left missing by every source
+}
+
+// Backfiller fills candle gaps from a list of sources, asking each in turn for the slots
+// the earlier ones could not build. Progress is checkpointed after every chunk, so an
+// interrupted run resumes where it stopped; the checkpoint is removed once a run completes.
+// Without a checkpoint repository every run starts from the beginning.
+type Backfiller struct {
+	candles     interfaces.CandleRepository
+	checkpoints interfaces.BackfillCheckpointRepository
+	sources     []Source
+	options     Options
+	logger      *logrus.Logger
+}
+
+func NewBackfiller(candles interfaces.CandleRepository, checkpoints interfaces.BackfillCheckpointRepository, sources []Source, options Options, logger *logrus.Logger) *Backfiller {
+	if options.ChunkCandles <= 0 {
+		options.ChunkCandles = 500
+	}
+
+	return &Backfiller{
+		candles:     candles,
+		checkpoints: checkpoints,
+		sources:     sources,
+		options:     options,
+		logger:      logger,
+	}
+}
+
+// Backfill finds and fills the gaps in the symbol's candles between from and to
+func (b *Backfiller) Backfill(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) (*Report, error) {
+	if interval.Duration() <= 0 {
+		return nil, fmt.Errorf("unknown candle interval %q", interval)
+	}
+	from, to = alignRange(interval, from, to)
+
+	report := &Report{Symbol: symbol, Interval: interval, Filled: make(map[string]int)}
+	checkpoint := &models.BackfillCheckpoint{Symbol: symbol, Interval: interval, From: from, To: to, Cursor: from}
+
+	if b.checkpoints != nil {
+		saved, err := b.checkpoints.Get(ctx, symbol, interval)
+		switch {
+		case err == nil && saved.From.Equal(from) && saved.To.Equal(to):
+			checkpoint = saved
+			report.Resumed = true
+		case err != nil && !errors.Is(err, interfaces.ErrNotFound):
+			return nil, fmt.Errorf("failed to load backfill checkpoint: %w", err)
+		}
+	}
+
+	gaps, err := FindStoredGaps(ctx, b.candles, symbol, interval, checkpoint.Cursor, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candle gaps: %w", err)
+	}
+	report.Gaps = gaps
+
+	logger := b.logger.WithFields(logrus.Fields{"symbol": symbol, "interval": interval})
+	logger.WithFields(logrus.Fields{
+		"gaps":    len(gaps),
+		"resumed": report.Resumed,
+		"cursor":  checkpoint.Cursor,
+	}).Info("Starting candle backfill")
+
+	chunk := time.Duration(b.options.ChunkCandles) * interval.Duration()
+	for _, gap := range gaps {
+		for start := gap.From; start.Before(gap.To); start = start.Add(chunk) {
+			end := start.Add(chunk)
+			if end.After(gap.To) {
+				end = gap.To
+			}
+
+			if err := b.fillChunk(ctx, symbol, interval, start, end, report, checkpoint); err != nil {
+				return report, err
+			}
+
+			checkpoint.Cursor = end
+			checkpoint.UpdatedAt = time.Now()
+			if b.checkpoints != nil {
+				if err := b.checkpoints.Save(ctx, checkpoint); err != nil {
+					logger.WithError(err).Warn("Failed to save backfill checkpoint")
+				}
+			}
+		}
+	}
+
+	if b.checkpoints != nil {
+		if err := b.checkpoints.Delete(ctx, symbol, interval); err != nil && !errors.Is(err, interfaces.ErrNotFound) {
+			logger.WithError(err).Warn("Failed to delete backfill checkpoint")
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"filled":   report.Filled,
+		"unfilled": len(report.Unfilled),
+	}).Info("Candle backfill completed")
+	return report, nil
+}
+
+// fillChunk offers the missing slots of [from, to) to each source in turn and stores
+// what they build. Source failures are logged and the slots passed on; storage
+// failures stop the run before the checkpoint moves past them.
+func (b *Backfiller) fillChunk(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time, report *Report, checkpoint *models.BackfillCheckpoint) error {
+	var filled []*models.Candle
+	done := make(map[int64]bool)
+	total := int(to.Sub(from) / interval.Duration())
+
+	for _, source := range b.sources {
+		if len(done) == total {
+			break
+		}
+
+		candles, err := source.Build(ctx, symbol, interval, from, to)
+		if err != nil {
+			b.logger.WithError(err).WithFields(logrus.Fields{
+				"symbol":   symbol,
+				"interval": interval,
+				"source":   source.Name(),
+			}).Warn("Backfill source failed")
+			continue
+		}
+
+		for _, candle := range candles {
+			key := candle.StartTime.UnixNano()
+			if done[key] {
+				continue
+			}
+			if err := b.candles.Upsert(ctx, candle); err != nil {
+				return fmt.Errorf("failed to store backfilled candle: %w", err)
+			}
+			done[key] = true
+			filled = append(filled, candle)
+			report.Filled[source.Name()]++
+			checkpoint.Filled++
+		}
+	}
+
+	unfilled, err := FindGaps(symbol, interval, from, to, filled)
+	if err != nil {
+		return err
+	}
+	for _, gap := range unfilled {
+		checkpoint.Unfilled += gap.Candles()
+	}
+	report.Unfilled = append(report.Unfilled, unfilled...)
+	return nil
+}
@@ -0,0 +1,230 @@
+package ohlc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCandles stores candles by interval and start time
+type memoryCandles struct {
+	interfaces.CandleRepository
+	candles  map[models.CandleInterval]map[time.Time]*models.Candle
+	failFrom time.Time // Upserts at or after this time fail, if set
+}
+
+func newMemoryCandles() *memoryCandles {
+	return &memoryCandles{candles: make(map[models.CandleInterval]map[time.Time]*models.Candle)}
+}
+
+func (m *memoryCandles) Upsert(ctx context.Context, candle *models.Candle) error {
+	if !m.failFrom.IsZero() && !candle.StartTime.Before(m.failFrom) {
+		return errors.New("database down")
+	}
+	if m.candles[candle.Interval] == nil {
+		m.candles[candle.Interval] = make(map[time.Time]*models.Candle)
+	}
+	m.candles[candle.Interval][candle.StartTime] = candle
+	return nil
+}
+
+func (m *memoryCandles) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	var result []*models.Candle
+	d := query.Interval.Duration()
+	for start := *query.StartTimeFrom; start.Before(*query.StartTimeTo); start = start.Add(d) {
+		if candle, ok := m.candles[*query.Interval][start]; ok {
+			result = append(result, candle)
+		}
+	}
+	return result, nil
+}
+
+type memoryFeeds struct {
+	interfaces.PriceFeedRepository
+	feeds []*models.PriceFeed
+}
+
+func (m *memoryFeeds) Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error) {
+	var result []*models.PriceFeed
+	for _, feed := range m.feeds {
+		if !feed.Timestamp.Before(*query.TimestampFrom) && feed.Timestamp.Before(*query.TimestampTo) {
+			result = append(result, feed)
+		}
+	}
+	return result, nil
+}
+
+type memoryCheckpoints struct {
+	saved   map[string]*models.BackfillCheckpoint
+	history []models.BackfillCheckpoint
+}
+
+func (m *memoryCheckpoints) Get(ctx context.Context, symbol string, interval models.CandleInterval) (*models.BackfillCheckpoint, error) {
+	if checkpoint, ok := m.saved[symbol+string(interval)]; ok {
+		copied := *checkpoint
+		return &copied, nil
+	}
+	return nil, interfaces.ErrNotFound
+}
+
+func (m *memoryCheckpoints) Save(ctx context.Context, checkpoint *models.BackfillCheckpoint) error {
+	copied := *checkpoint
+	m.saved[checkpoint.Symbol+string(checkpoint.Interval)] = &copied
+	m.history = append(m.history, copied)
+	return nil
+}
+
+func (m *memoryCheckpoints) Delete(ctx context.Context, symbol string, interval models.CandleInterval) error {
+	delete(m.saved, symbol+string(interval))
+	return nil
+}
+
+type fakeProvider struct {
+	calls int
+}
+
+func (p *fakeProvider) Name() string {
+	return "vendor"
+}
+
+func (p *fakeProvider) FetchCandles(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) ([]*models.Candle, error) {
+	p.calls++
+	var candles []*models.Candle
+	for start := from; start.Before(to); start = start.Add(interval.Duration()) {
+		candles = append(candles, newCandle(symbol, interval, start, decimal.NewFromInt(50)))
+	}
+	return candles, nil
+}
+
+func minuteCandle(minute int, open, high, low, close, volume int64) *models.Candle {
+	candle := newCandle("BTC-USD", models.Interval1m, base.Add(time.Duration(minute)*time.Minute), decimal.NewFromInt(open))
+	extend(candle, decimal.NewFromInt(high), decimal.NewFromInt(low), decimal.NewFromInt(close))
+	candle.Volume = decimal.NewFromInt(volume)
+	return candle
+}
+
+func newTestBackfiller(candles *memoryCandles, feeds *memoryFeeds, provider interfaces.HistoricalDataProvider, chunk int) (*Backfiller, *memoryCheckpoints) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	checkpoints := &memoryCheckpoints{saved: make(map[string]*models.BackfillCheckpoint)}
+	sources := []Source{&FeedSource{Feeds: feeds}, &ResampleSource{Candles: candles}}
+	if provider != nil {
+		sources = append(sources, &ProviderSource{Provider: provider})
+	}
+	return NewBackfiller(candles, checkpoints, sources, Options{ChunkCandles: chunk}, logger), checkpoints
+}
+
+func TestBackfiller_FillsFromFeedsThenFinerCandlesThenProvider(t *testing.T) {
+	candles := newMemoryCandles()
+	// 5m candles: slot 0 is stored, slot 1 can be built from feeds, slot 2 from 1m
+	// candles, and slot 3 only from the provider
+	candles.Upsert(context.Background(), newCandle("BTC-USD", models.Interval5m, base, decimal.NewFromInt(100)))
+	for minute := 10; minute < 15; minute++ {
+		candles.Upsert(context.Background(), minuteCandle(minute, 100, 110, 95, 105, 2))
+	}
+	feeds := &memoryFeeds{feeds: []*models.PriceFeed{
+		{Symbol: "BTC-USD", Price: decimal.NewFromInt(101), Timestamp: base.Add(5 * time.Minute)},
+		{Symbol: "BTC-USD", Price: decimal.NewFromInt(104), Timestamp: base.Add(6 * time.Minute)},
+		{Symbol: "BTC-USD", Price: decimal.NewFromInt(99), Timestamp: base.Add(7 * time.Minute)},
+	}}
+	provider := &fakeProvider{}
+	backfiller, checkpoints := newTestBackfiller(candles, feeds, provider, 100)
+
+	report, err := backfiller.Backfill(context.Background(), "BTC-USD", models.Interval5m, base, base.Add(20*time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"price_feeds": 1, "resample": 1, "vendor": 1}, report.Filled)
+	assert.Empty(t, report.Unfilled)
+	assert.Empty(t, checkpoints.saved, "Completed runs remove their checkpoint")
+
+	fromFeeds := candles.candles[models.Interval5m][base.Add(5*time.Minute)]
+	assert.True(t, decimal.NewFromInt(101).Equal(fromFeeds.Open))
+	assert.True(t, decimal.NewFromInt(104).Equal(fromFeeds.High))
+	assert.True(t, decimal.NewFromInt(99).Equal(fromFeeds.Low))
+	assert.True(t, decimal.NewFromInt(99).Equal(fromFeeds.Close))
+
+	resampled := candles.candles[models.Interval5m][base.Add(10*time.Minute)]
+	assert.True(t, decimal.NewFromInt(10).Equal(resampled.Volume))
+	assert.True(t, decimal.NewFromInt(110).Equal(resampled.High))
+}
+
+func TestBackfiller_DoesNotResamplePartialCoverage(t *testing.T) {
+	candles := newMemoryCandles()
+	candles.Upsert(context.Background(), minuteCandle(0, 100, 100, 100, 100, 1))
+	backfiller, _ := newTestBackfiller(candles, &memoryFeeds{}, nil, 100)
+
+	report, err := backfiller.Backfill(context.Background(), "BTC-USD", models.Interval5m, base, base.Add(5*time.Minute))
+	require.NoError(t, err)
+
+	assert.Empty(t, report.Filled)
+	require.Len(t, report.Unfilled, 1)
+	assert.Equal(t, base, report.Unfilled[0].From)
+}
+
+func TestBackfiller_ResumesFromCheckpoint(t *testing.T) {
+	candles := newMemoryCandles()
+	candles.failFrom = base.Add(4 * time.Minute)
+	provider := &fakeProvider{}
+	backfiller, checkpoints := newTestBackfiller(candles, &memoryFeeds{}, provider, 2)
+
+	_, err := backfiller.Backfill(context.Background(), "BTC-USD", models.Interval1m, base, base.Add(6*time.Minute))
+	require.Error(t, err)
+
+	saved := checkpoints.saved["BTC-USD"+string(models.Interval1m)]
+	require.NotNil(t, saved)
+	assert.Equal(t, base.Add(4*time.Minute), saved.Cursor)
+	assert.Equal(t, 4, saved.Filled)
+
+	candles.failFrom = time.Time{}
+	provider.calls = 0
+	report, err := backfiller.Backfill(context.Background(), "BTC-USD", models.Interval1m, base, base.Add(6*time.Minute))
+	require.NoError(t, err)
+
+	assert.True(t, report.Resumed)
+	assert.Equal(t, 2, report.Filled["vendor"])
+	assert.Equal(t, 1, provider.calls, "Only the chunk after the checkpoint is fetched again")
+	assert.Len(t, candles.candles[models.Interval1m], 6)
+}
+
+func TestBackfiller_RunsWithoutCheckpointRepository(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	candles := newMemoryCandles()
+	backfiller := NewBackfiller(candles, nil, []Source{&ProviderSource{Provider: &fakeProvider{}}}, Options{ChunkCandles: 2}, logger)
+
+	report, err := backfiller.Backfill(context.Background(), "BTC-USD", models.Interval1m, base, base.Add(5*time.Minute))
+	require.NoError(t, err)
+
+	assert.False(t, report.Resumed)
+	assert.Equal(t, 5, report.Filled["vendor"])
+	assert.Len(t, candles.candles[models.Interval1m], 5)
+}
+
+func TestResampleSource_AllowPartialRecordsCoverage(t *testing.T) {
+	candles := newMemoryCandles()
+	for minute := 0; minute < 3; minute++ {
+		candles.Upsert(context.Background(), minuteCandle(minute, 100, 110, 90, 105, 1))
+	}
+	source := &ResampleSource{Candles: candles, AllowPartial: true}
+
+	built, err := source.Build(context.Background(), "BTC-USD", models.Interval5m, base, base.Add(5*time.Minute))
+	require.NoError(t, err)
+
+	require.Len(t, built, 1)
+	assert.True(t, decimal.NewFromInt(3).Equal(built[0].Volume))
+
+	var metadata map[string]interface{}
+	require.NoError(t, json.Unmarshal(built[0].Metadata, &metadata))
+	assert.Equal(t, "3/5", metadata["coverage"])
+}
@@ -0,0 +1,76 @@
+package ohlc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+// FindGaps returns the runs of candles missing from the stored ones between from and to.
+// The range is widened to whole candles: from is aligned down and to is aligned up.
+// Stored candles may be in any order; ones not on the interval's alignment are ignored.
+func FindGaps(symbol string, interval models.CandleInterval, from, to time.Time, stored []*models.Candle) ([]models.CandleGap, error) {
+	d := interval.Duration()
+	if d <= 0 {
+		return nil, fmt.Errorf("unknown candle interval %q", interval)
+	}
+
+	start, end := alignRange(interval, from, to)
+
+	present := make(map[int64]bool, len(stored))
+	for _, candle := range stored {
+		present[candle.StartTime.UTC().UnixNano()] = true
+	}
+
+	var gaps []models.CandleGap
+	var open *models.CandleGap
+	for slot := start; slot.Before(end); slot = slot.Add(d) {
+		if present[slot.UnixNano()] {
+			if open != nil {
+				gaps = append(gaps, *open)
+				open = nil
+			}
+			continue
+		}
+		if open == nil {
+			open = &models.CandleGap{Symbol: symbol, Interval: interval, From: slot}
+		}
+		open.To = slot.Add(d)
+	}
+	if open != nil {
+		gaps = append(gaps, *open)
+	}
+	return gaps, nil
+}
+
+// FindStoredGaps reads the stored candles between from and to and returns the gaps in them
+func FindStoredGaps(ctx context.Context, candles interfaces.CandleRepository, symbol string, interval models.CandleInterval, from, to time.Time) ([]models.CandleGap, error) {
+	if interval.Duration() <= 0 {
+		return nil, fmt.Errorf("unknown candle interval %q", interval)
+	}
+
+	start, end := alignRange(interval, from, to)
+	stored, err := candles.Query(ctx, &models.CandleQuery{
+		Symbol:        &symbol,
+		Interval:      &interval,
+		StartTimeFrom: &start,
+		StartTimeTo:   &end,
+		SortBy:        "start_time",
+		SortOrder:     "ASC",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FindGaps(symbol, interval, start, end, stored)
+}
+
+func alignRange(interval models.CandleInterval, from, to time.Time) (time.Time, time.Time) {
+	start, end := interval.Align(from), interval.Align(to)
+	if end.Before(to) {
+		end = end.Add(interval.Duration())
+	}
+	return start, end
+}
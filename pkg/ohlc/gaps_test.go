@@ -0,0 +1,48 @@
+package ohlc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func storedAt(minutes ...int) []*models.Candle {
+	var candles []*models.Candle
+	for _, minute := range minutes {
+		candles = append(candles, &models.Candle{StartTime: base.Add(time.Duration(minute) * time.Minute)})
+	}
+	return candles
+}
+
+func TestFindGaps(t *testing.T) {
+	gaps, err := FindGaps("BTC-USD", models.Interval1m, base, base.Add(10*time.Minute), storedAt(0, 1, 4, 5, 9))
+	require.NoError(t, err)
+
+	require.Len(t, gaps, 2)
+	assert.Equal(t, base.Add(2*time.Minute), gaps[0].From)
+	assert.Equal(t, base.Add(4*time.Minute), gaps[0].To)
+	assert.Equal(t, 2, gaps[0].Candles())
+	assert.Equal(t, base.Add(6*time.Minute), gaps[1].From)
+	assert.Equal(t, base.Add(9*time.Minute), gaps[1].To)
+}
+
+func TestFindGaps_AlignsRangeToWholeCandles(t *testing.T) {
+	gaps, err := FindGaps("BTC-USD", models.Interval5m, base.Add(2*time.Minute), base.Add(11*time.Minute), storedAt(5))
+	require.NoError(t, err)
+
+	require.Len(t, gaps, 2)
+	assert.Equal(t, base, gaps[0].From)
+	assert.Equal(t, base.Add(5*time.Minute), gaps[0].To)
+	assert.Equal(t, base.Add(10*time.Minute), gaps[1].From)
+	assert.Equal(t, base.Add(15*time.Minute), gaps[1].To)
+}
+
+func TestFindGaps_RejectsUnknownInterval(t *testing.T) {
+	_, err := FindGaps("BTC-USD", "2m", base, base.Add(time.Hour), nil)
+	assert.Error(t, err)
+}
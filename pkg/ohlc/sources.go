@@ -0,0 +1,217 @@
+package ohlc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+)
+
+// Source builds candles for the slots of [from, to) it has data for. Slots it cannot
+// build are omitted, and the backfiller offers them to the next source.
+type Source interface {
+	Name() string
+	Build(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) ([]*models.Candle, error)
+}
+
+// intervals lists the known intervals, coarsest first
+var intervals = []models.CandleInterval{
+	models.Interval1d, models.Interval4h, models.Interval1h, models.Interval15m, models.Interval5m, models.Interval1m,
+}
+
+// FeedSource aggregates stored price feeds into candles. Price feeds carry no trade
+// volume, so the candles have zero volume and count ticks in their metadata.
+type FeedSource struct {
+	Feeds      interfaces.PriceFeedRepository
+	FeedSource string // Only use feeds from this source; empty uses every source
+}
+
+func (s *FeedSource) Name() string {
+	return "price_feeds"
+}
+
+func (s *FeedSource) Build(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) ([]*models.Candle, error) {
+	query := &models.PriceFeedQuery{
+		Symbol:        &symbol,
+		TimestampFrom: &from,
+		TimestampTo:   &to,
+		SortBy:        "timestamp",
+		SortOrder:     "ASC",
+	}
+	if s.FeedSource != "" {
+		query.Source = &s.FeedSource
+	}
+
+	feeds, err := s.Feeds.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[int64]*models.Candle)
+	ticks := make(map[int64]int)
+	var starts []int64
+	for _, feed := range feeds {
+		start := interval.Align(feed.Timestamp)
+		key := start.UnixNano()
+		candle, ok := buckets[key]
+		if !ok {
+			candle = newCandle(symbol, interval, start, feed.Price)
+			buckets[key] = candle
+			starts = append(starts, key)
+		}
+		extend(candle, feed.Price, feed.Price, feed.Price)
+		ticks[key]++
+	}
+
+	candles := make([]*models.Candle, 0, len(starts))
+	for _, key := range starts {
+		candle := buckets[key]
+		candle.Metadata, _ = json.Marshal(map[string]interface{}{"backfill": s.Name(), "ticks": ticks[key]})
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+// ResampleSource combines stored finer candles into coarser ones, trying the coarsest
+// finer interval first. Unless AllowPartial is set, a candle is only built when every
+// finer candle it covers is stored, so a partial hour never passes for a full one.
+type ResampleSource struct {
+	Candles      interfaces.CandleRepository
+	AllowPartial bool // Build from whatever finer candles exist, recording the coverage
+}
+
+func (s *ResampleSource) Name() string {
+	return "resample"
+}
+
+func (s *ResampleSource) Build(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) ([]*models.Candle, error) {
+	d := interval.Duration()
+	built := make(map[int64]bool)
+	var candles []*models.Candle
+
+	for _, finer := range intervals {
+		fd := finer.Duration()
+		if fd >= d || d%fd != 0 {
+			continue
+		}
+
+		stored, err := s.Candles.Query(ctx, &models.CandleQuery{
+			Symbol:        &symbol,
+			Interval:      &finer,
+			StartTimeFrom: &from,
+			StartTimeTo:   &to,
+			SortBy:        "start_time",
+			SortOrder:     "ASC",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s candles: %w", finer, err)
+		}
+
+		groups := make(map[int64][]*models.Candle)
+		var starts []int64
+		for _, candle := range stored {
+			key := interval.Align(candle.StartTime).UnixNano()
+			if built[key] {
+				continue
+			}
+			if _, ok := groups[key]; !ok {
+				starts = append(starts, key)
+			}
+			groups[key] = append(groups[key], candle)
+		}
+
+		for _, key := range starts {
+			group := groups[key]
+			expected := int(d / fd)
+			if len(group) != expected && !s.AllowPartial {
+				continue
+			}
+			candles = append(candles, resample(symbol, interval, finer, group, expected))
+			built[key] = true
+		}
+	}
+	return candles, nil
+}
+
+// resample merges finer candles, oldest first, into one candle
+func resample(symbol string, interval, finer models.CandleInterval, group []*models.Candle, expected int) *models.Candle {
+	candle := newCandle(symbol, interval, interval.Align(group[0].StartTime), group[0].Open)
+	var trades int
+	var hasTrades bool
+	for _, part := range group {
+		extend(candle, part.High, part.Low, part.Close)
+		candle.Volume = candle.Volume.Add(part.Volume)
+		if part.NumTrades != nil {
+			trades += *part.NumTrades
+			hasTrades = true
+		}
+	}
+	if hasTrades {
+		candle.NumTrades = &trades
+	}
+	candle.Metadata, _ = json.Marshal(map[string]interface{}{
+		"backfill":      "resample",
+		"from_interval": finer,
+		"coverage":      fmt.Sprintf("%d/%d", len(group), expected),
+	})
+	return candle
+}
+
+// ProviderSource fetches candles from a historical-data provider
+type ProviderSource struct {
+	Provider interfaces.HistoricalDataProvider
+}
+
+func (s *ProviderSource) Name() string {
+	return s.Provider.Name()
+}
+
+// Build keeps only the fetched candles that sit on the interval's alignment within the range
+func (s *ProviderSource) Build(ctx context.Context, symbol string, interval models.CandleInterval, from, to time.Time) ([]*models.Candle, error) {
+	fetched, err := s.Provider.FetchCandles(ctx, symbol, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var candles []*models.Candle
+	for _, candle := range fetched {
+		start := candle.StartTime.UTC()
+		if !interval.Align(start).Equal(start) || start.Before(from) || !start.Before(to) {
+			continue
+		}
+		candle.Symbol, candle.Interval, candle.StartTime = symbol, interval, start
+		if candle.EndTime.IsZero() {
+			candle.EndTime = start.Add(interval.Duration())
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
+
+func newCandle(symbol string, interval models.CandleInterval, start time.Time, open decimal.Decimal) *models.Candle {
+	return &models.Candle{
+		Symbol:    symbol,
+		Interval:  interval,
+		Open:      open,
+		High:      open,
+		Low:       open,
+		Close:     open,
+		Volume:    decimal.Zero,
+		StartTime: start,
+		EndTime:   start.Add(interval.Duration()),
+	}
+}
+
+func extend(candle *models.Candle, high, low, close decimal.Decimal) {
+	if high.GreaterThan(candle.High) {
+		candle.High = high
+	}
+	if low.LessThan(candle.Low) {
+		candle.Low = low
+	}
+	candle.Close = close
+}
@@ -0,0 +1,251 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+)
+
+type Method string
+
+const (
+	MethodMedian         Method = "median"
+	MethodVolumeWeighted Method = "volume_weighted"
+	MethodTrimmedMean    Method = "trimmed_mean"
+	MethodPriority       Method = "priority"
+)
+
+// Reasons a source's quote was left out of the consensus
+const (
+	RejectedStale    = "stale"
+	RejectedOutlier  = "outlier"
+	RejectedNoVolume = "no_volume"
+)
+
+// ErrNoQuorum is returned when too few sources survive staleness and outlier filtering
+var ErrNoQuorum = errors.New("not enough sources for consensus")
+
+type Options struct {
+	Method       Method
+	MaxAge       time.Duration            // Quotes older than this are stale; 0 accepts any age Pricer reads
+	SourceMaxAge map[string]time.Duration // Per-source overrides of MaxAge
+	Lookback     time.Duration            // History Pricer reads for sources without a max age; defaults to DefaultLookback
+	MaxDeviation float64                  // Reject quotes further than this fraction from the median; 0 disables
+	TrimFraction float64                  // Fraction dropped from each end by MethodTrimmedMean; defaults to 0.1
+	Priority     []string                 // Source preference for MethodPriority, highest first
+	MinSources   int                      // Defaults to 1
+}
+
+// Quote is one source's latest price as considered by the consensus
+type Quote struct {
+	Source    string          `json:"source"`
+	FeedID    string          `json:"feed_id"`
+	Price     decimal.Decimal `json:"price"`
+	Volume    decimal.Decimal `json:"volume"`
+	Timestamp time.Time       `json:"timestamp"`
+	Reason    string          `json:"reason,omitempty"` // Why the quote was rejected
+}
+
+type Consensus struct {
+	Symbol     string          `json:"symbol"`
+	Price      decimal.Decimal `json:"price"`
+	Method     Method          `json:"method"`
+	Sources    []Quote         `json:"sources"` // Quotes that contributed to the price
+	Rejected   []Quote         `json:"rejected,omitempty"`
+	ComputedAt time.Time       `json:"computed_at"`
+}
+
+// Compute derives a consensus price for symbol from feeds, using each source's most
+// recent feed. Stale quotes are dropped first, then outliers relative to the median of
+// the rest, and the method is applied to what remains.
+func Compute(symbol string, feeds []*models.PriceFeed, now time.Time, options Options) (*Consensus, error) {
+	if options.MinSources <= 0 {
+		options.MinSources = 1
+	}
+
+	consensus := &Consensus{
+		Symbol:     symbol,
+		Method:     options.Method,
+		ComputedAt: now,
+	}
+
+	var quotes []Quote
+	for _, quote := range latestBySource(symbol, feeds) {
+		if maxAge := options.maxAge(quote.Source); maxAge > 0 && now.Sub(quote.Timestamp) > maxAge {
+			quote.Reason = RejectedStale
+			consensus.Rejected = append(consensus.Rejected, quote)
+			continue
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if options.MaxDeviation > 0 && len(quotes) > 2 {
+		median := medianPrice(quotes)
+		limit := median.Mul(decimal.NewFromFloat(options.MaxDeviation))
+
+		kept := quotes[:0]
+		for _, quote := range quotes {
+			if quote.Price.Sub(median).Abs().GreaterThan(limit) {
+				quote.Reason = RejectedOutlier
+				consensus.Rejected = append(consensus.Rejected, quote)
+				continue
+			}
+			kept = append(kept, quote)
+		}
+		quotes = kept
+	}
+
+	var price decimal.Decimal
+	var chosen []Quote // Priority uses one source, but the quorum counts every usable one
+	switch options.Method {
+	case MethodMedian, "":
+		consensus.Method = MethodMedian
+		if len(quotes) >= options.MinSources {
+			price = medianPrice(quotes)
+		}
+	case MethodVolumeWeighted:
+		kept := quotes[:0]
+		for _, quote := range quotes {
+			if !quote.Volume.IsPositive() {
+				quote.Reason = RejectedNoVolume
+				consensus.Rejected = append(consensus.Rejected, quote)
+				continue
+			}
+			kept = append(kept, quote)
+		}
+		quotes = kept
+		if len(quotes) >= options.MinSources {
+			price = volumeWeightedPrice(quotes)
+		}
+	case MethodTrimmedMean:
+		if len(quotes) >= options.MinSources {
+			price = trimmedMeanPrice(quotes, options.TrimFraction)
+		}
+	case MethodPriority:
+		if len(quotes) >= options.MinSources {
+			chosen = []Quote{highestPriority(quotes, options.Priority)}
+			price = chosen[0].Price
+		}
+	default:
+		return nil, fmt.Errorf("unknown consensus method %q", options.Method)
+	}
+
+	if len(quotes) == 0 || len(quotes) < options.MinSources {
+		return nil, fmt.Errorf("%s: %d of %d required sources usable: %w", symbol, len(quotes), options.MinSources, ErrNoQuorum)
+	}
+
+	consensus.Price = price
+	consensus.Sources = quotes
+	if chosen != nil {
+		consensus.Sources = chosen
+	}
+	return consensus, nil
+}
+
+func (o Options) maxAge(source string) time.Duration {
+	if maxAge, ok := o.SourceMaxAge[source]; ok {
+		return maxAge
+	}
+	return o.MaxAge
+}
+
+// latestBySource keeps the newest feed per source, ordered by source for stable output
+func latestBySource(symbol string, feeds []*models.PriceFeed) []Quote {
+	latest := make(map[string]*models.PriceFeed)
+	for _, feed := range feeds {
+		if feed.Symbol != symbol {
+			continue
+		}
+		if current, ok := latest[feed.Source]; !ok || feed.Timestamp.After(current.Timestamp) {
+			latest[feed.Source] = feed
+		}
+	}
+
+	quotes := make([]Quote, 0, len(latest))
+	for source, feed := range latest {
+		quote := Quote{
+			Source:    source,
+			FeedID:    feed.FeedID,
+			Price:     feed.Price,
+			Timestamp: feed.Timestamp,
+		}
+		if feed.Volume24h != nil {
+			quote.Volume = *feed.Volume24h
+		}
+		quotes = append(quotes, quote)
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Source < quotes[j].Source })
+	return quotes
+}
+
+func sortedPrices(quotes []Quote) []decimal.Decimal {
+	prices := make([]decimal.Decimal, len(quotes))
+	for i, quote := range quotes {
+		prices[i] = quote.Price
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LessThan(prices[j]) })
+	return prices
+}
+
+func medianPrice(quotes []Quote) decimal.Decimal {
+	prices := sortedPrices(quotes)
+	if len(prices) == 0 {
+		return decimal.Zero
+	}
+
+	middle := len(prices) / 2
+	if len(prices)%2 == 1 {
+		return prices[middle]
+	}
+	return prices[middle-1].Add(prices[middle]).Div(decimal.NewFromInt(2))
+}
+
+func volumeWeightedPrice(quotes []Quote) decimal.Decimal {
+	var weighted, total decimal.Decimal
+	for _, quote := range quotes {
+		weighted = weighted.Add(quote.Price.Mul(quote.Volume))
+		total = total.Add(quote.Volume)
+	}
+	if total.IsZero() {
+		return decimal.Zero
+	}
+	return weighted.Div(total)
+}
+
+// trimmedMeanPrice drops the given fraction of quotes from each end before averaging,
+// falling back to the median when trimming would leave nothing
+func trimmedMeanPrice(quotes []Quote, fraction float64) decimal.Decimal {
+	if fraction <= 0 {
+		fraction = 0.1
+	}
+
+	prices := sortedPrices(quotes)
+	trim := int(float64(len(prices)) * fraction)
+	if len(prices)-2*trim <= 0 {
+		return medianPrice(quotes)
+	}
+
+	kept := prices[trim : len(prices)-trim]
+	sum := decimal.Zero
+	for _, price := range kept {
+		sum = sum.Add(price)
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(kept))))
+}
+
+// highestPriority returns the quote from the most preferred source; sources missing from
+// the priority list rank after listed ones, in source order
+func highestPriority(quotes []Quote, priority []string) Quote {
+	for _, source := range priority {
+		for _, quote := range quotes {
+			if quote.Source == source {
+				return quote
+			}
+		}
+	}
+	return quotes[0]
+}
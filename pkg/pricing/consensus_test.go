@@ -0,0 +1,239 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func feed(source, price string, age time.Duration) *models.PriceFeed {
+	return &models.PriceFeed{
+		FeedID:    source + "-" + price,
+		Symbol:    "BTC-USD",
+		Source:    source,
+		Price:     decimal.RequireFromString(price),
+		Timestamp: now.Add(-age),
+	}
+}
+
+func withVolume(f *models.PriceFeed, volume string) *models.PriceFeed {
+	v := decimal.RequireFromString(volume)
+	f.Volume24h = &v
+	return f
+}
+
+func sources(quotes []Quote) []string {
+	var result []string
+	for _, quote := range quotes {
+		result = append(result, quote.Source)
+	}
+	return result
+}
+
+func TestCompute_Median(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("binance", "100", time.Second),
+		feed("coinbase", "102", time.Second),
+		feed("kraken", "101", time.Second),
+		feed("bitstamp", "103", time.Second),
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{Method: MethodMedian})
+
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("101.5").Equal(consensus.Price))
+	assert.Equal(t, []string{"binance", "bitstamp", "coinbase", "kraken"}, sources(consensus.Sources))
+}
+
+func TestCompute_UsesLatestFeedPerSource(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("coinbase", "90", 10*time.Second),
+		feed("coinbase", "100", time.Second),
+		feed("other-symbol", "1", time.Second),
+	}
+	feeds[2].Symbol = "ETH-USD"
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{})
+
+	require.NoError(t, err)
+	assert.Equal(t, MethodMedian, consensus.Method)
+	assert.True(t, decimal.NewFromInt(100).Equal(consensus.Price))
+	assert.Equal(t, []string{"coinbase"}, sources(consensus.Sources))
+}
+
+func TestCompute_VolumeWeighted(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		withVolume(feed("binance", "100", time.Second), "3"),
+		withVolume(feed("coinbase", "200", time.Second), "1"),
+		feed("kraken", "1000", time.Second),
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{Method: MethodVolumeWeighted})
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(125).Equal(consensus.Price))
+	require.Len(t, consensus.Rejected, 1)
+	assert.Equal(t, RejectedNoVolume, consensus.Rejected[0].Reason)
+}
+
+func TestCompute_TrimmedMean(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("a", "1", time.Second),
+		feed("b", "100", time.Second),
+		feed("c", "101", time.Second),
+		feed("d", "102", time.Second),
+		feed("e", "500", time.Second),
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{Method: MethodTrimmedMean, TrimFraction: 0.2})
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(101).Equal(consensus.Price))
+}
+
+func TestCompute_PriorityFallsBackPastStaleSources(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("primary", "100", time.Minute),
+		feed("secondary", "101", time.Second),
+		feed("tertiary", "102", time.Second),
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{
+		Method:   MethodPriority,
+		MaxAge:   10 * time.Second,
+		Priority: []string{"primary", "secondary", "tertiary"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(101).Equal(consensus.Price))
+	assert.Equal(t, []string{"secondary"}, sources(consensus.Sources))
+	require.Len(t, consensus.Rejected, 1)
+	assert.Equal(t, RejectedStale, consensus.Rejected[0].Reason)
+}
+
+func TestCompute_PriorityCountsEveryUsableSourceForQuorum(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("primary", "100", time.Second),
+		feed("secondary", "101", time.Second),
+		feed("tertiary", "102", time.Hour),
+	}
+	options := Options{
+		Method:     MethodPriority,
+		MaxAge:     10 * time.Second,
+		Priority:   []string{"primary", "secondary", "tertiary"},
+		MinSources: 2,
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, options)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(consensus.Price))
+	assert.Equal(t, []string{"primary"}, sources(consensus.Sources))
+
+	options.MinSources = 3
+	_, err = Compute("BTC-USD", feeds, now, options)
+	assert.ErrorIs(t, err, ErrNoQuorum, "The stale source does not count toward the quorum")
+}
+
+func TestCompute_PerSourceStalenessOverride(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("fast", "100", 5*time.Second),
+		feed("slow", "101", 30*time.Second),
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{
+		MaxAge:       2 * time.Second,
+		SourceMaxAge: map[string]time.Duration{"slow": time.Minute},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slow"}, sources(consensus.Sources))
+}
+
+func TestCompute_RejectsOutliers(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("a", "100", time.Second),
+		feed("b", "101", time.Second),
+		feed("c", "99", time.Second),
+		feed("d", "150", time.Second),
+	}
+
+	consensus, err := Compute("BTC-USD", feeds, now, Options{MaxDeviation: 0.05})
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(consensus.Price))
+	require.Len(t, consensus.Rejected, 1)
+	assert.Equal(t, "d", consensus.Rejected[0].Source)
+	assert.Equal(t, RejectedOutlier, consensus.Rejected[0].Reason)
+}
+
+func TestCompute_RequiresQuorum(t *testing.T) {
+	feeds := []*models.PriceFeed{
+		feed("a", "100", time.Second),
+		feed("b", "101", time.Hour),
+	}
+
+	_, err := Compute("BTC-USD", feeds, now, Options{MaxAge: time.Minute, MinSources: 2})
+	assert.True(t, errors.Is(err, ErrNoQuorum))
+
+	_, err = Compute("BTC-USD", nil, now, Options{})
+	assert.True(t, errors.Is(err, ErrNoQuorum))
+
+	_, err = Compute("BTC-USD", feeds, now, Options{Method: "mode"})
+	assert.Error(t, err)
+}
+
+// queryingRepository returns feeds from Query and records the query it was given
+type queryingRepository struct {
+	interfaces.PriceFeedRepository
+	feeds []*models.PriceFeed
+	query *models.PriceFeedQuery
+}
+
+func (r *queryingRepository) Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error) {
+	r.query = query
+	return r.feeds, nil
+}
+
+func TestPricer_QueriesLoosestStalenessWindow(t *testing.T) {
+	repo := &queryingRepository{feeds: []*models.PriceFeed{feed("a", "100", time.Second)}}
+	pricer := NewPricer(repo, Options{
+		MaxAge:       10 * time.Second,
+		SourceMaxAge: map[string]time.Duration{"slow": time.Minute},
+	})
+	pricer.now = func() time.Time { return now }
+
+	consensus, err := pricer.Price(context.Background(), "BTC-USD")
+
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(consensus.Price))
+	require.NotNil(t, repo.query.TimestampFrom)
+	assert.Equal(t, now.Add(-time.Minute), *repo.query.TimestampFrom)
+	assert.Equal(t, "BTC-USD", *repo.query.Symbol)
+}
+
+func TestPricer_UnboundedSourcesReadTheLookback(t *testing.T) {
+	repo := &queryingRepository{feeds: []*models.PriceFeed{feed("a", "100", time.Second)}}
+	pricer := NewPricer(repo, Options{
+		SourceMaxAge: map[string]time.Duration{"fast": 5 * time.Second},
+	})
+	pricer.now = func() time.Time { return now }
+
+	_, err := pricer.Price(context.Background(), "BTC-USD")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-DefaultLookback), *repo.query.TimestampFrom,
+		"A tight per-source SLA should not shrink the window for sources without one")
+
+	pricer.options.Lookback = 10 * time.Minute
+	_, err = pricer.Price(context.Background(), "BTC-USD")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-10*time.Minute), *repo.query.TimestampFrom)
+}
@@ -0,0 +1,72 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+// DefaultLookback bounds the feeds Pricer reads for sources without a staleness cutoff
+const DefaultLookback = time.Minute
+
+// Pricer computes consensus prices from the feeds stored in a PriceFeedRepository
+type Pricer struct {
+	repo    interfaces.PriceFeedRepository
+	options Options
+	now     func() time.Time
+}
+
+func NewPricer(repo interfaces.PriceFeedRepository, options Options) *Pricer {
+	return &Pricer{
+		repo:    repo,
+		options: options,
+		now:     time.Now,
+	}
+}
+
+// Price reads the feeds recent enough to pass the loosest staleness cutoff and computes
+// the consensus over each source's latest one. A source without a cutoff only
+// contributes quotes from within Options.Lookback.
+func (p *Pricer) Price(ctx context.Context, symbol string) (*Consensus, error) {
+	now := p.now()
+	from := now.Add(-p.lookback())
+
+	feeds, err := p.repo.Query(ctx, &models.PriceFeedQuery{
+		Symbol:        &symbol,
+		TimestampFrom: &from,
+		SortBy:        "timestamp",
+		SortOrder:     "desc",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load price feeds for consensus: %w", err)
+	}
+
+	return Compute(symbol, feeds, now, p.options)
+}
+
+func (p *Pricer) lookback() time.Duration {
+	lookback := p.options.MaxAge
+	unbounded := lookback <= 0
+	for _, maxAge := range p.options.SourceMaxAge {
+		if maxAge <= 0 {
+			unbounded = true
+		}
+		if maxAge > lookback {
+			lookback = maxAge
+		}
+	}
+
+	if unbounded {
+		fallback := p.options.Lookback
+		if fallback <= 0 {
+			fallback = DefaultLookback
+		}
+		if fallback > lookback {
+			lookback = fallback
+		}
+	}
+	return lookback
+}
@@ -0,0 +1,84 @@
+package quality
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type streamKey struct {
+	source string
+	symbol string
+}
+
+// GapDetector tracks the last sequence number seen per (source, symbol) and reports
+// gaps and out-of-order arrivals. State is in memory, so after a restart the first
+// message of each stream sets a new baseline rather than being compared.
+type GapDetector struct {
+	mu   sync.Mutex
+	last map[streamKey]int64
+	now  func() time.Time
+}
+
+func NewGapDetector() *GapDetector {
+	return &GapDetector{
+		last: make(map[streamKey]int64),
+		now:  time.Now,
+	}
+}
+
+// Observe records a sequence number and returns the quality event it reveals, or nil if
+// it is the next expected one. Late arrivals do not move the stream backwards.
+func (d *GapDetector) Observe(source, symbol string, sequence int64) *models.DataQualityEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := streamKey{source: source, symbol: symbol}
+	last, seen := d.last[key]
+	if !seen {
+		d.last[key] = sequence
+		return nil
+	}
+
+	expected := last + 1
+	switch {
+	case sequence == expected:
+		d.last[key] = sequence
+		return nil
+	case sequence > expected:
+		d.last[key] = sequence
+		return d.event(models.QualitySequenceGap, key, expected, sequence, sequence-expected)
+	default:
+		return d.event(models.QualityOutOfOrder, key, expected, sequence, 0)
+	}
+}
+
+// ObserveFeed is Observe for a price feed; feeds without a sequence are ignored
+func (d *GapDetector) ObserveFeed(feed *models.PriceFeed) *models.DataQualityEvent {
+	if feed.Sequence == nil {
+		return nil
+	}
+	return d.Observe(feed.Source, feed.Symbol, *feed.Sequence)
+}
+
+// Reset forgets a stream, e.g. after a source announces a sequence reset
+func (d *GapDetector) Reset(source, symbol string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.last, streamKey{source: source, symbol: symbol})
+}
+
+func (d *GapDetector) event(eventType models.DataQualityEventType, key streamKey, expected, received, missing int64) *models.DataQualityEvent {
+	return &models.DataQualityEvent{
+		EventID:          uuid.New().String(),
+		Type:             eventType,
+		Source:           key.source,
+		Symbol:           key.symbol,
+		ExpectedSequence: expected,
+		ReceivedSequence: received,
+		Missing:          missing,
+		DetectedAt:       d.now(),
+	}
+}
@@ -0,0 +1,67 @@
+package quality
+
+import (
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGapDetector_InOrderSequencesProduceNoEvents(t *testing.T) {
+	d := NewGapDetector()
+	for seq := int64(100); seq < 110; seq++ {
+		assert.Nil(t, d.Observe("coinbase", "BTC-USD", seq))
+	}
+}
+
+func TestGapDetector_ReportsGap(t *testing.T) {
+	d := NewGapDetector()
+	d.Observe("coinbase", "BTC-USD", 1)
+
+	event := d.Observe("coinbase", "BTC-USD", 5)
+
+	require.NotNil(t, event)
+	assert.Equal(t, models.QualitySequenceGap, event.Type)
+	assert.Equal(t, int64(2), event.ExpectedSequence)
+	assert.Equal(t, int64(5), event.ReceivedSequence)
+	assert.Equal(t, int64(3), event.Missing)
+	assert.Nil(t, d.Observe("coinbase", "BTC-USD", 6), "The gap moves the stream forward")
+}
+
+func TestGapDetector_ReportsOutOfOrderWithoutRewinding(t *testing.T) {
+	d := NewGapDetector()
+	d.Observe("coinbase", "BTC-USD", 10)
+
+	event := d.Observe("coinbase", "BTC-USD", 7)
+
+	require.NotNil(t, event)
+	assert.Equal(t, models.QualityOutOfOrder, event.Type)
+	assert.Equal(t, int64(11), event.ExpectedSequence)
+	assert.Nil(t, d.Observe("coinbase", "BTC-USD", 11))
+}
+
+func TestGapDetector_TracksStreamsIndependently(t *testing.T) {
+	d := NewGapDetector()
+	d.Observe("coinbase", "BTC-USD", 1)
+	d.Observe("kraken", "BTC-USD", 500)
+	d.Observe("coinbase", "ETH-USD", 40)
+
+	assert.Nil(t, d.Observe("coinbase", "BTC-USD", 2))
+	assert.Nil(t, d.Observe("kraken", "BTC-USD", 501))
+	assert.Nil(t, d.Observe("coinbase", "ETH-USD", 41))
+
+	d.Reset("coinbase", "BTC-USD")
+	assert.Nil(t, d.Observe("coinbase", "BTC-USD", 1), "A reset stream takes a new baseline")
+}
+
+func TestGapDetector_ObserveFeedIgnoresUnsequencedFeeds(t *testing.T) {
+	d := NewGapDetector()
+	seq := int64(1)
+	d.ObserveFeed(&models.PriceFeed{Source: "coinbase", Symbol: "BTC-USD", Sequence: &seq})
+
+	assert.Nil(t, d.ObserveFeed(&models.PriceFeed{Source: "coinbase", Symbol: "BTC-USD"}))
+
+	seq = 3
+	assert.NotNil(t, d.ObserveFeed(&models.PriceFeed{Source: "coinbase", Symbol: "BTC-USD", Sequence: &seq}))
+}
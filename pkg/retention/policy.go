@@ -0,0 +1,127 @@
+package retention
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+)
+
+type Entity string
+
+const (
+	EntityPriceFeeds  Entity = "price_feeds"
+	EntityCandles     Entity = "candles"
+	EntitySnapshots   Entity = "snapshots"
+	EntityDataQuality Entity = "data_quality_events"
+)
+
+// Policy keeps one entity, or one candle interval, for MaxAge. With Downsample set,
+// expiring price feeds or candles are first rolled up into candles of that interval.
+type Policy struct {
+	Entity     Entity
+	Interval   models.CandleInterval // Candles only
+	MaxAge     time.Duration         // Zero keeps data forever
+	Downsample models.CandleInterval
+}
+
+func (p Policy) String() string {
+	name := string(p.Entity)
+	if p.Interval != "" {
+		name += ":" + string(p.Interval)
+	}
+	return name
+}
+
+func (p Policy) Validate() error {
+	switch p.Entity {
+	case EntityCandles:
+		if p.Interval.Duration() <= 0 {
+			return fmt.Errorf("%s: candle policies need a known interval", p)
+		}
+	case EntityPriceFeeds, EntitySnapshots, EntityDataQuality:
+		if p.Interval != "" {
+			return fmt.Errorf("%s: only candle policies take an interval", p)
+		}
+	default:
+		return fmt.Errorf("unknown retention entity %q", p.Entity)
+	}
+
+	if p.MaxAge < 0 {
+		return fmt.Errorf("%s: negative max age", p)
+	}
+	if p.Downsample == "" {
+		return nil
+	}
+
+	target := p.Downsample.Duration()
+	switch {
+	case p.Entity != EntityPriceFeeds && p.Entity != EntityCandles:
+		return fmt.Errorf("%s: only price feeds and candles can be downsampled", p)
+	case target <= 0:
+		return fmt.Errorf("%s: unknown downsample interval %q", p, p.Downsample)
+	case p.MaxAge == 0:
+		return fmt.Errorf("%s: downsampling needs a max age", p)
+	case p.Entity == EntityCandles && (target <= p.Interval.Duration() || target%p.Interval.Duration() != 0):
+		return fmt.Errorf("%s: cannot downsample to %s", p, p.Downsample)
+	}
+	return nil
+}
+
+// ParsePolicies reads comma-separated policies of the form entity[:interval]=age[>downsample],
+// e.g. "price_feeds=7d>1m,candles:1m=90d>1h,candles:1h=forever,snapshots=30d".
+// Ages are Go durations, whole days such as "7d", or "forever".
+func ParsePolicies(spec string) ([]Policy, error) {
+	var policies []Policy
+	for _, item := range strings.Split(spec, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		target, rule, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("retention policy %q: expected entity=age", item)
+		}
+
+		var policy Policy
+		entity, interval, _ := strings.Cut(strings.TrimSpace(target), ":")
+		policy.Entity = Entity(entity)
+		policy.Interval = models.CandleInterval(interval)
+
+		age, downsample, _ := strings.Cut(strings.TrimSpace(rule), ">")
+		maxAge, err := ParseAge(strings.TrimSpace(age))
+		if err != nil {
+			return nil, fmt.Errorf("retention policy %q: %w", item, err)
+		}
+		policy.MaxAge = maxAge
+		policy.Downsample = models.CandleInterval(strings.TrimSpace(downsample))
+
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// ParseAge reads an age as whole days ("30d"), a Go duration, or "forever", which is zero
+func ParseAge(value string) (time.Duration, error) {
+	if value == "forever" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return age, nil
+}
@@ -0,0 +1,37 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("price_feeds=7d>1m, candles:1m=90d>1h, candles:1h=forever, snapshots=36h")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Policy{
+		{Entity: EntityPriceFeeds, MaxAge: 7 * 24 * time.Hour, Downsample: models.Interval1m},
+		{Entity: EntityCandles, Interval: models.Interval1m, MaxAge: 90 * 24 * time.Hour, Downsample: models.Interval1h},
+		{Entity: EntityCandles, Interval: models.Interval1h},
+		{Entity: EntitySnapshots, MaxAge: 36 * time.Hour},
+	}, policies)
+}
+
+func TestParsePolicies_RejectsInvalidPolicies(t *testing.T) {
+	for _, spec := range []string{
+		"ticks=7d",
+		"candles=7d",
+		"snapshots:1m=7d",
+		"price_feeds=soon",
+		"snapshots=7d>1h",
+		"candles:1h=30d>5m",
+		"price_feeds=forever>1m",
+	} {
+		_, err := ParsePolicies(spec)
+		assert.Error(t, err, spec)
+	}
+}
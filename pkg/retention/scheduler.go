@@ -0,0 +1,332 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/ohlc"
+	"github.com/sirupsen/logrus"
+)
+
+// lockKey is shared by every replica so only one applies the policies at a time
+const lockKey = "retention"
+
+type Repositories struct {
+	PriceFeeds  interfaces.PriceFeedRepository
+	Candles     interfaces.CandleRepository
+	Snapshots   interfaces.MarketSnapshotRepository
+	DataQuality interfaces.DataQualityRepository
+}
+
+type Options struct {
+	Interval time.Duration     // Time between runs; defaults to 1h
+	LockTTL  time.Duration     // Renewed every third of the TTL while a run lasts; defaults to 30m
+	Locker   interfaces.Locker // If set, only one replica runs at a time
+}
+
+// Result is the outcome of one policy in one run
+type Result struct {
+	Policy      string    `json:"policy"`
+	Cutoff      time.Time `json:"cutoff"`
+	Downsampled int       `json:"downsampled"` // Candles built before deleting
+	Deleted     int64     `json:"deleted"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type Report struct {
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Skipped     bool      `json:"skipped"`             // Another replica held the lock
+	LockLost    bool      `json:"lock_lost,omitempty"` // The lock expired mid-run; later policies were not applied
+	Results     []Result  `json:"results"`
+	Deleted     int64     `json:"deleted"`
+}
+
+// Scheduler applies retention policies on an interval. A policy whose downsampling fails
+// deletes nothing in that run, so data is never dropped before it has been rolled up.
+type Scheduler struct {
+	repos    Repositories
+	policies []Policy
+	options  Options
+	logger   *logrus.Logger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	report *Report
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewScheduler(repos Repositories, policies []Policy, options Options, logger *logrus.Logger) *Scheduler {
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+	if options.LockTTL <= 0 {
+		options.LockTTL = 30 * time.Minute
+	}
+
+	return &Scheduler{
+		repos:    repos,
+		policies: policies,
+		options:  options,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return fmt.Errorf("retention scheduler already started")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.runLoop(loopCtx, s.done)
+
+	s.logger.WithFields(logrus.Fields{
+		"interval": s.options.Interval,
+		"policies": len(s.policies),
+	}).Info("Retention scheduler started")
+	return nil
+}
+
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.logger.Info("Retention scheduler stopped")
+	return nil
+}
+
+func (s *Scheduler) runLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Run(ctx); err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Warn("Retention run failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report returns the last run's report, or nil before the first run
+func (s *Scheduler) Report() *Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.report
+}
+
+// Run applies every policy once. Policy failures are recorded in the report and do not
+// stop the remaining policies; the error only reports that the lock could not be taken.
+// The lock is renewed while the policies run, and losing it cancels the run so no
+// policy deletes while another replica may hold the lock.
+func (s *Scheduler) Run(ctx context.Context) (*Report, error) {
+	report := &Report{StartedAt: s.now()}
+
+	runCtx := ctx
+	if s.options.Locker != nil {
+		lock, err := s.options.Locker.Acquire(ctx, lockKey, s.options.LockTTL)
+		if errors.Is(err, interfaces.ErrLockNotAcquired) {
+			report.Skipped = true
+			report.CompletedAt = s.now()
+			return report, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire retention lock: %w", err)
+		}
+		defer func() {
+			if err := s.options.Locker.Release(context.Background(), lock); err != nil {
+				s.logger.WithError(err).Warn("Failed to release retention lock")
+			}
+		}()
+
+		var stopRenewing context.CancelFunc
+		runCtx, stopRenewing = s.keepLock(ctx, lock)
+		defer stopRenewing()
+	}
+
+	for _, policy := range s.policies {
+		if policy.MaxAge == 0 {
+			continue
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+
+		result := s.apply(runCtx, policy, report.StartedAt)
+		report.Results = append(report.Results, result)
+		report.Deleted += result.Deleted
+
+		logger := s.logger.WithFields(logrus.Fields{
+			"policy":      result.Policy,
+			"cutoff":      result.Cutoff,
+			"downsampled": result.Downsampled,
+			"deleted":     result.Deleted,
+		})
+		if result.Error != "" {
+			logger.WithField("error", result.Error).Warn("Retention policy failed")
+		} else {
+			logger.Info("Retention policy applied")
+		}
+	}
+
+	// Only keepLock cancels runCtx while ctx is live
+	report.LockLost = runCtx.Err() != nil && ctx.Err() == nil
+
+	report.CompletedAt = s.now()
+	s.mu.Lock()
+	s.report = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+// keepLock renews lock every third of its TTL until the returned cancel is called. The
+// returned context is cancelled once the lock is known lost or has gone a full TTL
+// without a successful renewal.
+func (s *Scheduler) keepLock(ctx context.Context, lock *interfaces.Lock) (context.Context, context.CancelFunc) {
+	runCtx, cancel := context.WithCancel(ctx)
+	ttl := s.options.LockTTL
+
+	go func() {
+		// Measured from before the acquire returned, like every renewal below
+		deadline := time.NewTimer(ttl)
+		defer deadline.Stop()
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-deadline.C:
+				s.logger.Error("Retention lock expired before it could be renewed, stopping the run")
+				cancel()
+				return
+			case <-ticker.C:
+			}
+
+			sent := time.Now()
+			err := s.options.Locker.Renew(runCtx, lock, ttl)
+			switch {
+			case err == nil:
+				deadline.Reset(time.Until(sent.Add(ttl)))
+			case errors.Is(err, interfaces.ErrLockNotHeld):
+				s.logger.WithError(err).Error("Lost retention lock, stopping the run")
+				cancel()
+				return
+			case runCtx.Err() == nil:
+				s.logger.WithError(err).Warn("Failed to renew retention lock")
+			}
+		}
+	}()
+
+	return runCtx, cancel
+}
+
+func (s *Scheduler) apply(ctx context.Context, policy Policy, now time.Time) Result {
+	cutoff := now.Add(-policy.MaxAge)
+	if policy.Downsample != "" {
+		// Delete whole downsampled candles only, so no partial one is rolled up twice
+		cutoff = policy.Downsample.Align(cutoff)
+	}
+	result := Result{Policy: policy.String(), Cutoff: cutoff}
+
+	if policy.Downsample != "" {
+		downsampled, err := s.downsample(ctx, policy, cutoff)
+		result.Downsampled = downsampled
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to downsample: %v", err)
+			return result
+		}
+	}
+
+	// Downsampling can outlast the lock; never delete without it
+	if err := ctx.Err(); err != nil {
+		result.Error = fmt.Sprintf("skipped delete: %v", err)
+		return result
+	}
+
+	deleted, err := s.delete(ctx, policy, cutoff)
+	result.Deleted = deleted
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (s *Scheduler) delete(ctx context.Context, policy Policy, cutoff time.Time) (int64, error) {
+	switch policy.Entity {
+	case EntityPriceFeeds:
+		return s.repos.PriceFeeds.DeleteOlderThan(ctx, cutoff)
+	case EntityCandles:
+		return s.repos.Candles.DeleteIntervalOlderThan(ctx, policy.Interval, cutoff)
+	case EntitySnapshots:
+		return s.repos.Snapshots.DeleteOlderThan(ctx, cutoff)
+	case EntityDataQuality:
+		return s.repos.DataQuality.DeleteOlderThan(ctx, cutoff)
+	default:
+		return 0, fmt.Errorf("unknown retention entity %q", policy.Entity)
+	}
+}
+
+// downsample fills the policy's coarser candles for every symbol with expiring rows, from
+// its oldest expiring row up to the cutoff. Candles that already exist are left alone.
+func (s *Scheduler) downsample(ctx context.Context, policy Policy, cutoff time.Time) (int, error) {
+	var (
+		source ohlc.Source
+		oldest []*models.LatestTimestamp
+		err    error
+	)
+	if policy.Entity == EntityPriceFeeds {
+		source = &ohlc.FeedSource{Feeds: s.repos.PriceFeeds}
+		oldest, err = s.repos.PriceFeeds.GetOldestTimestamps(ctx, cutoff)
+	} else {
+		source = &ohlc.ResampleSource{Candles: s.repos.Candles, AllowPartial: true}
+		oldest, err = s.repos.Candles.GetOldestIntervalTimestamps(ctx, policy.Interval, cutoff)
+	}
+	if err != nil {
+		return 0, err
+	}
+	backfiller := ohlc.NewBackfiller(s.repos.Candles, nil, []ohlc.Source{source}, ohlc.Options{}, s.logger)
+
+	var built int
+	for _, entry := range oldest {
+		report, err := backfiller.Backfill(ctx, entry.Symbol, policy.Downsample, entry.Timestamp, cutoff)
+		if err != nil {
+			return built, err
+		}
+		built += report.Filled[source.Name()]
+	}
+	return built, nil
+}
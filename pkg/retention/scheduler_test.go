@@ -0,0 +1,302 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+
+type fakeFeeds struct {
+	interfaces.PriceFeedRepository
+	feeds   []*models.PriceFeed
+	deleted []time.Time
+}
+
+func (f *fakeFeeds) Query(ctx context.Context, query *models.PriceFeedQuery) ([]*models.PriceFeed, error) {
+	var result []*models.PriceFeed
+	for _, feed := range f.feeds {
+		if query.Symbol != nil && feed.Symbol != *query.Symbol {
+			continue
+		}
+		if query.TimestampFrom != nil && feed.Timestamp.Before(*query.TimestampFrom) {
+			continue
+		}
+		if feed.Timestamp.Before(*query.TimestampTo) {
+			result = append(result, feed)
+		}
+	}
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+func (f *fakeFeeds) GetOldestTimestamps(ctx context.Context, before time.Time) ([]*models.LatestTimestamp, error) {
+	var oldest []*models.LatestTimestamp
+	seen := make(map[string]*models.LatestTimestamp)
+	for _, feed := range f.feeds {
+		if !feed.Timestamp.Before(before) {
+			continue
+		}
+		if entry, ok := seen[feed.Symbol]; ok {
+			if feed.Timestamp.Before(entry.Timestamp) {
+				entry.Timestamp = feed.Timestamp
+			}
+			continue
+		}
+		seen[feed.Symbol] = &models.LatestTimestamp{Symbol: feed.Symbol, Timestamp: feed.Timestamp}
+		oldest = append(oldest, seen[feed.Symbol])
+	}
+	return oldest, nil
+}
+
+func (f *fakeFeeds) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	f.deleted = append(f.deleted, timestamp)
+	var kept []*models.PriceFeed
+	for _, feed := range f.feeds {
+		if !feed.Timestamp.Before(timestamp) {
+			kept = append(kept, feed)
+		}
+	}
+	removed := len(f.feeds) - len(kept)
+	f.feeds = kept
+	return int64(removed), nil
+}
+
+type fakeCandles struct {
+	interfaces.CandleRepository
+	upserted  []*models.Candle
+	upsertErr error
+	deleted   map[models.CandleInterval]time.Time
+}
+
+func (f *fakeCandles) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	return nil, nil
+}
+
+func (f *fakeCandles) GetOldestIntervalTimestamps(ctx context.Context, interval models.CandleInterval, before time.Time) ([]*models.LatestTimestamp, error) {
+	return nil, nil
+}
+
+func (f *fakeCandles) Upsert(ctx context.Context, candle *models.Candle) error {
+	if f.upsertErr != nil {
+		return f.upsertErr
+	}
+	f.upserted = append(f.upserted, candle)
+	return nil
+}
+
+func (f *fakeCandles) DeleteIntervalOlderThan(ctx context.Context, interval models.CandleInterval, timestamp time.Time) (int64, error) {
+	f.deleted[interval] = timestamp
+	return 3, nil
+}
+
+type fakeSnapshots struct {
+	interfaces.MarketSnapshotRepository
+}
+
+func (f *fakeSnapshots) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	return 0, errors.New("table locked")
+}
+
+type fakeLocker struct {
+	interfaces.Locker
+	held     bool
+	released int
+
+	mu      sync.Mutex
+	lost    bool // Renewals report the lock taken over
+	renewed int
+}
+
+func (f *fakeLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (*interfaces.Lock, error) {
+	if f.held {
+		return nil, interfaces.ErrLockNotAcquired
+	}
+	return &interfaces.Lock{Key: key}, nil
+}
+
+func (f *fakeLocker) Release(ctx context.Context, lock *interfaces.Lock) error {
+	f.released++
+	return nil
+}
+
+func (f *fakeLocker) Renew(ctx context.Context, lock *interfaces.Lock, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lost {
+		return interfaces.ErrLockNotHeld
+	}
+	f.renewed++
+	return nil
+}
+
+// slowSnapshots deletes after a delay, or fails once the context is cancelled
+type slowSnapshots struct {
+	interfaces.MarketSnapshotRepository
+	delay time.Duration
+}
+
+func (f *slowSnapshots) DeleteOlderThan(ctx context.Context, timestamp time.Time) (int64, error) {
+	select {
+	case <-time.After(f.delay):
+		return 1, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func newTestScheduler(policies []Policy, locker interfaces.Locker) (*Scheduler, *fakeFeeds, *fakeCandles) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	feeds := &fakeFeeds{}
+	candles := &fakeCandles{deleted: make(map[models.CandleInterval]time.Time)}
+	scheduler := NewScheduler(Repositories{
+		PriceFeeds: feeds,
+		Candles:    candles,
+		Snapshots:  &fakeSnapshots{},
+	}, policies, Options{Locker: locker}, logger)
+	scheduler.now = func() time.Time { return now }
+	return scheduler, feeds, candles
+}
+
+func TestScheduler_DownsamplesBeforeDeleting(t *testing.T) {
+	scheduler, feeds, candles := newTestScheduler([]Policy{
+		{Entity: EntityPriceFeeds, MaxAge: 24 * time.Hour, Downsample: models.Interval1h},
+		{Entity: EntityCandles, Interval: models.Interval1h},
+	}, nil)
+
+	expired := now.Add(-26 * time.Hour)
+	for i, price := range []int64{100, 102, 98} {
+		feeds.feeds = append(feeds.feeds, &models.PriceFeed{
+			Symbol: "BTC-USD", Price: decimal.NewFromInt(price), Timestamp: expired.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	feeds.feeds = append(feeds.feeds, &models.PriceFeed{Symbol: "BTC-USD", Price: decimal.NewFromInt(101), Timestamp: now})
+
+	report, err := scheduler.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1, "Policies that keep data forever do nothing")
+	result := report.Results[0]
+	assert.Empty(t, result.Error)
+	assert.Equal(t, time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC), result.Cutoff, "The cutoff is aligned to the downsample interval")
+	assert.Equal(t, 1, result.Downsampled)
+	assert.Equal(t, int64(3), result.Deleted)
+	assert.Len(t, feeds.feeds, 1)
+
+	require.Len(t, candles.upserted, 1)
+	assert.True(t, decimal.NewFromInt(102).Equal(candles.upserted[0].High))
+	assert.True(t, decimal.NewFromInt(98).Equal(candles.upserted[0].Close))
+	assert.Equal(t, report, scheduler.Report())
+}
+
+func TestScheduler_DownsamplesEverySymbolWithExpiringRows(t *testing.T) {
+	scheduler, feeds, candles := newTestScheduler([]Policy{
+		{Entity: EntityPriceFeeds, MaxAge: 24 * time.Hour, Downsample: models.Interval1h},
+	}, nil)
+
+	expired := now.Add(-26 * time.Hour)
+	for _, symbol := range []string{"BTC-USD", "UNLISTED-USD"} {
+		feeds.feeds = append(feeds.feeds, &models.PriceFeed{Symbol: symbol, Price: decimal.NewFromInt(100), Timestamp: expired})
+	}
+	feeds.feeds = append(feeds.feeds, &models.PriceFeed{Symbol: "ETH-USD", Price: decimal.NewFromInt(100), Timestamp: now})
+
+	report, err := scheduler.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, 2, report.Results[0].Downsampled, "Symbols come from the expiring rows, not the symbol registry")
+	var symbols []string
+	for _, candle := range candles.upserted {
+		symbols = append(symbols, candle.Symbol)
+	}
+	assert.ElementsMatch(t, []string{"BTC-USD", "UNLISTED-USD"}, symbols)
+}
+
+func TestScheduler_KeepsDataWhenDownsamplingFails(t *testing.T) {
+	scheduler, feeds, candles := newTestScheduler([]Policy{
+		{Entity: EntityPriceFeeds, MaxAge: time.Hour, Downsample: models.Interval1m},
+		{Entity: EntitySnapshots, MaxAge: time.Hour},
+		{Entity: EntityCandles, Interval: models.Interval1m, MaxAge: time.Hour},
+	}, nil)
+	candles.upsertErr = errors.New("database down")
+	feeds.feeds = []*models.PriceFeed{{Symbol: "BTC-USD", Price: decimal.NewFromInt(100), Timestamp: now.Add(-2 * time.Hour)}}
+
+	report, err := scheduler.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 3)
+	assert.NotEmpty(t, report.Results[0].Error)
+	assert.Empty(t, feeds.deleted)
+	assert.NotEmpty(t, report.Results[1].Error, "Failures are reported per policy")
+	assert.Empty(t, report.Results[2].Error)
+	assert.Equal(t, now.Add(-time.Hour), candles.deleted[models.Interval1m])
+	assert.Equal(t, int64(3), report.Deleted)
+}
+
+func TestScheduler_SkipsRunWhenLockIsHeld(t *testing.T) {
+	locker := &fakeLocker{held: true}
+	scheduler, _, candles := newTestScheduler([]Policy{{Entity: EntityCandles, Interval: models.Interval1m, MaxAge: time.Hour}}, locker)
+
+	report, err := scheduler.Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Skipped)
+	assert.Empty(t, candles.deleted)
+
+	locker.held = false
+	report, err = scheduler.Run(context.Background())
+	require.NoError(t, err)
+	assert.False(t, report.Skipped)
+	assert.Equal(t, 1, locker.released)
+}
+
+func TestScheduler_RenewsLockWhilePoliciesRun(t *testing.T) {
+	locker := &fakeLocker{}
+	scheduler, _, candles := newTestScheduler([]Policy{
+		{Entity: EntitySnapshots, MaxAge: time.Hour},
+		{Entity: EntityCandles, Interval: models.Interval1m, MaxAge: time.Hour},
+	}, locker)
+	scheduler.options.LockTTL = 30 * time.Millisecond
+	scheduler.repos.Snapshots = &slowSnapshots{delay: 100 * time.Millisecond}
+
+	report, err := scheduler.Run(context.Background())
+	require.NoError(t, err)
+	assert.False(t, report.LockLost)
+	assert.Empty(t, report.Results[0].Error)
+	assert.Contains(t, candles.deleted, models.Interval1m, "A run longer than the TTL should keep going while renewals succeed")
+
+	locker.mu.Lock()
+	defer locker.mu.Unlock()
+	assert.GreaterOrEqual(t, locker.renewed, 2)
+}
+
+func TestScheduler_StopsDeletingWhenLockIsLost(t *testing.T) {
+	locker := &fakeLocker{lost: true}
+	scheduler, _, candles := newTestScheduler([]Policy{
+		{Entity: EntitySnapshots, MaxAge: time.Hour},
+		{Entity: EntityCandles, Interval: models.Interval1m, MaxAge: time.Hour},
+	}, locker)
+	scheduler.options.LockTTL = 30 * time.Millisecond
+	scheduler.repos.Snapshots = &slowSnapshots{delay: time.Second}
+
+	report, err := scheduler.Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.LockLost)
+	require.Len(t, report.Results, 1, "Policies after the lock was lost should not run")
+	assert.NotEmpty(t, report.Results[0].Error, "The in-flight delete should be cancelled")
+	assert.Empty(t, candles.deleted)
+	assert.Equal(t, 1, locker.released)
+}
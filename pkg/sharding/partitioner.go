@@ -0,0 +1,208 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/hashring"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// RebalanceFunc is called after membership or the active symbol set changes with the
+// symbols this member gained and lost; it runs on the partitioner's goroutine
+type RebalanceFunc func(assigned, revoked []string)
+
+type Options struct {
+	RefreshInterval time.Duration // Periodic rebalance, in addition to watch events
+	HashReplicas    int           // Virtual nodes per member
+	OnRebalance     RebalanceFunc
+}
+
+// SymbolPartitioner spreads SymbolRepository.GetActive symbols across the live replicas
+// of a service using a consistent hash ring, so each symbol has exactly one owner and
+// only the departed or joining member's share moves when membership changes.
+//
+// A replica counts itself as a member until its own registration is visible, so it owns
+// its share before registering; replicas reporting critical health, itself included, are
+// left out of the ring.
+type SymbolPartitioner struct {
+	discovery   interfaces.ServiceDiscoveryRepository
+	symbols     interfaces.SymbolRepository
+	serviceName string
+	memberID    string
+	options     Options
+	logger      *logrus.Logger
+
+	mu      sync.RWMutex
+	ring    *hashring.Ring
+	owned   map[string]bool
+	members []string
+}
+
+func NewSymbolPartitioner(discovery interfaces.ServiceDiscoveryRepository, symbols interfaces.SymbolRepository, serviceName, memberID string, options Options, logger *logrus.Logger) *SymbolPartitioner {
+	if options.RefreshInterval <= 0 {
+		options.RefreshInterval = 30 * time.Second
+	}
+
+	return &SymbolPartitioner{
+		discovery:   discovery,
+		symbols:     symbols,
+		serviceName: serviceName,
+		memberID:    memberID,
+		options:     options,
+		logger:      logger,
+		ring:        hashring.New(options.HashReplicas, memberID),
+		owned:       map[string]bool{},
+	}
+}
+
+// Start performs an initial assignment and keeps rebalancing until ctx is done
+func (p *SymbolPartitioner) Start(ctx context.Context) error {
+	if err := p.Rebalance(ctx); err != nil {
+		return err
+	}
+
+	events, err := p.discovery.Watch(ctx, p.serviceName)
+	if err != nil {
+		p.logger.WithError(err).WithField("service_name", p.serviceName).Warn("Failed to watch membership, falling back to polling")
+	}
+
+	go p.rebalanceLoop(ctx, events)
+	return nil
+}
+
+func (p *SymbolPartitioner) rebalanceLoop(ctx context.Context, events <-chan *interfaces.ServiceEvent) {
+	ticker := time.NewTicker(p.options.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+		}
+
+		if err := p.Rebalance(ctx); err != nil {
+			p.logger.WithError(err).WithField("service_name", p.serviceName).Warn("Failed to rebalance symbols")
+		}
+	}
+}
+
+// Rebalance recomputes ownership from current membership and active symbols
+func (p *SymbolPartitioner) Rebalance(ctx context.Context) error {
+	instances, err := p.discovery.Discover(ctx, p.serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to discover members: %w", err)
+	}
+
+	active, err := p.symbols.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active symbols: %w", err)
+	}
+
+	weights := map[string]int{p.memberID: 1}
+	for _, info := range instances {
+		if info.EffectiveHealth() == interfaces.HealthCritical {
+			delete(weights, info.ServiceID)
+			continue
+		}
+		weights[info.ServiceID] = info.EffectiveWeight()
+	}
+	ring := hashring.NewWeighted(p.options.HashReplicas, weights)
+
+	owned := map[string]bool{}
+	for _, symbol := range active {
+		if owner, _ := ring.Get(symbol.Symbol); owner == p.memberID {
+			owned[symbol.Symbol] = true
+		}
+	}
+
+	p.mu.Lock()
+	assigned, revoked := diff(p.owned, owned)
+	p.ring = ring
+	p.owned = owned
+	p.members = ring.Members()
+	p.mu.Unlock()
+
+	if len(assigned) > 0 || len(revoked) > 0 {
+		p.logger.WithFields(logrus.Fields{
+			"member_id": p.memberID,
+			"members":   ring.Len(),
+			"owned":     len(owned),
+			"assigned":  len(assigned),
+			"revoked":   len(revoked),
+		}).Info("Symbol ownership rebalanced")
+
+		if p.options.OnRebalance != nil {
+			p.options.OnRebalance(assigned, revoked)
+		}
+	}
+
+	return nil
+}
+
+// Owns reports whether this member is responsible for symbol
+func (p *SymbolPartitioner) Owns(symbol string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.owned[symbol]
+}
+
+// Owner returns the member responsible for symbol under the current membership
+func (p *SymbolPartitioner) Owner(symbol string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	owner, _ := p.ring.Get(symbol)
+	return owner
+}
+
+// OwnedSymbols returns the symbols assigned to this member in sorted order
+func (p *SymbolPartitioner) OwnedSymbols() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return sortedKeys(p.owned)
+}
+
+// Members returns the member IDs currently on the ring
+func (p *SymbolPartitioner) Members() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	members := make([]string, len(p.members))
+	copy(members, p.members)
+	return members
+}
+
+func diff(before, after map[string]bool) (assigned, revoked []string) {
+	for symbol := range after {
+		if !before[symbol] {
+			assigned = append(assigned, symbol)
+		}
+	}
+	for symbol := range before {
+		if !after[symbol] {
+			revoked = append(revoked, symbol)
+		}
+	}
+	sort.Strings(assigned)
+	sort.Strings(revoked)
+	return assigned, revoked
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
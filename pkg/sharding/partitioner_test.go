@@ -0,0 +1,151 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memberDiscovery serves a mutable member list; unused methods fall through to the nil interface
+type memberDiscovery struct {
+	interfaces.ServiceDiscoveryRepository
+	mu      sync.Mutex
+	members []*interfaces.ServiceInfo
+}
+
+func (d *memberDiscovery) Discover(ctx context.Context, serviceName string) ([]*interfaces.ServiceInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*interfaces.ServiceInfo(nil), d.members...), nil
+}
+
+func (d *memberDiscovery) set(ids ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.members = nil
+	for _, id := range ids {
+		d.members = append(d.members, &interfaces.ServiceInfo{ServiceName: "market-data-simulator", ServiceID: id})
+	}
+}
+
+// activeSymbols serves a fixed active symbol list
+type activeSymbols struct {
+	interfaces.SymbolRepository
+	symbols []*models.Symbol
+}
+
+func (s *activeSymbols) GetActive(ctx context.Context) ([]*models.Symbol, error) {
+	return s.symbols, nil
+}
+
+func newSymbols(n int) *activeSymbols {
+	repo := &activeSymbols{}
+	for i := 0; i < n; i++ {
+		repo.symbols = append(repo.symbols, &models.Symbol{Symbol: fmt.Sprintf("SYM%d-USD", i), IsActive: true})
+	}
+	return repo
+}
+
+func newTestPartitioner(discovery interfaces.ServiceDiscoveryRepository, symbols interfaces.SymbolRepository, memberID string, onRebalance RebalanceFunc) *SymbolPartitioner {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	return NewSymbolPartitioner(discovery, symbols, "market-data-simulator", memberID, Options{OnRebalance: onRebalance}, logger)
+}
+
+func TestSymbolPartitioner_EverySymbolHasExactlyOneOwner(t *testing.T) {
+	discovery := &memberDiscovery{}
+	discovery.set("a", "b", "c")
+	symbols := newSymbols(200)
+	ctx := context.Background()
+
+	owners := map[string]int{}
+	for _, id := range []string{"a", "b", "c"} {
+		p := newTestPartitioner(discovery, symbols, id, nil)
+		require.NoError(t, p.Rebalance(ctx))
+		for _, symbol := range p.OwnedSymbols() {
+			owners[symbol]++
+		}
+		assert.NotEmpty(t, p.OwnedSymbols(), "Member %s should own some symbols", id)
+	}
+
+	assert.Len(t, owners, 200)
+	for symbol, count := range owners {
+		assert.Equal(t, 1, count, "Symbol %s should have exactly one owner", symbol)
+	}
+}
+
+func TestSymbolPartitioner_CallbackOnMembershipChange(t *testing.T) {
+	discovery := &memberDiscovery{}
+	discovery.set("a")
+	ctx := context.Background()
+
+	var assigned, revoked []string
+	p := newTestPartitioner(discovery, newSymbols(100), "a", func(a, r []string) {
+		assigned, revoked = a, r
+	})
+
+	require.NoError(t, p.Rebalance(ctx))
+	assert.Len(t, assigned, 100, "Sole member should be assigned everything")
+	assert.Empty(t, revoked)
+
+	discovery.set("a", "b")
+	require.NoError(t, p.Rebalance(ctx))
+	assert.Empty(t, assigned)
+	assert.NotEmpty(t, revoked, "A joining member should take over part of the symbols")
+	for _, symbol := range revoked {
+		assert.Equal(t, "b", p.Owner(symbol))
+		assert.False(t, p.Owns(symbol))
+	}
+	handedOff := revoked
+
+	discovery.set("a")
+	require.NoError(t, p.Rebalance(ctx))
+	assert.ElementsMatch(t, handedOff, assigned, "Symbols should come back when the member leaves")
+}
+
+func TestSymbolPartitioner_CountsItselfBeforeRegistration(t *testing.T) {
+	discovery := &memberDiscovery{}
+	p := newTestPartitioner(discovery, newSymbols(10), "a", nil)
+
+	require.NoError(t, p.Rebalance(context.Background()))
+
+	assert.Equal(t, []string{"a"}, p.Members())
+	assert.Len(t, p.OwnedSymbols(), 10)
+}
+
+func TestSymbolPartitioner_SkipsCriticalMembers(t *testing.T) {
+	discovery := &memberDiscovery{}
+	discovery.set("a", "b")
+	discovery.members[1].Health = interfaces.HealthCritical
+
+	p := newTestPartitioner(discovery, newSymbols(10), "a", nil)
+	require.NoError(t, p.Rebalance(context.Background()))
+
+	assert.Equal(t, []string{"a"}, p.Members())
+}
+
+func TestSymbolPartitioner_CriticalSelfReleasesEverything(t *testing.T) {
+	discovery := &memberDiscovery{}
+	discovery.set("a", "b")
+
+	var revoked []string
+	p := newTestPartitioner(discovery, newSymbols(10), "a", func(_, lost []string) { revoked = lost })
+	ctx := context.Background()
+	require.NoError(t, p.Rebalance(ctx))
+	owned := p.OwnedSymbols()
+	require.NotEmpty(t, owned)
+
+	discovery.members[0].Health = interfaces.HealthCritical
+	require.NoError(t, p.Rebalance(ctx))
+
+	assert.Equal(t, []string{"b"}, p.Members())
+	assert.Empty(t, p.OwnedSymbols())
+	assert.ElementsMatch(t, owned, revoked)
+}
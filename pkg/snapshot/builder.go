@@ -0,0 +1,287 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+type Options struct {
+	Window         time.Duration         // Period the change and volume cover; defaults to 24h
+	CandleInterval models.CandleInterval // Candles summed over the window; defaults to 1h
+	BuildInterval  time.Duration         // Scheduled builds; defaults to 1m
+	Symbols        []string              // Scheduled symbols; defaults to those with feeds within the window
+	Locker         interfaces.Locker     // If set, only one replica builds each scheduled round
+}
+
+type lastBuild struct {
+	feedID    string
+	timestamp time.Time
+}
+
+// Builder derives market snapshots from the latest price feed and the candles covering
+// the window before it. Spread, change and percentage change all come from the same
+// feed and opening candle, so they stay consistent with LastPrice and each other.
+type Builder struct {
+	feeds     interfaces.PriceFeedRepository
+	candles   interfaces.CandleRepository
+	snapshots interfaces.MarketSnapshotRepository
+	options   Options
+	logger    *logrus.Logger
+	now       func() time.Time
+
+	mu     sync.Mutex
+	built  map[string]lastBuild
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewBuilder(feeds interfaces.PriceFeedRepository, candles interfaces.CandleRepository, snapshots interfaces.MarketSnapshotRepository, options Options, logger *logrus.Logger) *Builder {
+	if options.Window <= 0 {
+		options.Window = 24 * time.Hour
+	}
+	if options.CandleInterval == "" {
+		options.CandleInterval = models.Interval1h
+	}
+	if options.BuildInterval <= 0 {
+		options.BuildInterval = time.Minute
+	}
+
+	return &Builder{
+		feeds:     feeds,
+		candles:   candles,
+		snapshots: snapshots,
+		options:   options,
+		logger:    logger,
+		now:       time.Now,
+		built:     make(map[string]lastBuild),
+	}
+}
+
+// Build snapshots the symbol's latest price feed. It returns nil without writing when
+// that feed was already snapshotted.
+func (b *Builder) Build(ctx context.Context, symbol string) (*models.MarketSnapshot, error) {
+	feed, err := b.feeds.GetLatestBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return b.BuildFromFeed(ctx, feed)
+}
+
+// BuildFromFeed snapshots a newly stored price feed. Feeds older than the last one
+// snapshotted for the symbol are skipped and return nil.
+func (b *Builder) BuildFromFeed(ctx context.Context, feed *models.PriceFeed) (*models.MarketSnapshot, error) {
+	if !b.isNewer(feed) {
+		return nil, nil
+	}
+
+	from := feed.Timestamp.Add(-b.options.Window)
+	candles, err := b.candles.Query(ctx, &models.CandleQuery{
+		Symbol:        &feed.Symbol,
+		Interval:      &b.options.CandleInterval,
+		StartTimeFrom: &from,
+		StartTimeTo:   &feed.Timestamp,
+		SortBy:        "start_time",
+		SortOrder:     "ASC",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := Derive(feed, candles)
+	if err := b.snapshots.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	if b.newerLocked(feed) {
+		b.built[feed.Symbol] = lastBuild{feedID: feed.FeedID, timestamp: feed.Timestamp}
+	}
+	b.mu.Unlock()
+	return snapshot, nil
+}
+
+func (b *Builder) isNewer(feed *models.PriceFeed) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.newerLocked(feed)
+}
+
+func (b *Builder) newerLocked(feed *models.PriceFeed) bool {
+	last, ok := b.built[feed.Symbol]
+	if !ok {
+		return true
+	}
+	return feed.FeedID != last.feedID && !feed.Timestamp.Before(last.timestamp)
+}
+
+// Derive builds a snapshot from a price feed and the candles of the window before it,
+// oldest first. Without candles the feed's own 24h volume is used and no change is set.
+// A crossed quote leaves the spread unset rather than negative.
+func Derive(feed *models.PriceFeed, candles []*models.Candle) *models.MarketSnapshot {
+	snapshot := &models.MarketSnapshot{
+		Symbol:    feed.Symbol,
+		LastPrice: feed.Price,
+		Bid:       feed.Bid,
+		Ask:       feed.Ask,
+		Volume24h: feed.Volume24h,
+		Timestamp: feed.Timestamp,
+	}
+
+	if feed.Bid != nil && feed.Ask != nil && !feed.Bid.GreaterThan(*feed.Ask) {
+		spread := feed.Ask.Sub(*feed.Bid)
+		snapshot.Spread = &spread
+	}
+
+	metadata := map[string]string{"feed_id": feed.FeedID, "source": feed.Source}
+
+	if len(candles) > 0 {
+		volume := decimal.Zero
+		for _, candle := range candles {
+			volume = volume.Add(candle.Volume)
+		}
+		snapshot.Volume24h = &volume
+
+		open := candles[0].Open
+		if open.IsPositive() {
+			change := feed.Price.Sub(open)
+			percent := change.Div(open).Mul(decimal.NewFromInt(100)).Round(4)
+			snapshot.PriceChange24h = &change
+			snapshot.PriceChangePercent24h = &percent
+		}
+		metadata["open_price"] = open.String()
+		metadata["candles"] = strconv.Itoa(len(candles))
+	}
+
+	snapshot.Metadata, _ = json.Marshal(metadata)
+	return snapshot
+}
+
+func (b *Builder) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel != nil {
+		return fmt.Errorf("snapshot builder already started")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+
+	go b.buildLoop(loopCtx, b.done)
+
+	b.logger.WithFields(logrus.Fields{
+		"build_interval":  b.options.BuildInterval,
+		"window":          b.options.Window,
+		"candle_interval": b.options.CandleInterval,
+	}).Info("Snapshot builder started")
+	return nil
+}
+
+func (b *Builder) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.cancel, b.done = nil, nil
+	b.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	b.logger.Info("Snapshot builder stopped")
+	return nil
+}
+
+func (b *Builder) buildLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(b.options.BuildInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.BuildAll(ctx); err != nil && ctx.Err() == nil {
+			b.logger.WithError(err).Warn("Scheduled snapshot build failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// BuildAll runs one scheduled round over the configured symbols. With a Locker, the
+// round is skipped when another replica already claimed it.
+func (b *Builder) BuildAll(ctx context.Context) error {
+	now := b.now()
+
+	if b.options.Locker != nil {
+		round := now.Truncate(b.options.BuildInterval)
+		key := "snapshot-builder:" + strconv.FormatInt(round.Unix(), 10)
+		if _, err := b.options.Locker.Acquire(ctx, key, b.options.BuildInterval); err != nil {
+			if errors.Is(err, interfaces.ErrLockNotAcquired) {
+				return nil
+			}
+			return fmt.Errorf("failed to claim snapshot round: %w", err)
+		}
+	}
+
+	symbols, err := b.symbols(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, symbol := range symbols {
+		if _, err := b.Build(ctx, symbol); err != nil {
+			if errors.Is(err, interfaces.ErrNotFound) {
+				continue
+			}
+			failed++
+			b.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to build market snapshot")
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to build %d of %d market snapshots", failed, len(symbols))
+	}
+	return nil
+}
+
+func (b *Builder) symbols(ctx context.Context, now time.Time) ([]string, error) {
+	if len(b.options.Symbols) > 0 {
+		return b.options.Symbols, nil
+	}
+
+	latest, err := b.feeds.GetLatestTimestamps(ctx, now.Add(-b.options.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, entry := range latest {
+		if !seen[entry.Symbol] {
+			seen[entry.Symbol] = true
+			symbols = append(symbols, entry.Symbol)
+		}
+	}
+	return symbols, nil
+}
@@ -0,0 +1,152 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeeds struct {
+	interfaces.PriceFeedRepository
+	latest *models.PriceFeed
+}
+
+func (f *fakeFeeds) GetLatestBySymbol(ctx context.Context, symbol string) (*models.PriceFeed, error) {
+	return f.latest, nil
+}
+
+type fakeCandles struct {
+	interfaces.CandleRepository
+	candles []*models.Candle
+	queries []*models.CandleQuery
+}
+
+func (f *fakeCandles) Query(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	f.queries = append(f.queries, query)
+	return f.candles, nil
+}
+
+type fakeSnapshots struct {
+	interfaces.MarketSnapshotRepository
+	created []*models.MarketSnapshot
+}
+
+func (f *fakeSnapshots) Create(ctx context.Context, snapshot *models.MarketSnapshot) error {
+	f.created = append(f.created, snapshot)
+	return nil
+}
+
+// fakeLocker grants each key once
+type fakeLocker struct {
+	interfaces.Locker
+	held map[string]bool
+}
+
+func (f *fakeLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (*interfaces.Lock, error) {
+	if f.held[key] {
+		return nil, interfaces.ErrLockNotAcquired
+	}
+	f.held[key] = true
+	return &interfaces.Lock{Key: key}, nil
+}
+
+func dec(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+func candle(open, volume string) *models.Candle {
+	return &models.Candle{Symbol: "BTC-USD", Open: *dec(open), Volume: *dec(volume)}
+}
+
+func TestDerive(t *testing.T) {
+	feed := &models.PriceFeed{
+		FeedID: "feed-1", Symbol: "BTC-USD", Source: "coinbase",
+		Price: decimal.RequireFromString("110"), Bid: dec("109.5"), Ask: dec("110.5"),
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	snapshot := Derive(feed, []*models.Candle{candle("100", "5"), candle("104", "2.5")})
+
+	assert.True(t, decimal.NewFromInt(110).Equal(snapshot.LastPrice))
+	assert.True(t, decimal.NewFromInt(1).Equal(*snapshot.Spread))
+	assert.True(t, decimal.RequireFromString("7.5").Equal(*snapshot.Volume24h))
+	assert.True(t, decimal.NewFromInt(10).Equal(*snapshot.PriceChange24h))
+	assert.True(t, decimal.NewFromInt(10).Equal(*snapshot.PriceChangePercent24h))
+	assert.Equal(t, feed.Timestamp, snapshot.Timestamp)
+
+	// Open plus change is always the last price
+	assert.True(t, snapshot.LastPrice.Equal(decimal.NewFromInt(100).Add(*snapshot.PriceChange24h)))
+}
+
+func TestDerive_WithoutCandlesOrWithCrossedQuote(t *testing.T) {
+	feed := &models.PriceFeed{
+		Symbol: "BTC-USD", Price: decimal.RequireFromString("100"),
+		Bid: dec("101"), Ask: dec("99"), Volume24h: dec("42"),
+	}
+
+	snapshot := Derive(feed, nil)
+
+	assert.Nil(t, snapshot.Spread)
+	assert.Nil(t, snapshot.PriceChange24h)
+	assert.Nil(t, snapshot.PriceChangePercent24h)
+	assert.True(t, decimal.NewFromInt(42).Equal(*snapshot.Volume24h))
+}
+
+func newTestBuilder(options Options) (*Builder, *fakeFeeds, *fakeCandles, *fakeSnapshots) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	feeds := &fakeFeeds{}
+	candles := &fakeCandles{candles: []*models.Candle{candle("100", "1")}}
+	snapshots := &fakeSnapshots{}
+	return NewBuilder(feeds, candles, snapshots, options, logger), feeds, candles, snapshots
+}
+
+func TestBuilder_BuildSkipsFeedsAlreadySnapshotted(t *testing.T) {
+	builder, feeds, candles, snapshots := newTestBuilder(Options{})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	feeds.latest = &models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", Price: decimal.NewFromInt(101), Timestamp: now}
+
+	snapshot, err := builder.Build(context.Background(), "BTC-USD")
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, now.Add(-24*time.Hour), *candles.queries[0].StartTimeFrom)
+
+	snapshot, err = builder.Build(context.Background(), "BTC-USD")
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	older := &models.PriceFeed{FeedID: "feed-0", Symbol: "BTC-USD", Price: decimal.NewFromInt(99), Timestamp: now.Add(-time.Second)}
+	snapshot, err = builder.BuildFromFeed(context.Background(), older)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot, "Late feeds do not replace a newer snapshot")
+
+	assert.Len(t, snapshots.created, 1)
+}
+
+func TestBuilder_BuildAllClaimsEachRoundOnce(t *testing.T) {
+	locker := &fakeLocker{held: make(map[string]bool)}
+	builder, feeds, _, snapshots := newTestBuilder(Options{Symbols: []string{"BTC-USD"}, Locker: locker})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	builder.now = func() time.Time { return now }
+
+	feeds.latest = &models.PriceFeed{FeedID: "feed-1", Symbol: "BTC-USD", Price: decimal.NewFromInt(101), Timestamp: now}
+	require.NoError(t, builder.BuildAll(context.Background()))
+
+	// Another replica's view of the same round: the lock is taken, so nothing is built
+	feeds.latest = &models.PriceFeed{FeedID: "feed-2", Symbol: "BTC-USD", Price: decimal.NewFromInt(102), Timestamp: now}
+	require.NoError(t, builder.BuildAll(context.Background()))
+	assert.Len(t, snapshots.created, 1)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, builder.BuildAll(context.Background()))
+	assert.Len(t, snapshots.created, 2)
+}
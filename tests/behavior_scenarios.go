@@ -5,6 +5,7 @@ package tests
 import (
 	"time"
 
+	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/market-data-adapter-go/pkg/models"
 )
 
@@ -56,9 +57,8 @@ func (suite *BehaviorTestSuite) priceFeedLifecycleScenario() {
 			pf.Symbol = "BTC-USD"
 		})
 	}).When("the price feed is created", func() {
-		err = suite.adapter.PriceFeedRepository().Create(suite.ctx, priceFeed)
+		_, err = suite.adapter.PriceFeedRepository().Create(suite.ctx, priceFeed)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID)
 	}).Then("the price feed should be retrievable", func() {
 		retrievedFeed, getErr := suite.adapter.PriceFeedRepository().GetByID(suite.ctx, feedID)
 		suite.Require().NoError(getErr)
@@ -97,7 +97,6 @@ func (suite *BehaviorTestSuite) candleLifecycleScenario() {
 	}).When("the candle is created", func() {
 		err = suite.adapter.CandleRepository().Upsert(suite.ctx, candle)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID)
 	}).Then("the candle should be retrievable", func() {
 		retrievedCandle, getErr := suite.adapter.CandleRepository().GetByID(suite.ctx, candleID)
 		suite.Require().NoError(getErr)
@@ -143,7 +142,6 @@ func (suite *BehaviorTestSuite) marketSnapshotLifecycleScenario() {
 	}).When("the snapshot is created", func() {
 		err = suite.adapter.MarketSnapshotRepository().Create(suite.ctx, snapshot)
 		suite.Require().NoError(err)
-		suite.trackCreatedMarketSnapshot(snapshotID)
 	}).Then("the snapshot should be retrievable", func() {
 		retrievedSnapshot, getErr := suite.adapter.MarketSnapshotRepository().GetByID(suite.ctx, snapshotID)
 		suite.Require().NoError(getErr)
@@ -167,19 +165,19 @@ func (suite *BehaviorTestSuite) serviceDiscoveryLifecycleScenario() {
 	suite.Given("a service registration with healthy status", func() {
 		// Service defined below
 	}).When("the service is registered", func() {
-		service := suite.CreateTestServiceRegistration(serviceID, func(s *models.ServiceRegistration) {
-			s.Status = "healthy"
-			s.Name = "test-lifecycle-service"
+		service := suite.CreateTestServiceInfo(serviceID, func(s *interfaces.ServiceInfo) {
+			s.Health = interfaces.HealthPassing
+			s.ServiceName = "test-lifecycle-service"
 		})
-		err = suite.adapter.RegisterService(suite.ctx, service)
+		err = suite.adapter.ServiceDiscoveryRepository().Register(suite.ctx, service)
 		suite.Require().NoError(err)
 		suite.trackCreatedService(serviceID)
 	}).Then("the service should be discoverable", func() {
 		retrievedService, getErr := suite.adapter.ServiceDiscoveryRepository().GetServiceInfo(suite.ctx, serviceID)
 		suite.Require().NoError(getErr)
 		suite.Require().NotNil(retrievedService)
-		suite.Equal(serviceID, retrievedService.ID)
-		suite.Equal("healthy", retrievedService.Status)
+		suite.Equal(serviceID, retrievedService.ServiceID)
+		suite.Equal(interfaces.HealthPassing, retrievedService.Health)
 	}).And("the service should appear in service list by name", func() {
 		services, listErr := suite.adapter.ServiceDiscoveryRepository().Discover(suite.ctx, "test-lifecycle-service")
 		suite.Require().NoError(listErr)
@@ -187,7 +185,7 @@ func (suite *BehaviorTestSuite) serviceDiscoveryLifecycleScenario() {
 
 		found := false
 		for _, s := range services {
-			if s.ID == serviceID {
+			if s.ServiceID == serviceID {
 				found = true
 				break
 			}
@@ -211,11 +209,11 @@ func (suite *BehaviorTestSuite) cacheOperationsScenario() {
 	suite.Given("a cache key-value pair", func() {
 		// Key and value are defined above
 	}).When("the value is stored in cache with TTL", func() {
-		err := suite.adapter.CacheRepository().Set(suite.ctx, key, value, ttl)
+		err := suite.Set(suite.ctx, key, value, ttl)
 		suite.Require().NoError(err)
 	}).Then("the value should be retrievable from cache", func() {
 		var retrieved map[string]interface{}
-		err := suite.adapter.CacheRepository().Get(suite.ctx, key, &retrieved)
+		err := suite.Get(suite.ctx, key, &retrieved)
 		suite.Require().NoError(err)
 		suite.Equal(value["test_field"], retrieved["test_field"])
 		suite.Equal(float64(42), retrieved["numeric"]) // JSON unmarshaling converts numbers to float64
@@ -26,12 +26,10 @@ type BehaviorTestSuite struct {
 	config  *config.Config
 	logger  *logrus.Logger
 
-	// Track created resources for cleanup
-	createdSymbols          []string
-	createdPriceFeeds       []string
-	createdCandles          []string
-	createdMarketSnapshots  []string
-	createdServices         []string
+	// Track created resources for cleanup; price feeds, candles and snapshots are
+	// append-only time series that expire through retention, so tests use unique IDs
+	createdSymbols  []string
+	createdServices []string
 }
 
 // SetupSuite runs once before all tests in the suite
@@ -76,24 +74,6 @@ func (suite *BehaviorTestSuite) TearDownTest() {
 
 // cleanupCreatedResources removes all tracked test resources
 func (suite *BehaviorTestSuite) cleanupCreatedResources() {
-	// Cleanup market snapshots
-	for _, snapshotID := range suite.createdMarketSnapshots {
-		_ = suite.adapter.MarketSnapshotRepository().Delete(suite.ctx, snapshotID)
-	}
-	suite.createdMarketSnapshots = nil
-
-	// Cleanup candles
-	for _, candleID := range suite.createdCandles {
-		_ = suite.adapter.CandleRepository().Delete(suite.ctx, candleID)
-	}
-	suite.createdCandles = nil
-
-	// Cleanup price feeds
-	for _, feedID := range suite.createdPriceFeeds {
-		_ = suite.adapter.PriceFeedRepository().Delete(suite.ctx, feedID)
-	}
-	suite.createdPriceFeeds = nil
-
 	// Cleanup symbols
 	for _, symbolID := range suite.createdSymbols {
 		_ = suite.adapter.SymbolRepository().Delete(suite.ctx, symbolID)
@@ -112,18 +92,6 @@ func (suite *BehaviorTestSuite) trackCreatedSymbol(symbolID string) {
 	suite.createdSymbols = append(suite.createdSymbols, symbolID)
 }
 
-func (suite *BehaviorTestSuite) trackCreatedPriceFeed(feedID string) {
-	suite.createdPriceFeeds = append(suite.createdPriceFeeds, feedID)
-}
-
-func (suite *BehaviorTestSuite) trackCreatedCandle(candleID string) {
-	suite.createdCandles = append(suite.createdCandles, candleID)
-}
-
-func (suite *BehaviorTestSuite) trackCreatedMarketSnapshot(snapshotID string) {
-	suite.createdMarketSnapshots = append(suite.createdMarketSnapshots, snapshotID)
-}
-
 func (suite *BehaviorTestSuite) trackCreatedService(serviceID string) {
 	suite.createdServices = append(suite.createdServices, serviceID)
 }
@@ -283,6 +251,11 @@ func (scenario *BDDScenario) Then(description string, fn func()) *BDDScenario {
 	return scenario
 }
 
+// Then runs assertions nested in a step, where the results they check are in scope
+func (suite *BehaviorTestSuite) Then(description string, fn func()) {
+	fn()
+}
+
 // And adds an additional step to the current phase
 func (scenario *BDDScenario) And(description string, fn func()) *BDDScenario {
 	if len(scenario.thens) > 0 {
@@ -36,9 +36,8 @@ func (suite *CandleBehaviorTestSuite) TestCandleCRUDOperations() {
 			c.Close = decimal.NewFromFloat(50050.00)
 		})
 
-		err := suite.adapter.CandleRepository().Create(suite.ctx, candle)
+		err := suite.adapter.CandleRepository().Upsert(suite.ctx, candle)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID)
 	}).Then("the candle should be retrievable", func() {
 		retrieved, err := suite.adapter.CandleRepository().GetByID(suite.ctx, candleID)
 		suite.Require().NoError(err)
@@ -60,19 +59,17 @@ func (suite *CandleBehaviorTestSuite) TestCandleQueryBySymbolAndInterval() {
 			c.Symbol = "ETH-USD"
 			c.Interval = models.Interval1m
 		})
-		err := suite.adapter.CandleRepository().Create(suite.ctx, candle1)
+		err := suite.adapter.CandleRepository().Upsert(suite.ctx, candle1)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID1)
 
 		candle2 := suite.CreateTestCandle(candleID2, func(c *models.Candle) {
 			c.Symbol = "ETH-USD"
 			c.Interval = models.Interval5m
 		})
-		err = suite.adapter.CandleRepository().Create(suite.ctx, candle2)
+		err = suite.adapter.CandleRepository().Upsert(suite.ctx, candle2)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID2)
 	}).When("querying candles by symbol and 1m interval", func() {
-		candles, err := suite.adapter.CandleRepository().GetBySymbolAndInterval(suite.ctx, "ETH-USD", models.Interval1m)
+		candles, err := suite.adapter.CandleRepository().GetBySymbolAndInterval(suite.ctx, "ETH-USD", models.Interval1m, 100)
 		suite.Require().NoError(err)
 
 		suite.Then("only 1m interval candles should be returned", func() {
@@ -105,9 +102,8 @@ func (suite *CandleBehaviorTestSuite) TestCandleTimeSeriesQuery() {
 			c.StartTime = now.Add(-3 * time.Hour)
 			c.EndTime = now.Add(-2 * time.Hour)
 		})
-		err := suite.adapter.CandleRepository().Create(suite.ctx, candle1)
+		err := suite.adapter.CandleRepository().Upsert(suite.ctx, candle1)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID1)
 
 		// Recent candle
 		candle2 := suite.CreateTestCandle(candleID2, func(c *models.Candle) {
@@ -116,9 +112,8 @@ func (suite *CandleBehaviorTestSuite) TestCandleTimeSeriesQuery() {
 			c.StartTime = now.Add(-1 * time.Hour)
 			c.EndTime = now
 		})
-		err = suite.adapter.CandleRepository().Create(suite.ctx, candle2)
+		err = suite.adapter.CandleRepository().Upsert(suite.ctx, candle2)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID2)
 
 		// Future candle
 		candle3 := suite.CreateTestCandle(candleID3, func(c *models.Candle) {
@@ -127,20 +122,19 @@ func (suite *CandleBehaviorTestSuite) TestCandleTimeSeriesQuery() {
 			c.StartTime = now.Add(1 * time.Hour)
 			c.EndTime = now.Add(2 * time.Hour)
 		})
-		err = suite.adapter.CandleRepository().Create(suite.ctx, candle3)
+		err = suite.adapter.CandleRepository().Upsert(suite.ctx, candle3)
 		suite.Require().NoError(err)
-		suite.trackCreatedCandle(candleID3)
 	}).When("querying candles within a time range", func() {
 		startTime := now.Add(-2 * time.Hour)
 		endTime := now.Add(30 * time.Minute)
 
-		candles, err := suite.adapter.CandleRepository().GetByTimeRange(
-			suite.ctx,
-			"BTC-USD",
-			models.Interval1h,
-			startTime,
-			endTime,
-		)
+		interval := models.Interval1h
+		candles, err := suite.adapter.CandleRepository().Query(suite.ctx, &models.CandleQuery{
+			Symbol:        stringPtr("BTC-USD"),
+			Interval:      &interval,
+			StartTimeFrom: &startTime,
+			StartTimeTo:   &endTime,
+		})
 		suite.Require().NoError(err)
 
 		suite.Then("only candles within the time range should be returned", func() {
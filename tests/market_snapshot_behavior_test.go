@@ -4,6 +4,7 @@ package tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
@@ -35,7 +36,6 @@ func (suite *MarketSnapshotBehaviorTestSuite) TestMarketSnapshotCRUDOperations()
 
 		err := suite.adapter.MarketSnapshotRepository().Create(suite.ctx, snapshot)
 		suite.Require().NoError(err)
-		suite.trackCreatedMarketSnapshot(snapshotID)
 	}).Then("the market snapshot should be retrievable", func() {
 		retrieved, err := suite.adapter.MarketSnapshotRepository().GetByID(suite.ctx, snapshotID)
 		suite.Require().NoError(err)
@@ -59,7 +59,6 @@ func (suite *MarketSnapshotBehaviorTestSuite) TestLatestMarketSnapshot() {
 		snapshot1.Timestamp = snapshot1.Timestamp.Add(-1 * time.Hour)
 		err := suite.adapter.MarketSnapshotRepository().Create(suite.ctx, snapshot1)
 		suite.Require().NoError(err)
-		suite.trackCreatedMarketSnapshot(snapshotID1)
 
 		snapshot2 := suite.CreateTestMarketSnapshot(snapshotID2, func(ms *models.MarketSnapshot) {
 			ms.Symbol = "ETH-USD"
@@ -67,9 +66,8 @@ func (suite *MarketSnapshotBehaviorTestSuite) TestLatestMarketSnapshot() {
 		})
 		err = suite.adapter.MarketSnapshotRepository().Create(suite.ctx, snapshot2)
 		suite.Require().NoError(err)
-		suite.trackCreatedMarketSnapshot(snapshotID2)
 	}).When("querying for the latest snapshot", func() {
-		latest, err := suite.adapter.MarketSnapshotRepository().GetLatest(suite.ctx, "ETH-USD")
+		latest, err := suite.adapter.MarketSnapshotRepository().GetLatestBySymbol(suite.ctx, "ETH-USD")
 		suite.Require().NoError(err)
 
 		suite.Then("the most recent snapshot should be returned", func() {
@@ -93,16 +91,14 @@ func (suite *MarketSnapshotBehaviorTestSuite) TestMarketSnapshotQueryBySymbol()
 		})
 		err := suite.adapter.MarketSnapshotRepository().Create(suite.ctx, snapshot1)
 		suite.Require().NoError(err)
-		suite.trackCreatedMarketSnapshot(snapshotID1)
 
 		snapshot2 := suite.CreateTestMarketSnapshot(snapshotID2, func(ms *models.MarketSnapshot) {
 			ms.Symbol = "ADA-USD"
 		})
 		err = suite.adapter.MarketSnapshotRepository().Create(suite.ctx, snapshot2)
 		suite.Require().NoError(err)
-		suite.trackCreatedMarketSnapshot(snapshotID2)
 	}).When("querying snapshots for SOL-USD", func() {
-		snapshots, err := suite.adapter.MarketSnapshotRepository().GetBySymbol(suite.ctx, "SOL-USD")
+		snapshots, err := suite.adapter.MarketSnapshotRepository().GetBySymbol(suite.ctx, "SOL-USD", 100)
 		suite.Require().NoError(err)
 
 		suite.Then("only SOL-USD snapshots should be returned", func() {
@@ -4,6 +4,7 @@ package tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
@@ -34,9 +35,8 @@ func (suite *PriceFeedBehaviorTestSuite) TestPriceFeedCRUDOperations() {
 			pf.Source = "test-exchange"
 		})
 
-		err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed)
+		_, err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID)
 	}).Then("the price feed should be retrievable", func() {
 		retrieved, err := suite.adapter.PriceFeedRepository().GetByID(suite.ctx, feedID)
 		suite.Require().NoError(err)
@@ -59,20 +59,18 @@ func (suite *PriceFeedBehaviorTestSuite) TestPriceFeedQueryBySymbol() {
 			pf.Source = "exchange-A"
 			pf.Price = decimal.NewFromFloat(3000.00)
 		})
-		err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed1)
+		_, err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed1)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID1)
 
 		feed2 := suite.CreateTestPriceFeed(feedID2, func(pf *models.PriceFeed) {
 			pf.Symbol = "ETH-USD"
 			pf.Source = "exchange-B"
 			pf.Price = decimal.NewFromFloat(3001.00)
 		})
-		err = suite.adapter.PriceFeedRepository().Create(suite.ctx, feed2)
+		_, err = suite.adapter.PriceFeedRepository().Create(suite.ctx, feed2)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID2)
 	}).When("querying price feeds by symbol", func() {
-		feeds, err := suite.adapter.PriceFeedRepository().GetBySymbol(suite.ctx, "ETH-USD")
+		feeds, err := suite.adapter.PriceFeedRepository().GetBySymbol(suite.ctx, "ETH-USD", 100)
 		suite.Require().NoError(err)
 
 		suite.Then("all feeds for the symbol should be returned", func() {
@@ -102,20 +100,18 @@ func (suite *PriceFeedBehaviorTestSuite) TestLatestPriceFeed() {
 			pf.Symbol = "SOL-USD"
 			pf.Price = decimal.NewFromFloat(100.00)
 		})
-		feed1.Timestamp = feed1.Timestamp.Add(-1 * suite.Hour)
-		err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed1)
+		feed1.Timestamp = feed1.Timestamp.Add(-1 * time.Hour)
+		_, err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed1)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID1)
 
 		feed2 := suite.CreateTestPriceFeed(feedID2, func(pf *models.PriceFeed) {
 			pf.Symbol = "SOL-USD"
 			pf.Price = decimal.NewFromFloat(105.00)
 		})
-		err = suite.adapter.PriceFeedRepository().Create(suite.ctx, feed2)
+		_, err = suite.adapter.PriceFeedRepository().Create(suite.ctx, feed2)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID2)
 	}).When("querying for the latest price feed", func() {
-		latest, err := suite.adapter.PriceFeedRepository().GetLatest(suite.ctx, "SOL-USD")
+		latest, err := suite.adapter.PriceFeedRepository().GetLatestBySymbol(suite.ctx, "SOL-USD")
 		suite.Require().NoError(err)
 
 		suite.Then("the most recent feed should be returned", func() {
@@ -135,11 +131,10 @@ func (suite *PriceFeedBehaviorTestSuite) TestPriceFeedQueryBySource() {
 			pf.Source = "coinbase"
 			pf.Symbol = "ADA-USD"
 		})
-		err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed)
+		_, err := suite.adapter.PriceFeedRepository().Create(suite.ctx, feed)
 		suite.Require().NoError(err)
-		suite.trackCreatedPriceFeed(feedID)
 	}).When("querying price feeds by source", func() {
-		feeds, err := suite.adapter.PriceFeedRepository().GetBySource(suite.ctx, "coinbase")
+		feeds, err := suite.adapter.PriceFeedRepository().Query(suite.ctx, &models.PriceFeedQuery{Source: stringPtr("coinbase")})
 		suite.Require().NoError(err)
 
 		suite.Then("feeds from that source should be found", func() {
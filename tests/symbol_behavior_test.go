@@ -44,9 +44,7 @@ func (suite *SymbolBehaviorTestSuite) TestSymbolCRUDOperations() {
 		suite.Equal("ETH-USD", retrieved.Symbol)
 		suite.True(retrieved.IsActive)
 	}).And("the symbol can be updated", func() {
-		err := suite.adapter.SymbolRepository().Update(suite.ctx, symbolID, func(s *models.Symbol) {
-			s.IsActive = false
-		})
+		err := suite.adapter.SymbolRepository().UpdateActiveStatus(suite.ctx, symbolID, false)
 		suite.Require().NoError(err)
 
 		updated, err := suite.adapter.SymbolRepository().GetByID(suite.ctx, symbolID)
@@ -127,7 +125,7 @@ func (suite *SymbolBehaviorTestSuite) TestSymbolQueryByBaseCurrency() {
 		suite.Require().NoError(err)
 		suite.trackCreatedSymbol(symbolID2)
 	}).When("querying symbols by base currency BTC", func() {
-		symbols, err := suite.adapter.SymbolRepository().GetByBaseCurrency(suite.ctx, "BTC")
+		symbols, err := suite.adapter.SymbolRepository().Query(suite.ctx, &models.SymbolQuery{BaseCurrency: stringPtr("BTC")})
 		suite.Require().NoError(err)
 
 		suite.Then("all BTC pairs should be returned", func() {